@@ -1,9 +1,66 @@
 package config
 
+import "time"
+
 type Config struct {
 	DBUsername string `envconfig:"DB_USER" default:"Raihan"`
 	DBPassword string `envconfig:"DB_PASS" default:"Pastibisa"`
 	DBHost     string `envconfig:"DB_HOST" default:"localhost"`
 	DBPort     int    `envconfig:"DB_PORT" default:"3306"`
 	DBName     string `envconfig:"DB_NAME" default:"Gin_todo"`
+
+	// JWTSecret has no default: a well-known key would let anyone forge tokens.
+	JWTSecret    string        `envconfig:"JWT_SECRET"`
+	JWTExpiresIn time.Duration `envconfig:"JWT_EXPIRES_IN" default:"24h"`
+
+	PurgeAfterDays int           `envconfig:"PURGE_AFTER_DAYS" default:"30"`
+	PurgeInterval  time.Duration `envconfig:"PURGE_INTERVAL" default:"1h"`
+
+	// ReminderNotifier is one of log, smtp or webhook.
+	ReminderNotifier   string        `envconfig:"REMINDER_NOTIFIER" default:"log"`
+	ReminderLead       time.Duration `envconfig:"REMINDER_LEAD" default:"1h"`
+	ReminderInterval   time.Duration `envconfig:"REMINDER_INTERVAL" default:"1m"`
+	ReminderTimeout    time.Duration `envconfig:"REMINDER_TIMEOUT" default:"10s"`
+	ReminderWebhookURL string        `envconfig:"REMINDER_WEBHOOK_URL"`
+	ReminderBackoff    time.Duration `envconfig:"REMINDER_BACKOFF" default:"1m"`
+	ReminderMaxBackoff time.Duration `envconfig:"REMINDER_MAX_BACKOFF" default:"1h"`
+
+	SMTPAddr     string `envconfig:"SMTP_ADDR" default:"localhost:25"`
+	SMTPUsername string `envconfig:"SMTP_USER"`
+	SMTPPassword string `envconfig:"SMTP_PASS"`
+	SMTPFrom     string `envconfig:"SMTP_FROM" default:"todo@localhost"`
+
+	WebhookTimeout     time.Duration `envconfig:"WEBHOOK_TIMEOUT" default:"10s"`
+	WebhookInterval    time.Duration `envconfig:"WEBHOOK_INTERVAL" default:"5s"`
+	WebhookMaxAttempts int           `envconfig:"WEBHOOK_MAX_ATTEMPTS" default:"8"`
+	WebhookBackoff     time.Duration `envconfig:"WEBHOOK_BACKOFF" default:"30s"`
+	WebhookMaxBackoff  time.Duration `envconfig:"WEBHOOK_MAX_BACKOFF" default:"1h"`
+
+	// OutboxSink is one of none, stdout, file or http; webhooks always get
+	// the events too.
+	OutboxSink        string        `envconfig:"OUTBOX_SINK" default:"none"`
+	OutboxFile        string        `envconfig:"OUTBOX_FILE" default:"outbox.jsonl"`
+	OutboxURL         string        `envconfig:"OUTBOX_URL"`
+	OutboxInterval    time.Duration `envconfig:"OUTBOX_INTERVAL" default:"1s"`
+	OutboxTimeout     time.Duration `envconfig:"OUTBOX_TIMEOUT" default:"10s"`
+	OutboxMaxAttempts int           `envconfig:"OUTBOX_MAX_ATTEMPTS" default:"20"`
+	OutboxBackoff     time.Duration `envconfig:"OUTBOX_BACKOFF" default:"1s"`
+	OutboxMaxBackoff  time.Duration `envconfig:"OUTBOX_MAX_BACKOFF" default:"5m"`
+	OutboxRetention   time.Duration `envconfig:"OUTBOX_RETENTION" default:"168h"`
+
+	StreamBuffer    int           `envconfig:"STREAM_BUFFER" default:"1024"`
+	StreamHeartbeat time.Duration `envconfig:"STREAM_HEARTBEAT" default:"15s"`
+
+	// WSAllowedOrigins lists the browser origins allowed to open /ws besides
+	// the API's own.
+	WSAllowedOrigins []string `envconfig:"WS_ALLOWED_ORIGINS"`
+
+	// UndoWindow is how long after a change POST /undo can still revert it.
+	UndoWindow time.Duration `envconfig:"UNDO_WINDOW" default:"5m"`
+
+	// AttachmentDir is where the local blob store keeps attachment contents.
+	AttachmentDir     string `envconfig:"ATTACHMENT_DIR" default:"attachments"`
+	AttachmentMaxSize int64  `envconfig:"ATTACHMENT_MAX_SIZE" default:"10485760"`
+
+	ShutdownTimeout time.Duration `envconfig:"SHUTDOWN_TIMEOUT" default:"10s"`
 }
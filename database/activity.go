@@ -0,0 +1,119 @@
+package database
+
+import (
+	"context"
+	"gorm.io/gorm"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+type ActivityRepository struct {
+	DB *gorm.DB
+}
+
+func NewActivityRepository(dbClient *gorm.DB) repository.ActivityRepository {
+	return &ActivityRepository{
+		DB: dbClient,
+	}
+}
+
+// todoField is a field of a todo the activity log keeps the values of.
+type todoField struct {
+	name  string
+	value func(todo *entity.Todolist) interface{}
+}
+
+var trackedFields = []todoField{
+	{"title", func(todo *entity.Todolist) interface{} { return todo.Title }},
+	{"description", func(todo *entity.Todolist) interface{} { return todo.Description }},
+	{"status", func(todo *entity.Todolist) interface{} { return todo.Status }},
+	{"completed_at", func(todo *entity.Todolist) interface{} { return todo.CompletedAt }},
+	{"due_date", func(todo *entity.Todolist) interface{} { return todo.DueDate }},
+	{"priority", func(todo *entity.Todolist) interface{} { return todo.Priority }},
+	{"recurrence", func(todo *entity.Todolist) interface{} { return todo.Recurrence }},
+	{"list_id", func(todo *entity.Todolist) interface{} { return todo.ListID }},
+	{"position", func(todo *entity.Todolist) interface{} { return todo.Position }},
+}
+
+// todoChanges returns the tracked fields that differ between two states of a
+// todo; against an empty todo it gives the fields a new one was created with.
+func todoChanges(before, after *entity.Todolist) entity.Changes {
+	changes := make(entity.Changes)
+	for _, field := range trackedFields {
+		from, to := field.value(before), field.value(after)
+		if sameValue(from, to) {
+			continue
+		}
+		changes[field.name] = entity.FieldChange{From: plainValue(from), To: plainValue(to)}
+	}
+	return changes
+}
+
+// batchKey is the context key of the batch ID activities are recorded under.
+type batchKey struct{}
+
+// inBatch gives db a new batch ID unless it already carries one. Every write
+// starts its transaction from it, so the activity of a request, including
+// that of the writes it nests like a bulk one does, shares a batch.
+func inBatch(db *gorm.DB) *gorm.DB {
+	ctx := db.Statement.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	if _, ok := ctx.Value(batchKey{}).(string); ok {
+		return db
+	}
+	id, err := newEventID()
+	if err != nil {
+		// recordActivity then gives each entry a batch of its own
+		return db
+	}
+	return db.WithContext(context.WithValue(ctx, batchKey{}, id))
+}
+
+// recordActivity appends to the activity log inside tx, so that the entry is
+// kept exactly when the change it describes commits.
+func recordActivity(tx *gorm.DB, userID int64, todo *entity.Todolist, action string, changes entity.Changes) error {
+	batchID, ok := tx.Statement.Context.Value(batchKey{}).(string)
+	if !ok {
+		id, err := newEventID()
+		if err != nil {
+			return err
+		}
+		batchID = id
+	}
+	return tx.Create(&entity.Activity{
+		UserID:   userID,
+		TodoID:   todo.ID,
+		Revision: todo.Version,
+		BatchID:  batchID,
+		Action:   action,
+		Changes:  changes,
+	}).Error
+}
+
+// History returns a page of the activity of one of the user's todos, newest
+// first, with the total number of entries.
+func (a ActivityRepository) History(userID, todoID int64, limit, offset int) ([]entity.Activity, int64, error) {
+	return a.page(func(db *gorm.DB) *gorm.DB {
+		return db.Where("user_id = ? AND todo_id = ?", userID, todoID)
+	}, limit, offset)
+}
+
+// Feed returns a page of the activity across all of the user's todos, newest
+// first, with the total number of entries.
+func (a ActivityRepository) Feed(userID int64, limit, offset int) ([]entity.Activity, int64, error) {
+	return a.page(func(db *gorm.DB) *gorm.DB {
+		return db.Where("user_id = ?", userID)
+	}, limit, offset)
+}
+
+func (a ActivityRepository) page(filter func(db *gorm.DB) *gorm.DB, limit, offset int) ([]entity.Activity, int64, error) {
+	var activities []entity.Activity
+	var total int64
+	if err := a.DB.Model(&entity.Activity{}).Scopes(filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+	result := a.DB.Scopes(filter).Order("id DESC").Limit(limit).Offset(offset).Find(&activities)
+	return activities, total, result.Error
+}
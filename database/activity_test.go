@@ -0,0 +1,124 @@
+package database
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+	"todoGin/model/entity"
+)
+
+func TestTodoChanges(t *testing.T) {
+	due := time.Date(2023, 6, 26, 9, 0, 0, 0, time.UTC)
+	description := "ship it"
+	listID := int64(3)
+	todo := entity.Todolist{ID: 1, Title: "deploy", Priority: entity.PriorityMedium, Position: 1024, Version: 1}
+
+	testCases := []struct {
+		name     string
+		before   entity.Todolist
+		after    func(todo entity.Todolist) entity.Todolist
+		expected entity.Changes
+	}{
+		{
+			name:   "Created",
+			before: entity.Todolist{},
+			after:  func(todo entity.Todolist) entity.Todolist { return todo },
+			expected: entity.Changes{
+				"title":    {From: "", To: "deploy"},
+				"priority": {From: "", To: entity.PriorityMedium},
+				"position": {From: 0.0, To: 1024.0},
+			},
+		},
+		{
+			name:   "Pointers are dereferenced",
+			before: todo,
+			after: func(todo entity.Todolist) entity.Todolist {
+				todo.Description, todo.DueDate, todo.ListID = &description, &due, &listID
+				return todo
+			},
+			expected: entity.Changes{
+				"description": {From: nil, To: description},
+				"due_date":    {From: nil, To: due},
+				"list_id":     {From: nil, To: listID},
+			},
+		},
+		{
+			name:   "Untracked fields are left out",
+			before: todo,
+			after: func(todo entity.Todolist) entity.Todolist {
+				todo.Version, todo.RemindedAt, todo.UpdatedAt = 2, &due, due
+				return todo
+			},
+			expected: entity.Changes{},
+		},
+		{
+			name:   "Same due date at another precision",
+			before: entity.Todolist{DueDate: &due},
+			after: func(todo entity.Todolist) entity.Todolist {
+				later := due.Add(time.Microsecond)
+				return entity.Todolist{DueDate: &later}
+			},
+			expected: entity.Changes{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			after := tc.after(todo)
+			assert.Equal(t, tc.expected, todoChanges(&tc.before, &after))
+		})
+	}
+}
+
+func TestSubtaskChanges(t *testing.T) {
+	subtask := entity.Subtask{ID: 5, Title: "write tests"}
+	done := subtask
+	done.Status = true
+
+	assert.Equal(t, entity.Changes{
+		"subtask.id":     {From: nil, To: int64(5)},
+		"subtask.title":  {From: nil, To: "write tests"},
+		"subtask.status": {From: nil, To: false},
+	}, subtaskChanges(nil, &subtask))
+	assert.Equal(t, entity.Changes{
+		"subtask.id":     {From: int64(5), To: int64(5)},
+		"subtask.status": {From: false, To: true},
+	}, subtaskChanges(&subtask, &done))
+	assert.Equal(t, entity.Changes{
+		"subtask.id":     {From: int64(5), To: nil},
+		"subtask.title":  {From: "write tests", To: nil},
+		"subtask.status": {From: true, To: nil},
+	}, subtaskChanges(&done, nil))
+}
+
+func TestCommentChanges(t *testing.T) {
+	comment := entity.Comment{ID: 4, Body: "**Blocked**"}
+	edited := comment
+	edited.Body = "Unblocked"
+
+	assert.Equal(t, entity.Changes{
+		"comment.id":   {From: nil, To: int64(4)},
+		"comment.body": {From: nil, To: "**Blocked**"},
+	}, commentChanges(nil, &comment))
+	assert.Equal(t, entity.Changes{
+		"comment.id":   {From: int64(4), To: int64(4)},
+		"comment.body": {From: "**Blocked**", To: "Unblocked"},
+	}, commentChanges(&comment, &edited))
+	assert.Equal(t, entity.Changes{
+		"comment.id":   {From: int64(4), To: nil},
+		"comment.body": {From: "Unblocked", To: nil},
+	}, commentChanges(&edited, nil))
+}
+
+func TestAttachmentChanges(t *testing.T) {
+	attachment := entity.Attachment{ID: 7, Filename: "invoice.pdf"}
+
+	assert.Equal(t, entity.Changes{
+		"attachment.id":       {From: nil, To: int64(7)},
+		"attachment.filename": {From: nil, To: "invoice.pdf"},
+	}, attachmentChanges(nil, &attachment))
+	assert.Equal(t, entity.Changes{
+		"attachment.id":       {From: int64(7), To: nil},
+		"attachment.filename": {From: "invoice.pdf", To: nil},
+	}, attachmentChanges(&attachment, nil))
+}
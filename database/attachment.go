@@ -0,0 +1,127 @@
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+type AttachmentRepository struct {
+	DB *gorm.DB
+}
+
+func NewAttachmentRepository(dbClient *gorm.DB) repository.AttachmentRepository {
+	return &AttachmentRepository{
+		DB: dbClient,
+	}
+}
+
+func (a AttachmentRepository) GetAll(userID, todoID int64) ([]entity.Attachment, error) {
+	var todo entity.Todolist
+	err := a.DB.Select("id").Where("id = ? AND user_id = ?", todoID, userID).First(&todo).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	attachments := []entity.Attachment{}
+	result := a.DB.Where("todo_id = ?", todoID).Order("id").Find(&attachments)
+	return attachments, result.Error
+}
+
+func (a AttachmentRepository) GetByID(userID, todoID, attachmentID int64) (*entity.Attachment, error) {
+	var attachment entity.Attachment
+	err := a.DB.Model(&entity.Attachment{}).
+		Select("attachments.*").
+		Joins("JOIN todolists ON todolists.id = attachments.todo_id AND todolists.deleted_at IS NULL").
+		Where("attachments.id = ? AND attachments.todo_id = ? AND todolists.user_id = ?", attachmentID, todoID, userID).
+		First(&attachment).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// Create records an attachment whose content is already stored, provided
+// the todo is the user's.
+func (a AttachmentRepository) Create(attachment *entity.Attachment) (*entity.Attachment, error) {
+	err := inBatch(a.DB).Transaction(func(tx *gorm.DB) error {
+		todo, err := commentTodo(tx, attachment.UserID, attachment.TodoID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(attachment).Error; err != nil {
+			return err
+		}
+		return recordActivity(tx, attachment.UserID, todo, entity.ActivityAttached, attachmentChanges(nil, attachment))
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return attachment, nil
+}
+
+func (a AttachmentRepository) Delete(userID, todoID, attachmentID int64) (*entity.Attachment, error) {
+	var attachment entity.Attachment
+	err := inBatch(a.DB).Transaction(func(tx *gorm.DB) error {
+		todo, err := commentTodo(tx, userID, todoID)
+		if err != nil {
+			return err
+		}
+		err = tx.Where("id = ? AND todo_id = ?", attachmentID, todoID).First(&attachment).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.Delete(&attachment).Error; err != nil {
+			return err
+		}
+		return recordActivity(tx, userID, todo, entity.ActivityDetached, attachmentChanges(&attachment, nil))
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &attachment, nil
+}
+
+// Orphans returns up to limit attachments whose todo has been purged.
+func (a AttachmentRepository) Orphans(limit int) ([]entity.Attachment, error) {
+	var attachments []entity.Attachment
+	result := a.DB.Model(&entity.Attachment{}).
+		Select("attachments.*").
+		Joins("LEFT JOIN todolists ON todolists.id = attachments.todo_id").
+		Where("todolists.id IS NULL").
+		Order("attachments.id").
+		Limit(limit).
+		Find(&attachments)
+	return attachments, result.Error
+}
+
+// Forget removes the record of an attachment whose content is gone.
+func (a AttachmentRepository) Forget(attachmentID int64) error {
+	return a.DB.Delete(&entity.Attachment{}, attachmentID).Error
+}
+
+// attachmentChanges is the activity of an attachment being added, when
+// before is nil, or removed, when after is.
+func attachmentChanges(before, after *entity.Attachment) entity.Changes {
+	id := entity.FieldChange{}
+	filename := entity.FieldChange{}
+	if before != nil {
+		id.From, filename.From = before.ID, before.Filename
+	}
+	if after != nil {
+		id.To, filename.To = after.ID, after.Filename
+	}
+	return entity.Changes{"attachment.id": id, "attachment.filename": filename}
+}
@@ -0,0 +1,80 @@
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"todoGin/repository"
+)
+
+// errBulkAborted unwinds the transaction of an all-or-nothing batch.
+var errBulkAborted = errors.New("bulk aborted")
+
+// Bulk runs the operations in order. When atomic, they share one transaction
+// and the first failure rolls the whole batch back, the other operations then
+// reporting ErrBulkRolledBack or ErrBulkSkipped. Otherwise each operation
+// commits in a transaction of its own, so a failure leaves the others as they
+// are. Either way the operations log their activity under one batch, which
+// POST /undo reverts at once.
+func (t TodoRepository) Bulk(userID int64, ops []repository.BulkOperation, atomic bool) ([]repository.BulkResult, error) {
+	results := make([]repository.BulkResult, len(ops))
+	batched := inBatch(t.DB)
+	if !atomic {
+		batch := TodoRepository{DB: batched}
+		for i, op := range ops {
+			results[i] = batch.apply(userID, op)
+		}
+		return results, nil
+	}
+
+	failed := -1
+	err := batched.Transaction(func(tx *gorm.DB) error {
+		inTx := TodoRepository{DB: tx}
+		for i, op := range ops {
+			results[i] = inTx.apply(userID, op)
+			if results[i].Err != nil {
+				failed = i
+				return errBulkAborted
+			}
+		}
+		return nil
+	})
+	if failed >= 0 {
+		for i := range results {
+			if i < failed {
+				results[i] = repository.BulkResult{Err: repository.ErrBulkRolledBack}
+			} else if i > failed {
+				results[i] = repository.BulkResult{Err: repository.ErrBulkSkipped}
+			}
+		}
+		return results, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return results, nil
+}
+
+func (t TodoRepository) apply(userID int64, op repository.BulkOperation) repository.BulkResult {
+	switch op.Op {
+	case repository.BulkCreate:
+		op.Todo.UserID = userID
+		todo, err := t.Create(op.Todo)
+		if err != nil {
+			return repository.BulkResult{Err: err}
+		}
+		return repository.BulkResult{Todo: todo, RowsAffected: 1}
+	case repository.BulkUpdate:
+		todo, rowsAffected, err := t.Update(userID, op.TodoID, repository.UpdateOptions{Version: op.Version}, op.Updates)
+		if err == nil && todo == nil {
+			err = repository.ErrTodoNotFound
+		}
+		return repository.BulkResult{Todo: todo, RowsAffected: rowsAffected, Err: err}
+	case repository.BulkDelete:
+		rowsAffected, err := t.Delete(userID, op.TodoID, op.Version)
+		if err == nil && rowsAffected == 0 {
+			err = repository.ErrTodoNotFound
+		}
+		return repository.BulkResult{RowsAffected: rowsAffected, Err: err}
+	}
+	return repository.BulkResult{Err: errors.New("unknown bulk operation " + op.Op)}
+}
@@ -0,0 +1,55 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"reflect"
+	"time"
+)
+
+// changedColumns returns the subset of updates whose value differs from what
+// model already holds. Columns unknown to the schema are always kept so the
+// database gets to reject them.
+func changedColumns(tx *gorm.DB, model interface{}, updates map[string]interface{}) (map[string]interface{}, error) {
+	stmt := &gorm.Statement{DB: tx}
+	if err := stmt.Parse(model); err != nil {
+		return nil, err
+	}
+	value := reflect.Indirect(reflect.ValueOf(model))
+
+	changes := make(map[string]interface{}, len(updates))
+	for column, next := range updates {
+		if field := stmt.Schema.LookUpField(column); field != nil {
+			current, _ := field.ValueOf(tx.Statement.Context, value)
+			if sameValue(current, next) {
+				continue
+			}
+		}
+		changes[column] = next
+	}
+	return changes, nil
+}
+
+// sameValue compares a column's current value with an update value, treating
+// nil pointers as NULL and times at the millisecond precision MySQL stores.
+func sameValue(current, next interface{}) bool {
+	current, next = plainValue(current), plainValue(next)
+	if current == nil || next == nil {
+		return current == nil && next == nil
+	}
+	if c, ok := current.(time.Time); ok {
+		n, ok := next.(time.Time)
+		return ok && c.Truncate(time.Millisecond).Equal(n.Truncate(time.Millisecond))
+	}
+	return reflect.DeepEqual(current, next)
+}
+
+// plainValue dereferences a pointer value, turning a nil one into nil.
+func plainValue(v interface{}) interface{} {
+	if rv := reflect.ValueOf(v); rv.Kind() == reflect.Ptr {
+		if rv.IsNil() {
+			return nil
+		}
+		return rv.Elem().Interface()
+	}
+	return v
+}
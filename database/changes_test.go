@@ -0,0 +1,36 @@
+package database
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+	"time"
+)
+
+func TestSameValue(t *testing.T) {
+	title := "Deploy"
+	due := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name     string
+		current  interface{}
+		next     interface{}
+		expected bool
+	}{
+		{name: "Equal strings", current: "Deploy", next: "Deploy", expected: true},
+		{name: "Different strings", current: "Deploy", next: "Release", expected: false},
+		{name: "Pointer and value", current: &title, next: "Deploy", expected: true},
+		{name: "Nil pointer and nil", current: (*string)(nil), next: nil, expected: true},
+		{name: "Nil pointer and value", current: (*string)(nil), next: "Deploy", expected: false},
+		{name: "Value and nil", current: &title, next: nil, expected: false},
+		{name: "Bools", current: false, next: true, expected: false},
+		{name: "Times within a millisecond", current: &due, next: due.Add(300 * time.Microsecond), expected: true},
+		{name: "Times in other zones", current: &due, next: due.In(time.FixedZone("WIB", 7*3600)), expected: true},
+		{name: "Different times", current: &due, next: due.Add(time.Hour), expected: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, sameValue(tc.current, tc.next))
+		})
+	}
+}
@@ -0,0 +1,162 @@
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+type CommentRepository struct {
+	DB *gorm.DB
+}
+
+func NewCommentRepository(dbClient *gorm.DB) repository.CommentRepository {
+	return &CommentRepository{
+		DB: dbClient,
+	}
+}
+
+// withAuthor selects comments along with their author's username.
+func withAuthor(db *gorm.DB) *gorm.DB {
+	return db.Model(&entity.Comment{}).
+		Select("comments.*, users.username AS author").
+		Joins("JOIN users ON users.id = comments.user_id")
+}
+
+func (c CommentRepository) GetAll(userID, todoID int64) ([]entity.Comment, error) {
+	var todo entity.Todolist
+	err := c.DB.Select("id").Where("id = ? AND user_id = ?", todoID, userID).First(&todo).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	comments := []entity.Comment{}
+	result := c.DB.Scopes(withAuthor).
+		Where("comments.todo_id = ?", todoID).
+		Order("comments.id").
+		Find(&comments)
+	return comments, result.Error
+}
+
+func (c CommentRepository) Create(userID, todoID int64, body string) (*entity.Comment, error) {
+	comment := entity.Comment{TodoID: todoID, UserID: userID, Body: body}
+	err := inBatch(c.DB).Transaction(func(tx *gorm.DB) error {
+		todo, err := commentTodo(tx, userID, todoID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&comment).Error; err != nil {
+			return err
+		}
+		if err := tx.Scopes(withAuthor).First(&comment, comment.ID).Error; err != nil {
+			return err
+		}
+		return recordActivity(tx, userID, todo, entity.ActivityCommented, commentChanges(nil, &comment))
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Update replaces the body of a comment the user wrote.
+func (c CommentRepository) Update(userID, todoID, commentID int64, body string) (*entity.Comment, error) {
+	var comment entity.Comment
+	err := inBatch(c.DB).Transaction(func(tx *gorm.DB) error {
+		todo, err := commentTodo(tx, userID, todoID)
+		if err != nil {
+			return err
+		}
+		err = tx.Scopes(withAuthor).
+			Where("comments.id = ? AND comments.todo_id = ? AND comments.user_id = ?", commentID, todoID, userID).
+			First(&comment).Error
+		if err != nil {
+			return err
+		}
+		if comment.Body == body {
+			return nil
+		}
+		before := comment
+		if err := tx.Model(&comment).Update("body", body).Error; err != nil {
+			return err
+		}
+		if err := tx.Scopes(withAuthor).First(&comment, comment.ID).Error; err != nil {
+			return err
+		}
+		return recordActivity(tx, userID, todo, entity.ActivityCommentEdited, commentChanges(&before, &comment))
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &comment, nil
+}
+
+// Delete removes a comment the user wrote.
+func (c CommentRepository) Delete(userID, todoID, commentID int64) (int64, error) {
+	var rowsAffected int64
+	err := inBatch(c.DB).Transaction(func(tx *gorm.DB) error {
+		todo, err := commentTodo(tx, userID, todoID)
+		if err != nil {
+			return err
+		}
+		var comment entity.Comment
+		err = tx.Where("id = ? AND todo_id = ? AND user_id = ?", commentID, todoID, userID).First(&comment).Error
+		if err != nil {
+			return err
+		}
+		result := tx.Delete(&comment)
+		if result.Error != nil {
+			return result.Error
+		}
+		rowsAffected = result.RowsAffected
+		return recordActivity(tx, userID, todo, entity.ActivityCommentDeleted, commentChanges(&comment, nil))
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return rowsAffected, err
+}
+
+// commentTodo locks the caller's todo and moves its updated_at, so that
+// cached listings showing its comment count go stale, but leaves its version
+// alone: a comment does not conflict with edits to the todo, and neither
+// does an attachment, which goes through here too. It fails with
+// gorm.ErrRecordNotFound when the todo is missing or not the caller's.
+func commentTodo(tx *gorm.DB, userID, todoID int64) (*entity.Todolist, error) {
+	var todo entity.Todolist
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ?", todoID, userID).
+		First(&todo).Error
+	if err != nil {
+		return nil, err
+	}
+	if err := tx.Model(&todo).UpdateColumn("updated_at", time.Now()).Error; err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// commentChanges is the activity of a comment going from before to after,
+// where nil stands for a comment being added or removed.
+func commentChanges(before, after *entity.Comment) entity.Changes {
+	id := entity.FieldChange{}
+	body := entity.FieldChange{}
+	if before != nil {
+		id.From, body.From = before.ID, before.Body
+	}
+	if after != nil {
+		id.To, body.To = after.ID, after.Body
+	}
+	return entity.Changes{"comment.id": id, "comment.body": body}
+}
@@ -2,7 +2,9 @@ package database
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	mysqlDriver "github.com/go-sql-driver/mysql"
 	"github.com/golang-migrate/migrate/v4"
 	mysqlMigration "github.com/golang-migrate/migrate/v4/database/mysql"
 	_ "github.com/golang-migrate/migrate/v4/source/file"
@@ -18,9 +20,8 @@ import (
 
 func DatabaseInit(ctx context.Context, cfg *config.Config) (*gorm.DB, error) {
 
-	fmt.Printf("%+v\n", cfg)
-	//
-	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s",
+	// migrations share this connection and some run several statements
+	dsn := fmt.Sprintf("%s:%s@tcp(%s:%d)/%s?parseTime=true&multiStatements=true",
 		cfg.DBUsername,
 		cfg.DBPassword,
 		cfg.DBHost,
@@ -100,3 +101,13 @@ func Migrate(db *gorm.DB) error {
 
 	return err
 }
+
+// erDupEntry is the MySQL error number of a unique key violation.
+const erDupEntry = 1062
+
+// isDuplicateKey reports whether err is MySQL refusing a row because it
+// would break a unique key.
+func isDuplicateKey(err error) bool {
+	var mysqlErr *mysqlDriver.MySQLError
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == erDupEntry
+}
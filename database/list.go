@@ -0,0 +1,124 @@
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+type ListRepository struct {
+	DB *gorm.DB
+}
+
+func NewListRepository(dbClient *gorm.DB) repository.ListRepository {
+	return &ListRepository{
+		DB: dbClient,
+	}
+}
+
+func (l ListRepository) GetAll(userID int64, archived bool) ([]entity.List, error) {
+	var lists []entity.List
+	result := l.DB.Where("user_id = ? AND archived = ?", userID, archived).
+		Order("name").
+		Order("id").
+		Find(&lists)
+	return lists, result.Error
+}
+
+func (l ListRepository) GetByID(userID, listID int64) (*entity.List, error) {
+	var list entity.List
+	result := l.DB.Where("id = ? AND user_id = ?", listID, userID).First(&list)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &list, result.Error
+}
+
+func (l ListRepository) Create(list *entity.List) (*entity.List, error) {
+	result := l.DB.Create(list)
+	return list, result.Error
+}
+
+// Update renames or (un)archives the list and returns it as stored. A missing
+// list yields a nil list.
+func (l ListRepository) Update(userID, listID int64, updates map[string]interface{}) (*entity.List, error) {
+	var list entity.List
+	err := l.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND user_id = ?", listID, userID).First(&list).Error; err != nil {
+			return err
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		if err := tx.Model(&list).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.First(&list, list.ID).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &list, nil
+}
+
+// Delete removes the list after detaching its todos, trashed ones included,
+// and soft-deleting the live ones, so they can still be restored from the
+// trash. Detaching bumps each todo's version and is logged and published like
+// any other change, rather than left to the foreign key.
+func (l ListRepository) Delete(userID, listID int64) (int64, error) {
+	var rowsAffected int64
+	err := inBatch(l.DB).Transaction(func(tx *gorm.DB) error {
+		var todos []entity.Todolist
+		err := tx.Unscoped().
+			Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("list_id = ? AND user_id = ?", listID, userID).
+			Find(&todos).Error
+		if err != nil {
+			return err
+		}
+		for i := range todos {
+			if err := detachTodo(tx, userID, &todos[i]); err != nil {
+				return err
+			}
+		}
+		result := tx.Where("id = ? AND user_id = ?", listID, userID).Delete(&entity.List{})
+		rowsAffected = result.RowsAffected
+		return result.Error
+	})
+	return rowsAffected, err
+}
+
+// detachTodo takes a todo out of the list being deleted. A live todo is
+// trashed in the same step and published as deleted from that list; one
+// already in the trash is published as updated and logged as unlisted, which
+// undo leaves alone since the list is gone for good.
+func detachTodo(tx *gorm.DB, userID int64, todo *entity.Todolist) error {
+	before := *todo
+	updates := map[string]interface{}{"list_id": nil, "version": gorm.Expr("version + 1")}
+	live := !todo.DeletedAt.Valid
+	if live {
+		updates["deleted_at"] = time.Now()
+	}
+	if err := tx.Unscoped().Model(todo).Updates(updates).Error; err != nil {
+		return err
+	}
+	if err := tx.Unscoped().First(todo, todo.ID).Error; err != nil {
+		return err
+	}
+	if !live {
+		if err := recordActivity(tx, userID, todo, entity.ActivityUnlisted, todoChanges(&before, todo)); err != nil {
+			return err
+		}
+		return recordEvent(tx, userID, entity.EventTodoUpdated, todo)
+	}
+	if err := recordActivity(tx, userID, todo, entity.ActivityDeleted, todoChanges(&before, todo)); err != nil {
+		return err
+	}
+	return recordEvent(tx, userID, entity.EventTodoDeleted, deletedTodo{ID: todo.ID, ListID: before.ListID})
+}
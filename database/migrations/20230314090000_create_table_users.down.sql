@@ -0,0 +1 @@
+DROP TABLE IF EXISTS users;
\ No newline at end of file
@@ -0,0 +1,10 @@
+CREATE TABLE users
+(
+    id bigint NOT NULL AUTO_INCREMENT,
+    username varchar (100) not null,
+    password varchar (255) not null,
+    created_at datetime DEFAULT CURRENT_TIMESTAMP,
+    updated_at datetime DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_users_username (username)
+);
@@ -0,0 +1,4 @@
+ALTER TABLE todolists
+    DROP FOREIGN KEY fk_todolists_user,
+    DROP INDEX idx_todolists_user_id,
+    DROP COLUMN user_id;
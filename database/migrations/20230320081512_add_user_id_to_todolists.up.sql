@@ -0,0 +1,4 @@
+ALTER TABLE todolists
+    ADD COLUMN user_id bigint NULL AFTER id,
+    ADD INDEX idx_todolists_user_id (user_id),
+    ADD CONSTRAINT fk_todolists_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE;
@@ -0,0 +1,5 @@
+ALTER TABLE todolists
+    DROP INDEX idx_todolists_user_title,
+    DROP INDEX idx_todolists_user_created_at,
+    DROP INDEX idx_todolists_user_status,
+    DROP COLUMN created_at;
@@ -0,0 +1,5 @@
+ALTER TABLE todolists
+    ADD COLUMN created_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3),
+    ADD INDEX idx_todolists_user_status (user_id, status),
+    ADD INDEX idx_todolists_user_created_at (user_id, created_at),
+    ADD INDEX idx_todolists_user_title (user_id, title);
@@ -0,0 +1,2 @@
+ALTER TABLE todolists
+    DROP INDEX ft_todolists_title;
@@ -0,0 +1,2 @@
+ALTER TABLE todolists
+    ADD FULLTEXT INDEX ft_todolists_title (title);
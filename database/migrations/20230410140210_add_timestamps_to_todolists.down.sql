@@ -0,0 +1,4 @@
+ALTER TABLE todolists
+    DROP INDEX idx_todolists_deleted_at,
+    DROP COLUMN deleted_at,
+    DROP COLUMN updated_at;
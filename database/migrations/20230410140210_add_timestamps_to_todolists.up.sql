@@ -0,0 +1,4 @@
+ALTER TABLE todolists
+    ADD COLUMN updated_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    ADD COLUMN deleted_at datetime(3) NULL,
+    ADD INDEX idx_todolists_deleted_at (deleted_at);
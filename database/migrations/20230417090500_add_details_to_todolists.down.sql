@@ -0,0 +1,8 @@
+ALTER TABLE todolists
+    DROP INDEX ft_todolists_title_description,
+    ADD FULLTEXT INDEX ft_todolists_title (title),
+    DROP INDEX idx_todolists_user_due_date,
+    DROP COLUMN completed_at,
+    DROP COLUMN priority,
+    DROP COLUMN due_date,
+    DROP COLUMN description;
@@ -0,0 +1,8 @@
+ALTER TABLE todolists
+    ADD COLUMN description text NULL AFTER title,
+    ADD COLUMN due_date datetime(3) NULL,
+    ADD COLUMN priority enum ('low', 'medium', 'high', 'urgent') NOT NULL DEFAULT 'medium',
+    ADD COLUMN completed_at datetime(3) NULL,
+    ADD INDEX idx_todolists_user_due_date (user_id, due_date),
+    DROP INDEX ft_todolists_title,
+    ADD FULLTEXT INDEX ft_todolists_title_description (title, description);
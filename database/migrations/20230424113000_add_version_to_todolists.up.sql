@@ -0,0 +1,2 @@
+ALTER TABLE todolists
+    ADD COLUMN version bigint NOT NULL DEFAULT 1;
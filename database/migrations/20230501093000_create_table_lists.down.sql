@@ -0,0 +1,6 @@
+ALTER TABLE todolists
+    DROP FOREIGN KEY fk_todolists_list,
+    DROP INDEX idx_todolists_list_id,
+    DROP COLUMN list_id;
+
+DROP TABLE lists;
@@ -0,0 +1,17 @@
+CREATE TABLE lists
+(
+    id bigint NOT NULL AUTO_INCREMENT,
+    user_id bigint NOT NULL,
+    name varchar (100) NOT NULL,
+    archived tinyint(1) NOT NULL DEFAULT 0,
+    created_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3),
+    updated_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (id),
+    KEY idx_lists_user_archived (user_id, archived),
+    CONSTRAINT fk_lists_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+ALTER TABLE todolists
+    ADD COLUMN list_id bigint NULL AFTER user_id,
+    ADD INDEX idx_todolists_list_id (list_id),
+    ADD CONSTRAINT fk_todolists_list FOREIGN KEY (list_id) REFERENCES lists (id) ON DELETE SET NULL;
@@ -0,0 +1,13 @@
+CREATE TABLE subtasks
+(
+    id bigint NOT NULL AUTO_INCREMENT,
+    todo_id bigint NOT NULL,
+    title varchar (300) NOT NULL,
+    status tinyint(1) NOT NULL DEFAULT 0,
+    position int NOT NULL DEFAULT 0,
+    created_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3),
+    updated_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (id),
+    KEY idx_subtasks_todo_position (todo_id, position),
+    CONSTRAINT fk_subtasks_todo FOREIGN KEY (todo_id) REFERENCES todolists (id) ON DELETE CASCADE
+);
@@ -0,0 +1,20 @@
+CREATE TABLE tags
+(
+    id bigint NOT NULL AUTO_INCREMENT,
+    user_id bigint NOT NULL,
+    name varchar (50) NOT NULL,
+    created_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (id),
+    UNIQUE KEY uq_tags_user_name (user_id, name),
+    CONSTRAINT fk_tags_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE todo_tags
+(
+    todo_id bigint NOT NULL,
+    tag_id bigint NOT NULL,
+    PRIMARY KEY (todo_id, tag_id),
+    KEY idx_todo_tags_tag_id (tag_id),
+    CONSTRAINT fk_todo_tags_todo FOREIGN KEY (todo_id) REFERENCES todolists (id) ON DELETE CASCADE,
+    CONSTRAINT fk_todo_tags_tag FOREIGN KEY (tag_id) REFERENCES tags (id) ON DELETE CASCADE
+);
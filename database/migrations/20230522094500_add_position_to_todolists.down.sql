@@ -0,0 +1,3 @@
+ALTER TABLE todolists
+    DROP INDEX idx_todolists_user_position,
+    DROP COLUMN position;
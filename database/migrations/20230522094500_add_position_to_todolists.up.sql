@@ -0,0 +1,5 @@
+ALTER TABLE todolists
+    ADD COLUMN position double NOT NULL DEFAULT 0,
+    ADD INDEX idx_todolists_user_position (user_id, position);
+
+UPDATE todolists SET position = id * 1024;
@@ -0,0 +1,2 @@
+ALTER TABLE todolists
+    ADD COLUMN recurrence varchar(255) NULL;
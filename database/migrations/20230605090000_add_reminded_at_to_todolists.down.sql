@@ -0,0 +1,3 @@
+ALTER TABLE todolists
+    DROP INDEX idx_todolists_due_reminder,
+    DROP COLUMN reminded_at;
@@ -0,0 +1,3 @@
+ALTER TABLE todolists
+    ADD COLUMN reminded_at datetime(3) NULL,
+    ADD INDEX idx_todolists_due_reminder (status, reminded_at, due_date);
@@ -0,0 +1,34 @@
+CREATE TABLE webhooks
+(
+    id bigint NOT NULL AUTO_INCREMENT,
+    user_id bigint NOT NULL,
+    url varchar (2048) NOT NULL,
+    secret varchar (64) NOT NULL,
+    events varchar (512) NOT NULL DEFAULT '[]',
+    active tinyint(1) NOT NULL DEFAULT 1,
+    created_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3),
+    updated_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (id),
+    KEY idx_webhooks_user_id (user_id),
+    CONSTRAINT fk_webhooks_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
+
+CREATE TABLE webhook_deliveries
+(
+    id bigint NOT NULL AUTO_INCREMENT,
+    webhook_id bigint NOT NULL,
+    event varchar (50) NOT NULL,
+    payload text NOT NULL,
+    status enum ('pending', 'succeeded', 'failed') NOT NULL DEFAULT 'pending',
+    attempts int NOT NULL DEFAULT 0,
+    response_code int NULL,
+    last_error text NULL,
+    next_attempt_at datetime(3) NULL,
+    delivered_at datetime(3) NULL,
+    created_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3),
+    updated_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (id),
+    KEY idx_webhook_deliveries_webhook_id (webhook_id, id),
+    KEY idx_webhook_deliveries_due (status, next_attempt_at),
+    CONSTRAINT fk_webhook_deliveries_webhook FOREIGN KEY (webhook_id) REFERENCES webhooks (id) ON DELETE CASCADE
+);
@@ -0,0 +1,5 @@
+ALTER TABLE webhook_deliveries
+    DROP INDEX idx_webhook_deliveries_event,
+    DROP COLUMN event_id;
+
+DROP TABLE IF EXISTS outbox_events;
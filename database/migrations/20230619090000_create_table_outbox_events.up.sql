@@ -0,0 +1,19 @@
+CREATE TABLE outbox_events
+(
+    id bigint NOT NULL AUTO_INCREMENT,
+    event_id char(36) NOT NULL,
+    user_id bigint NOT NULL,
+    event varchar (50) NOT NULL,
+    payload mediumtext NOT NULL,
+    attempts int NOT NULL DEFAULT 0,
+    last_error text NULL,
+    published_at datetime(3) NULL,
+    created_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_outbox_events_event_id (event_id),
+    KEY idx_outbox_events_published_at (published_at, id)
+);
+
+ALTER TABLE webhook_deliveries
+    ADD COLUMN event_id char(36) NULL AFTER webhook_id,
+    ADD UNIQUE INDEX idx_webhook_deliveries_event (webhook_id, event_id);
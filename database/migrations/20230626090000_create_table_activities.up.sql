@@ -0,0 +1,13 @@
+CREATE TABLE activities
+(
+    id bigint NOT NULL AUTO_INCREMENT,
+    user_id bigint NOT NULL,
+    todo_id bigint NOT NULL,
+    revision bigint NOT NULL,
+    action varchar (50) NOT NULL,
+    changes text NULL,
+    created_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (id),
+    KEY idx_activities_user (user_id, id),
+    KEY idx_activities_todo (todo_id, id)
+);
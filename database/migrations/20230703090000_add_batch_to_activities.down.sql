@@ -0,0 +1,3 @@
+ALTER TABLE activities
+    DROP INDEX idx_activities_batch,
+    DROP COLUMN batch_id;
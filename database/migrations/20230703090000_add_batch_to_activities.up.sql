@@ -0,0 +1,3 @@
+ALTER TABLE activities
+    ADD COLUMN batch_id char(36) NOT NULL DEFAULT '' AFTER revision,
+    ADD INDEX idx_activities_batch (user_id, batch_id);
@@ -0,0 +1,13 @@
+CREATE TABLE comments
+(
+    id bigint NOT NULL AUTO_INCREMENT,
+    todo_id bigint NOT NULL,
+    user_id bigint NOT NULL,
+    body text NOT NULL,
+    created_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3),
+    updated_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3) ON UPDATE CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (id),
+    KEY idx_comments_todo (todo_id, id),
+    CONSTRAINT fk_comments_todo FOREIGN KEY (todo_id) REFERENCES todolists (id) ON DELETE CASCADE,
+    CONSTRAINT fk_comments_user FOREIGN KEY (user_id) REFERENCES users (id) ON DELETE CASCADE
+);
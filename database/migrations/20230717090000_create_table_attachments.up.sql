@@ -0,0 +1,15 @@
+CREATE TABLE attachments
+(
+    id bigint NOT NULL AUTO_INCREMENT,
+    todo_id bigint NOT NULL,
+    user_id bigint NOT NULL,
+    filename varchar(255) NOT NULL,
+    content_type varchar(100) NOT NULL,
+    size bigint NOT NULL,
+    checksum char(64) NOT NULL,
+    storage_key varchar(100) NOT NULL,
+    created_at datetime(3) NULL DEFAULT CURRENT_TIMESTAMP(3),
+    PRIMARY KEY (id),
+    UNIQUE KEY idx_attachments_storage_key (storage_key),
+    KEY idx_attachments_todo (todo_id, id)
+);
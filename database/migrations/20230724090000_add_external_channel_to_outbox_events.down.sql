@@ -0,0 +1,5 @@
+ALTER TABLE outbox_events
+    DROP INDEX idx_outbox_events_external_published_at,
+    DROP COLUMN external_published_at,
+    DROP COLUMN external_last_error,
+    DROP COLUMN external_attempts;
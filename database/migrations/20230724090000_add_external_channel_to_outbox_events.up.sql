@@ -0,0 +1,8 @@
+ALTER TABLE outbox_events
+    ADD COLUMN external_attempts int NOT NULL DEFAULT 0 AFTER published_at,
+    ADD COLUMN external_last_error text NULL AFTER external_attempts,
+    ADD COLUMN external_published_at datetime(3) NULL AFTER external_last_error,
+    ADD INDEX idx_outbox_events_external_published_at (external_published_at, id);
+
+-- events the single relay already published reached the external sink too
+UPDATE outbox_events SET external_published_at = published_at WHERE published_at IS NOT NULL;
@@ -0,0 +1,3 @@
+ALTER TABLE outbox_events
+    DROP COLUMN external_failed_at,
+    DROP COLUMN failed_at;
@@ -0,0 +1,3 @@
+ALTER TABLE outbox_events
+    ADD COLUMN failed_at datetime(3) NULL AFTER published_at,
+    ADD COLUMN external_failed_at datetime(3) NULL AFTER external_published_at;
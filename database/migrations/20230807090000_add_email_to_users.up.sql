@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD COLUMN email varchar (254) NULL AFTER username;
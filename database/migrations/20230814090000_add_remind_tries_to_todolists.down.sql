@@ -0,0 +1,3 @@
+ALTER TABLE todolists
+    DROP COLUMN remind_after,
+    DROP COLUMN remind_tries;
@@ -0,0 +1,3 @@
+ALTER TABLE todolists
+    ADD COLUMN remind_tries int NOT NULL DEFAULT 0 AFTER reminded_at,
+    ADD COLUMN remind_after datetime(3) NULL AFTER remind_tries;
@@ -0,0 +1,157 @@
+package database
+
+import (
+	"crypto/rand"
+	"encoding/json"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+type OutboxRepository struct {
+	DB *gorm.DB
+}
+
+func NewOutboxRepository(dbClient *gorm.DB) repository.OutboxRepository {
+	return &OutboxRepository{
+		DB: dbClient,
+	}
+}
+
+// deletedTodo is the data of a todo.deleted event.
+type deletedTodo struct {
+	ID     int64  `json:"id"`
+	ListID *int64 `json:"list_id"`
+}
+
+// recordEvent writes event to the outbox inside tx, so that it is published
+// exactly when the change that caused it commits.
+func recordEvent(tx *gorm.DB, userID int64, event string, data interface{}) error {
+	outboxEvent, err := newOutboxEvent(userID, event, data, time.Now())
+	if err != nil {
+		return err
+	}
+	return tx.Create(outboxEvent).Error
+}
+
+func newOutboxEvent(userID int64, event string, data interface{}, now time.Time) (*entity.OutboxEvent, error) {
+	id, err := newEventID()
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(entity.EventPayload{ID: id, Event: event, OccurredAt: now, Data: data})
+	if err != nil {
+		return nil, err
+	}
+	return &entity.OutboxEvent{
+		EventID: id,
+		UserID:  userID,
+		Event:   event,
+		Payload: string(payload),
+	}, nil
+}
+
+// newEventID returns a random (version 4) UUID.
+func newEventID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
+
+// updateEvents names the events an update applying changes causes: always
+// todo.updated, and todo.completed too when it is what completed the todo.
+func updateEvents(changes map[string]interface{}) []string {
+	if changes["status"] == true {
+		return []string{entity.EventTodoUpdated, entity.EventTodoCompleted}
+	}
+	return []string{entity.EventTodoUpdated}
+}
+
+// outboxColumns are the columns tracking a channel's progress.
+type outboxColumns struct {
+	publishedAt, failedAt, attempts, lastError string
+}
+
+var channelColumns = map[string]outboxColumns{
+	repository.OutboxLocal:    {"published_at", "failed_at", "attempts", "last_error"},
+	repository.OutboxExternal: {"external_published_at", "external_failed_at", "external_attempts", "external_last_error"},
+}
+
+func columnsOf(channel string) (outboxColumns, error) {
+	columns, ok := channelColumns[channel]
+	if !ok {
+		return outboxColumns{}, fmt.Errorf("unknown outbox channel %q", channel)
+	}
+	return columns, nil
+}
+
+// Pending returns up to limit events the channel has neither published nor
+// given up on, oldest first.
+func (o OutboxRepository) Pending(channel string, limit int) ([]entity.OutboxEvent, error) {
+	columns, err := columnsOf(channel)
+	if err != nil {
+		return nil, err
+	}
+	var events []entity.OutboxEvent
+	result := o.DB.Where(columns.publishedAt + " IS NULL AND " + columns.failedAt + " IS NULL").Order("id").Limit(limit).Find(&events)
+	return events, result.Error
+}
+
+func (o OutboxRepository) MarkPublished(channel string, id int64, at time.Time) error {
+	columns, err := columnsOf(channel)
+	if err != nil {
+		return err
+	}
+	return o.DB.Model(&entity.OutboxEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+		columns.publishedAt: at,
+		columns.attempts:    gorm.Expr(columns.attempts + " + 1"),
+		columns.lastError:   nil,
+	}).Error
+}
+
+func (o OutboxRepository) MarkFailed(channel string, id int64, message string, at time.Time, maxAttempts int) (bool, error) {
+	columns, err := columnsOf(channel)
+	if err != nil {
+		return false, err
+	}
+	var failed bool
+	err = o.DB.Transaction(func(tx *gorm.DB) error {
+		err := tx.Model(&entity.OutboxEvent{}).Where("id = ?", id).Updates(map[string]interface{}{
+			columns.attempts:  gorm.Expr(columns.attempts + " + 1"),
+			columns.lastError: message,
+		}).Error
+		if err != nil {
+			return err
+		}
+		result := tx.Model(&entity.OutboxEvent{}).
+			Where("id = ? AND "+columns.attempts+" >= ?", id, maxAttempts).
+			Update(columns.failedAt, at)
+		failed = result.RowsAffected > 0
+		return result.Error
+	})
+	return failed, err
+}
+
+// Prune deletes the events that every one of channels published, or gave up
+// on, before publishedBefore.
+func (o OutboxRepository) Prune(publishedBefore time.Time, channels []string) (int64, error) {
+	if len(channels) == 0 {
+		return 0, nil
+	}
+	db := o.DB
+	for _, channel := range channels {
+		columns, err := columnsOf(channel)
+		if err != nil {
+			return 0, err
+		}
+		db = db.Where("COALESCE("+columns.publishedAt+", "+columns.failedAt+") < ?", publishedBefore)
+	}
+	result := db.Delete(&entity.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
@@ -0,0 +1,72 @@
+package database
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"regexp"
+	"testing"
+	"time"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+func TestNewEventID(t *testing.T) {
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	seen := make(map[string]bool)
+	for i := 0; i < 100; i++ {
+		id, err := newEventID()
+		require.NoError(t, err)
+		assert.Regexp(t, uuid, id)
+		assert.False(t, seen[id], "duplicate id %s", id)
+		seen[id] = true
+	}
+}
+
+func TestNewOutboxEvent(t *testing.T) {
+	now := time.Date(2023, 6, 19, 9, 0, 0, 0, time.UTC)
+	event, err := newOutboxEvent(7, entity.EventTodoCreated, &entity.Todolist{ID: 1, Title: "deploy"}, now)
+	require.NoError(t, err)
+	assert.Equal(t, int64(7), event.UserID)
+	assert.Equal(t, entity.EventTodoCreated, event.Event)
+
+	var payload struct {
+		ID         string          `json:"id"`
+		Event      string          `json:"event"`
+		OccurredAt time.Time       `json:"occurred_at"`
+		Data       entity.Todolist `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal([]byte(event.Payload), &payload))
+	assert.Equal(t, event.EventID, payload.ID)
+	assert.Equal(t, entity.EventTodoCreated, payload.Event)
+	assert.Equal(t, now, payload.OccurredAt)
+	assert.Equal(t, "deploy", payload.Data.Title)
+}
+
+func TestUpdateEvents(t *testing.T) {
+	testCases := []struct {
+		name     string
+		changes  map[string]interface{}
+		expected []string
+	}{
+		{name: "Edit", changes: map[string]interface{}{"title": "deploy"}, expected: []string{entity.EventTodoUpdated}},
+		{name: "Complete", changes: map[string]interface{}{"status": true}, expected: []string{entity.EventTodoUpdated, entity.EventTodoCompleted}},
+		{name: "Reopen", changes: map[string]interface{}{"status": false}, expected: []string{entity.EventTodoUpdated}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, updateEvents(tc.changes))
+		})
+	}
+}
+
+func TestColumnsOf(t *testing.T) {
+	columns, err := columnsOf(repository.OutboxExternal)
+	assert.NoError(t, err)
+	assert.Equal(t, "external_published_at", columns.publishedAt)
+	assert.Equal(t, "external_failed_at", columns.failedAt)
+
+	_, err = columnsOf("published_at IS NULL OR 1")
+	assert.Error(t, err)
+}
@@ -0,0 +1,173 @@
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"math"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+const (
+	// positionStep is the gap left between todos when appending and when
+	// renumbering, so that many moves fit in between before the next
+	// renumbering.
+	positionStep = 1024.0
+	// minPositionGap is the smallest gap still split by a move; below it the
+	// user's todos are renumbered first.
+	minPositionGap = 1e-6
+)
+
+// Move places the todo right before or after the anchor todo in the user's
+// manual order. It takes the midpoint of the gap, and only renumbers the
+// user's todos once that gap has become too small to split.
+func (t TodoRepository) Move(userID, todoID, version, anchorID int64, after bool) (*entity.Todolist, error) {
+	var todo entity.Todolist
+	err := inBatch(t.DB).Transaction(func(tx *gorm.DB) error {
+		if err := lockTodo(tx, userID, todoID, version, &todo); err != nil {
+			return err
+		}
+		before := todo
+		if anchorID == todoID {
+			return repository.ErrAnchorNotFound
+		}
+
+		position, err := positionNextTo(tx, userID, todoID, anchorID, after)
+		if err != nil {
+			return err
+		}
+		if position == nil {
+			if err := renumber(tx, userID); err != nil {
+				return err
+			}
+			if position, err = positionNextTo(tx, userID, todoID, anchorID, after); err != nil {
+				return err
+			}
+		}
+
+		err = tx.Model(&todo).Updates(map[string]interface{}{
+			"position": *position,
+			"version":  gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.First(&todo, todo.ID).Error; err != nil {
+			return err
+		}
+		if err := recordActivity(tx, userID, &todo, entity.ActivityMoved, todoChanges(&before, &todo)); err != nil {
+			return err
+		}
+		return recordEvent(tx, userID, entity.EventTodoUpdated, &todo)
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &todo, nil
+}
+
+// positionNextTo returns the position halfway between the anchor and its
+// neighbour on the requested side, ignoring the todo being moved, or nil when
+// that gap is too small to split.
+func positionNextTo(tx *gorm.DB, userID, todoID, anchorID int64, after bool) (*float64, error) {
+	var anchor entity.Todolist
+	err := tx.Where("id = ? AND user_id = ?", anchorID, userID).First(&anchor).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, repository.ErrAnchorNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	neighbours := tx.Model(&entity.Todolist{}).
+		Where("user_id = ? AND id <> ?", userID, todoID)
+	if after {
+		neighbours = neighbours.
+			Where("(position > ? OR (position = ? AND id > ?))", anchor.Position, anchor.Position, anchor.ID).
+			Order("position").Order("id")
+	} else {
+		neighbours = neighbours.
+			Where("(position < ? OR (position = ? AND id < ?))", anchor.Position, anchor.Position, anchor.ID).
+			Order("position DESC").Order("id DESC")
+	}
+	var neighbour []float64
+	if err := neighbours.Limit(1).Pluck("position", &neighbour).Error; err != nil {
+		return nil, err
+	}
+
+	var next *float64
+	if len(neighbour) > 0 {
+		next = &neighbour[0]
+	}
+	position, ok := positionBetween(anchor.Position, next, after)
+	if !ok {
+		return nil, nil
+	}
+	return &position, nil
+}
+
+// positionBetween returns the midpoint between the anchor and its neighbour,
+// or a full step past the anchor when it has none on that side. It reports
+// false when the gap is too small to split.
+func positionBetween(anchor float64, neighbour *float64, after bool) (float64, bool) {
+	if neighbour == nil {
+		if after {
+			return anchor + positionStep, true
+		}
+		return anchor - positionStep, true
+	}
+	if math.Abs(anchor-*neighbour) < minPositionGap {
+		return 0, false
+	}
+	return (anchor + *neighbour) / 2, true
+}
+
+// renumber spreads the user's todos, trashed ones included, evenly in their
+// current order. A live todo whose position changes gets a new version and
+// is logged as moved and published like any other move; a trashed one only
+// has its position put right, for when it is restored.
+func renumber(tx *gorm.DB, userID int64) error {
+	var todos []entity.Todolist
+	err := tx.Unscoped().
+		Where("user_id = ?", userID).
+		Order("position").Order("id").
+		Find(&todos).Error
+	if err != nil {
+		return err
+	}
+	for i := range todos {
+		todo := &todos[i]
+		position := float64(i+1) * positionStep
+		if todo.Position == position {
+			continue
+		}
+		if todo.DeletedAt.Valid {
+			err := tx.Unscoped().Model(todo).UpdateColumn("position", position).Error
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		before := *todo
+		err := tx.Model(todo).Updates(map[string]interface{}{
+			"position": position,
+			"version":  gorm.Expr("version + 1"),
+		}).Error
+		if err != nil {
+			return err
+		}
+		if err := tx.First(todo, todo.ID).Error; err != nil {
+			return err
+		}
+		if err := recordActivity(tx, userID, todo, entity.ActivityMoved, todoChanges(&before, todo)); err != nil {
+			return err
+		}
+		if err := recordEvent(tx, userID, entity.EventTodoUpdated, todo); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"github.com/stretchr/testify/assert"
+	"testing"
+)
+
+func TestPositionBetween(t *testing.T) {
+	at := func(p float64) *float64 { return &p }
+
+	testCases := []struct {
+		name      string
+		anchor    float64
+		neighbour *float64
+		after     bool
+		expected  float64
+		ok        bool
+	}{
+		{name: "After the last", anchor: 2048, after: true, expected: 3072, ok: true},
+		{name: "Before the first", anchor: 1024, after: false, expected: 0, ok: true},
+		{name: "After, before the next", anchor: 1024, neighbour: at(2048), after: true, expected: 1536, ok: true},
+		{name: "Before, after the previous", anchor: 2048, neighbour: at(1024), after: false, expected: 1536, ok: true},
+		{name: "Equal positions", anchor: 1024, neighbour: at(1024), after: true, ok: false},
+		{name: "Gap too small", anchor: 1024, neighbour: at(1024 + minPositionGap/2), after: true, ok: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			position, ok := positionBetween(tc.anchor, tc.neighbour, tc.after)
+			assert.Equal(t, tc.ok, ok)
+			if tc.ok {
+				assert.Equal(t, tc.expected, position)
+			}
+		})
+	}
+
+	t.Run("Repeated halving eventually asks for a renumber", func(t *testing.T) {
+		low, high := positionStep, 2*positionStep
+		moves := 0
+		for {
+			position, ok := positionBetween(low, &high, true)
+			if !ok {
+				break
+			}
+			assert.Greater(t, position, low)
+			assert.Less(t, position, high)
+			high = position
+			moves++
+		}
+		assert.Greater(t, moves, 20)
+	})
+}
@@ -0,0 +1,139 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"sort"
+	"time"
+	"todoGin/model/entity"
+	"todoGin/recurrence"
+	"todoGin/repository"
+)
+
+// maxOccurrencesPerTodo caps how many due dates a single recurring todo
+// contributes to Occurrences.
+const maxOccurrencesPerTodo = 1000
+
+// scheduleAfter returns the recurrence rule and due date the todo will have
+// once changes are applied. It fails when a rule would be left without a due
+// date to count from.
+func scheduleAfter(todo *entity.Todolist, changes map[string]interface{}) (*string, *time.Time, error) {
+	rule, due := todo.Recurrence, todo.DueDate
+	if value, ok := changes["recurrence"]; ok {
+		rule = nil
+		if s, ok := value.(string); ok {
+			rule = &s
+		}
+	}
+	if value, ok := changes["due_date"]; ok {
+		due = nil
+		if d, ok := value.(time.Time); ok {
+			due = &d
+		}
+	}
+	if rule != nil && due == nil {
+		return nil, nil, repository.ErrRecurrenceWithoutDueDate
+	}
+	return rule, due, nil
+}
+
+// nextOccurrence builds the todo that follows a completed instance of the
+// rule, due at the first occurrence after both its due date and completedAt
+// so that missed repetitions are skipped. It returns nil once the series has
+// ended.
+func nextOccurrence(todo *entity.Todolist, rule string, due, completedAt time.Time) (*entity.Todolist, error) {
+	parsed, err := recurrence.Parse(rule)
+	if err != nil {
+		return nil, err
+	}
+	after := due
+	if completedAt.After(after) {
+		after = completedAt
+	}
+	nextDue, rest, ok := parsed.Advance(due, after)
+	if !ok {
+		return nil, nil
+	}
+	nextRule := rest.String()
+	return &entity.Todolist{
+		UserID:      todo.UserID,
+		ListID:      todo.ListID,
+		Title:       todo.Title,
+		Description: todo.Description,
+		DueDate:     &nextDue,
+		Priority:    todo.Priority,
+		Recurrence:  &nextRule,
+	}, nil
+}
+
+// createOccurrence inserts next at the end of the user's order and gives it
+// the tags of the todo it follows and a fresh copy of its checklist.
+func createOccurrence(tx *gorm.DB, previousID int64, next *entity.Todolist) error {
+	if _, err := (TodoRepository{DB: tx}).Create(next); err != nil {
+		return err
+	}
+
+	var links []entity.TodoTag
+	if err := tx.Where("todo_id = ?", previousID).Find(&links).Error; err != nil {
+		return err
+	}
+	if len(links) > 0 {
+		for i := range links {
+			links[i].TodoID = next.ID
+		}
+		if err := tx.Create(&links).Error; err != nil {
+			return err
+		}
+	}
+
+	var subtasks []entity.Subtask
+	err := tx.Where("todo_id = ?", previousID).Order("position").Order("id").Find(&subtasks).Error
+	if err != nil || len(subtasks) == 0 {
+		return err
+	}
+	for i, subtask := range subtasks {
+		subtasks[i] = entity.Subtask{TodoID: next.ID, Title: subtask.Title, Position: subtask.Position}
+	}
+	return tx.Create(&subtasks).Error
+}
+
+// Occurrences lists the due dates in [from, to) of the user's open todos,
+// expanding recurring ones into the repetitions still to come. Occurrences
+// other than a todo's own due date are marked Projected.
+func (t TodoRepository) Occurrences(userID int64, from, to time.Time) ([]entity.Occurrence, error) {
+	var todos []entity.Todolist
+	result := t.DB.
+		Where("user_id = ? AND status = ? AND due_date < ?", userID, false, to).
+		Where("(due_date >= ? OR recurrence IS NOT NULL)", from).
+		Find(&todos)
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	occurrences := make([]entity.Occurrence, 0, len(todos))
+	for _, todo := range todos {
+		due := *todo.DueDate
+		dates := []time.Time{due}
+		if todo.Recurrence != nil {
+			rule, err := recurrence.Parse(*todo.Recurrence)
+			if err != nil {
+				return nil, err
+			}
+			dates = rule.Between(due, from, to, maxOccurrencesPerTodo)
+		}
+		for _, date := range dates {
+			occurrences = append(occurrences, entity.Occurrence{
+				TodoID:    todo.ID,
+				Title:     todo.Title,
+				DueDate:   date,
+				Projected: !date.Equal(due),
+			})
+		}
+	}
+	sort.SliceStable(occurrences, func(i, j int) bool {
+		if !occurrences[i].DueDate.Equal(occurrences[j].DueDate) {
+			return occurrences[i].DueDate.Before(occurrences[j].DueDate)
+		}
+		return occurrences[i].TodoID < occurrences[j].TodoID
+	})
+	return occurrences, nil
+}
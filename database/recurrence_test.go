@@ -0,0 +1,80 @@
+package database
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+func TestScheduleAfter(t *testing.T) {
+	weekly := "FREQ=WEEKLY"
+	due := time.Date(2023, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name    string
+		todo    entity.Todolist
+		changes map[string]interface{}
+		rule    *string
+		wantErr error
+	}{
+		{name: "Untouched", todo: entity.Todolist{Recurrence: &weekly, DueDate: &due}, changes: map[string]interface{}{"title": "x"}, rule: &weekly},
+		{name: "Rule added", todo: entity.Todolist{DueDate: &due}, changes: map[string]interface{}{"recurrence": weekly}, rule: &weekly},
+		{name: "Rule cleared", todo: entity.Todolist{Recurrence: &weekly, DueDate: &due}, changes: map[string]interface{}{"recurrence": nil}},
+		{name: "Due date cleared", todo: entity.Todolist{Recurrence: &weekly, DueDate: &due}, changes: map[string]interface{}{"due_date": nil}, wantErr: repository.ErrRecurrenceWithoutDueDate},
+		{name: "Rule without due date", todo: entity.Todolist{}, changes: map[string]interface{}{"recurrence": weekly}, wantErr: repository.ErrRecurrenceWithoutDueDate},
+		{name: "Both cleared", todo: entity.Todolist{Recurrence: &weekly, DueDate: &due}, changes: map[string]interface{}{"recurrence": nil, "due_date": nil}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, _, err := scheduleAfter(&tc.todo, tc.changes)
+			assert.ErrorIs(t, err, tc.wantErr)
+			assert.Equal(t, tc.rule, rule)
+		})
+	}
+}
+
+func TestNextOccurrence(t *testing.T) {
+	listID := int64(3)
+	description := "run the deploy checklist"
+	due := time.Date(2023, 5, 1, 9, 0, 0, 0, time.UTC)
+	todo := &entity.Todolist{
+		ID:          1,
+		UserID:      7,
+		ListID:      &listID,
+		Title:       "deploy",
+		Description: &description,
+		Priority:    entity.PriorityHigh,
+		Status:      true,
+	}
+
+	t.Run("Completed on time", func(t *testing.T) {
+		next, err := nextOccurrence(todo, "FREQ=WEEKLY;COUNT=3", due, due.Add(-time.Hour))
+		require.NoError(t, err)
+		require.NotNil(t, next)
+		assert.Equal(t, time.Date(2023, 5, 8, 9, 0, 0, 0, time.UTC), *next.DueDate)
+		assert.Equal(t, "FREQ=WEEKLY;COUNT=2", *next.Recurrence)
+		assert.Equal(t, todo.UserID, next.UserID)
+		assert.Equal(t, todo.ListID, next.ListID)
+		assert.Equal(t, todo.Title, next.Title)
+		assert.Equal(t, todo.Priority, next.Priority)
+		assert.False(t, next.Status)
+		assert.Zero(t, next.ID)
+	})
+
+	t.Run("Completed late skips missed occurrences", func(t *testing.T) {
+		next, err := nextOccurrence(todo, "FREQ=WEEKLY", due, due.AddDate(0, 0, 10))
+		require.NoError(t, err)
+		require.NotNil(t, next)
+		assert.Equal(t, time.Date(2023, 5, 15, 9, 0, 0, 0, time.UTC), *next.DueDate)
+	})
+
+	t.Run("Last occurrence", func(t *testing.T) {
+		next, err := nextOccurrence(todo, "FREQ=WEEKLY;COUNT=1", due, due)
+		require.NoError(t, err)
+		assert.Nil(t, next)
+	})
+}
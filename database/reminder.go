@@ -0,0 +1,51 @@
+package database
+
+import (
+	"gorm.io/gorm"
+	"time"
+	"todoGin/model/entity"
+)
+
+// DueSoon returns up to limit open todos, of every user, that are due by
+// before and have not been reminded about yet, soonest first. Overdue todos
+// missed while the scheduler was down are included. Reminders that failed
+// wait until their retry time and then come after the ones not tried yet, so
+// that failing ones cannot crowd the others out.
+func (t TodoRepository) DueSoon(now, before time.Time, limit int) ([]entity.Reminder, error) {
+	var reminders []entity.Reminder
+	result := t.DB.Model(&entity.Todolist{}).
+		Select("todolists.id AS todo_id, todolists.user_id, users.username, users.email, todolists.title, todolists.due_date, todolists.remind_tries AS attempts").
+		Joins("JOIN users ON users.id = todolists.user_id").
+		Where("todolists.status = ? AND todolists.reminded_at IS NULL", false).
+		Where("todolists.due_date <= ?", before).
+		Where("todolists.remind_after IS NULL OR todolists.remind_after <= ?", now).
+		Order("todolists.remind_tries").
+		Order("todolists.due_date").
+		Order("todolists.id").
+		Limit(limit).
+		Scan(&reminders)
+	return reminders, result.Error
+}
+
+// MarkReminded records that the reminder for the todo's dueDate went out. It
+// changes nothing when the due date has moved since, so the new date gets a
+// reminder of its own. Being bookkeeping, it leaves the version and
+// updated_at alone.
+func (t TodoRepository) MarkReminded(todoID int64, dueDate, at time.Time) (int64, error) {
+	result := t.DB.Model(&entity.Todolist{}).
+		Where("id = ? AND due_date = ? AND reminded_at IS NULL", todoID, dueDate).
+		UpdateColumn("reminded_at", at)
+	return result.RowsAffected, result.Error
+}
+
+// MarkReminderFailed counts a failed try to send the reminder for the todo's
+// dueDate and holds it back until retryAt. Like MarkReminded it leaves the
+// version and updated_at alone.
+func (t TodoRepository) MarkReminderFailed(todoID int64, dueDate, retryAt time.Time) error {
+	return t.DB.Model(&entity.Todolist{}).
+		Where("id = ? AND due_date = ? AND reminded_at IS NULL", todoID, dueDate).
+		UpdateColumns(map[string]interface{}{
+			"remind_tries": gorm.Expr("remind_tries + 1"),
+			"remind_after": retryAt,
+		}).Error
+}
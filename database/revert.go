@@ -0,0 +1,217 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"gorm.io/gorm"
+	"time"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+// revertFields turns the logged value of each field a revert writes back into
+// the value Update takes. Logged values are JSON-decoded once read back, so
+// times come as strings and numbers as float64. completed_at follows status.
+var revertFields = map[string]func(value interface{}) (interface{}, bool){
+	"title":       func(value interface{}) (interface{}, bool) { return asString(value) },
+	"description": optional(asString),
+	"status": func(value interface{}) (interface{}, bool) {
+		status, ok := value.(bool)
+		return status, ok
+	},
+	"due_date":   optional(asTime),
+	"priority":   func(value interface{}) (interface{}, bool) { return asString(value) },
+	"recurrence": optional(asString),
+	"list_id":    optional(asInt64),
+	"position":   func(value interface{}) (interface{}, bool) { return asFloat64(value) },
+}
+
+func optional(convert func(value interface{}) (interface{}, bool)) func(value interface{}) (interface{}, bool) {
+	return func(value interface{}) (interface{}, bool) {
+		if value == nil {
+			return nil, true
+		}
+		return convert(value)
+	}
+}
+
+func asString(value interface{}) (interface{}, bool) {
+	s, ok := value.(string)
+	return s, ok
+}
+
+func asTime(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case time.Time:
+		return v, true
+	case string:
+		t, err := time.Parse(time.RFC3339Nano, v)
+		return t, err == nil
+	}
+	return nil, false
+}
+
+func asInt64(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case int64:
+		return v, true
+	case float64:
+		return int64(v), true
+	}
+	return nil, false
+}
+
+func asFloat64(value interface{}) (interface{}, bool) {
+	v, ok := value.(float64)
+	return v, ok
+}
+
+// revertUpdates returns the updates that undo the field changes of
+// activities, given newest first, so the oldest value of each field wins.
+func revertUpdates(activities []entity.Activity) (map[string]interface{}, error) {
+	updates := make(map[string]interface{})
+	for _, activity := range activities {
+		for field, change := range activity.Changes {
+			convert, ok := revertFields[field]
+			if !ok {
+				continue
+			}
+			value, ok := convert(change.From)
+			if !ok {
+				return nil, fmt.Errorf("activity %d: cannot revert %s to %v", activity.ID, field, change.From)
+			}
+			updates[field] = value
+		}
+	}
+	return updates, nil
+}
+
+// Revert puts the todo's fields back the way they were at revision. It goes
+// through Update, so the revert is a change of its own with a new revision;
+// subtasks and tags are left as they are. A missing todo yields a nil todo.
+func (t TodoRepository) Revert(userID, todoID, revision int64, opts repository.UpdateOptions) (*entity.Todolist, int64, error) {
+	var todo *entity.Todolist
+	var rowsAffected int64
+	err := inBatch(t.DB).Transaction(func(tx *gorm.DB) error {
+		var activities []entity.Activity
+		err := tx.Where("user_id = ? AND todo_id = ? AND revision >= ?", userID, todoID, revision).
+			Order("id DESC").
+			Find(&activities).Error
+		if err != nil {
+			return err
+		}
+		if len(activities) == 0 || activities[len(activities)-1].Revision != revision {
+			return repository.ErrRevisionNotFound
+		}
+		// the changes made at revision itself are part of the state to keep
+		var later []entity.Activity
+		for _, activity := range activities {
+			if activity.Revision > revision {
+				later = append(later, activity)
+			}
+		}
+		updates, err := revertUpdates(later)
+		if err != nil {
+			return err
+		}
+		todo, rowsAffected, err = TodoRepository{DB: tx}.Update(userID, todoID, opts, updates)
+		return err
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+	return todo, rowsAffected, nil
+}
+
+// discussionActions log comments and attachments. They are no change to the
+// todo itself, so Undo passes over them to the change before.
+var discussionActions = []string{
+	entity.ActivityCommented, entity.ActivityCommentEdited, entity.ActivityCommentDeleted,
+	entity.ActivityAttached, entity.ActivityDetached,
+}
+
+// Undo reverts the user's last change, with everything else the same request
+// changed, provided it was made after since. Field changes are reverted
+// through Update, a deletion by restoring the todo and a creation or a
+// restore by deleting it again; a change to subtasks or tags cannot be undone
+// and fails with repository.ErrCannotUndo, as does a batch with nothing to
+// revert but trashed todos taken out of a deleted list. It returns the IDs of
+// the todos reverted.
+func (t TodoRepository) Undo(userID int64, since time.Time) ([]int64, error) {
+	var todoIDs []int64
+	err := inBatch(t.DB).Transaction(func(tx *gorm.DB) error {
+		var last entity.Activity
+		err := tx.Where("user_id = ? AND created_at >= ? AND action NOT IN ?", userID, since, discussionActions).
+			Order("id DESC").First(&last).Error
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return repository.ErrNothingToUndo
+		}
+		if err != nil {
+			return err
+		}
+		var batch []entity.Activity
+		query := tx.Where("id = ?", last.ID)
+		// entries logged before batches were recorded stand on their own
+		if last.BatchID != "" {
+			query = tx.Where("user_id = ? AND batch_id = ? AND created_at >= ? AND action NOT IN ?", userID, last.BatchID, since, discussionActions)
+		}
+		err = query.Order("id DESC").Find(&batch).Error
+		if err != nil {
+			return err
+		}
+
+		inTx := TodoRepository{DB: tx}
+		undone := make(map[int64]bool)
+		for _, activity := range batch {
+			if activity.Action == entity.ActivityUnlisted {
+				continue
+			}
+			if err := inTx.undo(userID, activity); err != nil {
+				return err
+			}
+			if !undone[activity.TodoID] {
+				undone[activity.TodoID] = true
+				todoIDs = append(todoIDs, activity.TodoID)
+			}
+		}
+		if len(todoIDs) == 0 {
+			return repository.ErrCannotUndo
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return todoIDs, nil
+}
+
+// undo reverts a single activity. The todo may have been purged since, which
+// is reported as repository.ErrTodoNotFound.
+func (t TodoRepository) undo(userID int64, activity entity.Activity) error {
+	var rowsAffected int64
+	var err error
+	switch activity.Action {
+	case entity.ActivityUpdated, entity.ActivityMoved:
+		updates, err := revertUpdates([]entity.Activity{activity})
+		if err != nil {
+			return err
+		}
+		todo, _, err := t.Update(userID, activity.TodoID, repository.UpdateOptions{}, updates)
+		if err != nil {
+			return err
+		}
+		if todo != nil {
+			rowsAffected = 1
+		}
+	case entity.ActivityCreated, entity.ActivityRestored:
+		rowsAffected, err = t.Delete(userID, activity.TodoID, 0)
+	case entity.ActivityDeleted:
+		rowsAffected, err = t.Restore(userID, activity.TodoID)
+	default:
+		return repository.ErrCannotUndo
+	}
+	if err == nil && rowsAffected == 0 {
+		err = repository.ErrTodoNotFound
+	}
+	return err
+}
@@ -0,0 +1,58 @@
+package database
+
+import (
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+	"todoGin/model/entity"
+)
+
+func TestRevertUpdates(t *testing.T) {
+	due := time.Date(2023, 7, 3, 9, 0, 0, 0, time.UTC)
+	later := due.Add(24 * time.Hour)
+	listID := int64(3)
+	before := entity.Todolist{ID: 1, Title: "deploy", Priority: entity.PriorityMedium, DueDate: &due, ListID: &listID, Position: 1024}
+	edited := before
+	edited.Title, edited.DueDate, edited.ListID = "deploy v2", &later, nil
+	completed := edited
+	completed.Title, completed.Status, completed.CompletedAt = "deploy v3", true, &later
+
+	// newest first, as read back from the activities table
+	activities := []entity.Activity{
+		{ID: 3, Action: entity.ActivityUpdated, Changes: todoChanges(&edited, &completed)},
+		{ID: 2, Action: entity.ActivitySubtaskAdded, Changes: subtaskChanges(nil, &entity.Subtask{ID: 5, Title: "write tests"})},
+		{ID: 1, Action: entity.ActivityUpdated, Changes: todoChanges(&before, &edited)},
+	}
+	data, err := json.Marshal(activities)
+	require.NoError(t, err)
+	var stored []entity.Activity
+	require.NoError(t, json.Unmarshal(data, &stored))
+
+	expected := map[string]interface{}{
+		"title":    "deploy",
+		"status":   false,
+		"due_date": due,
+		"list_id":  listID,
+	}
+	testCases := []struct {
+		name       string
+		activities []entity.Activity
+	}{
+		{name: "As recorded", activities: activities},
+		{name: "As stored", activities: stored},
+	}
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			updates, err := revertUpdates(tc.activities)
+			require.NoError(t, err)
+			assert.Equal(t, expected, updates)
+		})
+	}
+
+	t.Run("Unreadable value", func(t *testing.T) {
+		_, err := revertUpdates([]entity.Activity{{ID: 4, Changes: entity.Changes{"due_date": {From: "tomorrow"}}}})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,186 @@
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+type SubtaskRepository struct {
+	DB *gorm.DB
+}
+
+func NewSubtaskRepository(dbClient *gorm.DB) repository.SubtaskRepository {
+	return &SubtaskRepository{
+		DB: dbClient,
+	}
+}
+
+// Create appends a subtask at the end of the todo's checklist.
+func (s SubtaskRepository) Create(userID, todoID int64, title string) (*entity.Subtask, error) {
+	subtask := entity.Subtask{TodoID: todoID, Title: title}
+	err := inBatch(s.DB).Transaction(func(tx *gorm.DB) error {
+		todo, err := touchTodo(tx, userID, todoID)
+		if err != nil {
+			return err
+		}
+		err = tx.Model(&entity.Subtask{}).
+			Select("COALESCE(MAX(position), 0) + 1").
+			Where("todo_id = ?", todoID).
+			Row().Scan(&subtask.Position)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(&subtask).Error; err != nil {
+			return err
+		}
+		return recordActivity(tx, userID, todo, entity.ActivitySubtaskAdded, subtaskChanges(nil, &subtask))
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subtask, nil
+}
+
+func (s SubtaskRepository) Update(userID, todoID, subtaskID int64, updates map[string]interface{}) (*entity.Subtask, error) {
+	var subtask entity.Subtask
+	err := inBatch(s.DB).Transaction(func(tx *gorm.DB) error {
+		var locked entity.Todolist
+		if err := lockTodo(tx, userID, todoID, 0, &locked); err != nil {
+			return err
+		}
+		if err := tx.Where("id = ? AND todo_id = ?", subtaskID, todoID).First(&subtask).Error; err != nil {
+			return err
+		}
+		before := subtask
+		changes, err := changedColumns(tx, &subtask, updates)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			// nothing to change, so the todo keeps its version
+			return nil
+		}
+		if err := tx.Model(&subtask).Updates(changes).Error; err != nil {
+			return err
+		}
+		if err := tx.First(&subtask, subtask.ID).Error; err != nil {
+			return err
+		}
+		todo, err := touchTodo(tx, userID, todoID)
+		if err != nil {
+			return err
+		}
+		return recordActivity(tx, userID, todo, entity.ActivitySubtaskUpdated, subtaskChanges(&before, &subtask))
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &subtask, nil
+}
+
+func (s SubtaskRepository) Delete(userID, todoID, subtaskID int64) (int64, error) {
+	var rowsAffected int64
+	err := inBatch(s.DB).Transaction(func(tx *gorm.DB) error {
+		todo, err := touchTodo(tx, userID, todoID)
+		if err != nil {
+			return err
+		}
+		var subtask entity.Subtask
+		// a missing subtask rolls back, leaving the parent's version alone
+		if err := tx.Where("id = ? AND todo_id = ?", subtaskID, todoID).First(&subtask).Error; err != nil {
+			return err
+		}
+		result := tx.Delete(&subtask)
+		if result.Error != nil {
+			return result.Error
+		}
+		rowsAffected = result.RowsAffected
+		return recordActivity(tx, userID, todo, entity.ActivitySubtaskRemoved, subtaskChanges(&subtask, nil))
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return rowsAffected, err
+}
+
+// Reorder renumbers the todo's subtasks in the order of subtaskIDs, which must
+// name each of them exactly once.
+func (s SubtaskRepository) Reorder(userID, todoID int64, subtaskIDs []int64) ([]entity.Subtask, error) {
+	var subtasks []entity.Subtask
+	err := inBatch(s.DB).Transaction(func(tx *gorm.DB) error {
+		todo, err := touchTodo(tx, userID, todoID)
+		if err != nil {
+			return err
+		}
+		if err := tx.Where("todo_id = ?", todoID).Order("position").Order("id").Find(&subtasks).Error; err != nil {
+			return err
+		}
+		byID := make(map[int64]*entity.Subtask, len(subtasks))
+		for i := range subtasks {
+			byID[subtasks[i].ID] = &subtasks[i]
+		}
+		if len(subtaskIDs) != len(subtasks) {
+			return repository.ErrInvalidOrder
+		}
+
+		previous := make([]int64, len(subtasks))
+		for i, subtask := range subtasks {
+			previous[i] = subtask.ID
+		}
+		ordered := make([]entity.Subtask, 0, len(subtaskIDs))
+		for i, id := range subtaskIDs {
+			subtask, ok := byID[id]
+			if !ok {
+				return repository.ErrInvalidOrder
+			}
+			delete(byID, id)
+			subtask.Position = i + 1
+			if err := tx.Model(subtask).Update("position", subtask.Position).Error; err != nil {
+				return err
+			}
+			ordered = append(ordered, *subtask)
+		}
+		subtasks = ordered
+		return recordActivity(tx, userID, todo, entity.ActivitySubtasksReordered, entity.Changes{
+			"subtask.order": {From: previous, To: subtaskIDs},
+		})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return subtasks, nil
+}
+
+// subtaskChanges is the activity of a subtask going from before to after,
+// where nil stands for a subtask being added or removed. The subtask's ID is
+// always included, to tell which one it was.
+func subtaskChanges(before, after *entity.Subtask) entity.Changes {
+	changes := make(entity.Changes)
+	field := func(name string, value func(subtask *entity.Subtask) interface{}) {
+		var from, to interface{}
+		if before != nil {
+			from = value(before)
+		}
+		if after != nil {
+			to = value(after)
+		}
+		if name == "id" || from != to {
+			changes["subtask."+name] = entity.FieldChange{From: from, To: to}
+		}
+	}
+	field("id", func(subtask *entity.Subtask) interface{} { return subtask.ID })
+	field("title", func(subtask *entity.Subtask) interface{} { return subtask.Title })
+	field("status", func(subtask *entity.Subtask) interface{} { return subtask.Status })
+	return changes
+}
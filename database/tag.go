@@ -0,0 +1,133 @@
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+type TagRepository struct {
+	DB *gorm.DB
+}
+
+func NewTagRepository(dbClient *gorm.DB) repository.TagRepository {
+	return &TagRepository{
+		DB: dbClient,
+	}
+}
+
+// GetAll lists the user's tags by name with how many live todos carry each.
+func (t TagRepository) GetAll(userID int64) ([]entity.TagCount, error) {
+	var tags []entity.TagCount
+	result := t.DB.Model(&entity.Tag{}).
+		Select("tags.*, COUNT(todolists.id) AS todo_count").
+		Joins("LEFT JOIN todo_tags ON todo_tags.tag_id = tags.id").
+		Joins("LEFT JOIN todolists ON todolists.id = todo_tags.todo_id AND todolists.deleted_at IS NULL").
+		Where("tags.user_id = ?", userID).
+		Group("tags.id").
+		Order("tags.name").
+		Scan(&tags)
+	return tags, result.Error
+}
+
+// Attach labels the todo with the named tags, creating the ones the user does
+// not have yet, and returns all of the todo's tags. Only a todo that gains a
+// tag gets a new version.
+func (t TagRepository) Attach(userID, todoID int64, names []string) ([]entity.Tag, error) {
+	var tags []entity.Tag
+	err := inBatch(t.DB).Transaction(func(tx *gorm.DB) error {
+		var todo entity.Todolist
+		if err := lockTodo(tx, userID, todoID, 0, &todo); err != nil {
+			return err
+		}
+		before, err := tagNames(tx, todoID)
+		if err != nil {
+			return err
+		}
+		links := make([]entity.TodoTag, 0, len(names))
+		linked := make(map[int64]bool, len(names))
+		for _, name := range names {
+			tag := entity.Tag{UserID: userID, Name: name}
+			if err := tx.Where(&tag).FirstOrCreate(&tag).Error; err != nil {
+				return err
+			}
+			if !linked[tag.ID] {
+				linked[tag.ID] = true
+				links = append(links, entity.TodoTag{TodoID: todoID, TagID: tag.ID})
+			}
+		}
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&links)
+		if result.Error != nil {
+			return result.Error
+		}
+		if err := tx.Model(&entity.Todolist{ID: todoID}).Order("name").Association("Tags").Find(&tags); err != nil {
+			return err
+		}
+		if result.RowsAffected == 0 {
+			// the todo had every tag already
+			return nil
+		}
+		touched, err := touchTodo(tx, userID, todoID)
+		if err != nil {
+			return err
+		}
+		after := make([]string, len(tags))
+		for i, tag := range tags {
+			after[i] = tag.Name
+		}
+		return recordActivity(tx, userID, touched, entity.ActivityTagged, entity.Changes{"tags": {From: before, To: after}})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+func (t TagRepository) Detach(userID, todoID int64, name string) (int64, error) {
+	var rowsAffected int64
+	err := inBatch(t.DB).Transaction(func(tx *gorm.DB) error {
+		todo, err := touchTodo(tx, userID, todoID)
+		if err != nil {
+			return err
+		}
+		before, err := tagNames(tx, todoID)
+		if err != nil {
+			return err
+		}
+		tagIDs := tx.Model(&entity.Tag{}).Select("id").Where("user_id = ? AND name = ?", userID, name)
+		result := tx.Where("todo_id = ? AND tag_id IN (?)", todoID, tagIDs).Delete(&entity.TodoTag{})
+		if result.Error != nil {
+			return result.Error
+		}
+		rowsAffected = result.RowsAffected
+		if rowsAffected == 0 {
+			// nothing detached, so leave the todo's version alone
+			return gorm.ErrRecordNotFound
+		}
+		after, err := tagNames(tx, todoID)
+		if err != nil {
+			return err
+		}
+		return recordActivity(tx, userID, todo, entity.ActivityUntagged, entity.Changes{"tags": {From: before, To: after}})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return rowsAffected, err
+}
+
+// tagNames returns the names of the todo's tags in order.
+func tagNames(tx *gorm.DB, todoID int64) ([]string, error) {
+	names := []string{}
+	err := tx.Table("tags").
+		Joins("JOIN todo_tags ON todo_tags.tag_id = tags.id").
+		Where("todo_tags.todo_id = ?", todoID).
+		Order("tags.name").
+		Pluck("tags.name", &names).Error
+	return names, err
+}
@@ -1,8 +1,11 @@
 package database
 
 import (
+	"database/sql"
 	"errors"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
 	"todoGin/model/entity"
 	"todoGin/repository"
 )
@@ -18,16 +21,53 @@ func NewTodoRepository(dbClient *gorm.DB) repository.TodoRepository {
 	}
 }
 
-func (t TodoRepository) GetAll() ([]entity.Todolist, error) {
+func (t TodoRepository) GetAll(userID int64, query repository.TodoQuery) ([]entity.Todolist, int64, error) {
 	var todos []entity.Todolist
+	var total int64
 
-	result := t.DB.Find(&todos)
-	return todos, result.Error
+	filter := func(db *gorm.DB) *gorm.DB {
+		db = db.Where("user_id = ?", userID)
+		if query.Trashed {
+			db = db.Unscoped().Where("deleted_at IS NOT NULL")
+		}
+		if query.Status != nil {
+			db = db.Where("status = ?", *query.Status)
+		}
+		if query.ListID != nil {
+			db = db.Where("list_id = ?", *query.ListID)
+		}
+		if len(query.Tags) > 0 {
+			db = db.Where("todolists.id IN (?)", t.taggedTodoIDs(userID, query.Tags, query.MatchAllTags))
+		}
+		return db
+	}
+	if err := t.DB.Model(&entity.Todolist{}).Scopes(filter).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	sort := query.Sort
+	if sort == "" {
+		sort = "position"
+	}
+	db := t.DB.Scopes(filter).
+		Select("todolists.*, (SELECT COUNT(*) FROM comments WHERE comments.todo_id = todolists.id) AS comment_count").
+		Preload("Tags").
+		Order(clause.OrderByColumn{Column: clause.Column{Name: sort}, Desc: query.Desc}).
+		Order("id").
+		Offset(query.Offset)
+	if query.Limit > 0 {
+		db = db.Limit(query.Limit)
+	}
+	result := db.Find(&todos)
+	return todos, total, result.Error
 }
 
-func (t TodoRepository) GetByID(todoID int64) (*entity.Todolist, error) {
+// GetByID returns the todo with its subtasks in checklist order.
+func (t TodoRepository) GetByID(userID, todoID int64) (*entity.Todolist, error) {
 	var todo entity.Todolist
-	result := t.DB.Where("id = ?", todoID).First(&todo)
+	result := t.DB.Preload("Subtasks", func(db *gorm.DB) *gorm.DB {
+		return db.Order("position").Order("id")
+	}).Preload("Tags").Where("id = ? AND user_id = ?", todoID, userID).First(&todo)
 	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
 		return nil, nil
 	}
@@ -35,31 +75,259 @@ func (t TodoRepository) GetByID(todoID int64) (*entity.Todolist, error) {
 	return &todo, result.Error
 }
 
-func (t TodoRepository) Create(title string) (*entity.Todolist, error) {
-	todo := entity.Todolist{
-		Title: title,
+// Create inserts the todo at the end of the user's manual order.
+func (t TodoRepository) Create(todo *entity.Todolist) (*entity.Todolist, error) {
+	err := inBatch(t.DB).Transaction(func(tx *gorm.DB) error {
+		err := tx.Unscoped().Model(&entity.Todolist{}).
+			Select("COALESCE(MAX(position), 0) + ?", positionStep).
+			Where("user_id = ?", todo.UserID).
+			Row().Scan(&todo.Position)
+		if err != nil {
+			return err
+		}
+		if err := tx.Create(todo).Error; err != nil {
+			return err
+		}
+		err = recordActivity(tx, todo.UserID, todo, entity.ActivityCreated, todoChanges(&entity.Todolist{}, todo))
+		if err != nil {
+			return err
+		}
+		return recordEvent(tx, todo.UserID, entity.EventTodoCreated, todo)
+	})
+	return todo, err
+}
+
+// Update applies updates to the todo under a row lock and returns the row as
+// stored afterwards, with the number of rows actually changed. Columns whose
+// value is unchanged are skipped; flipping status also stamps or clears
+// completed_at, and completing it may complete its subtasks too. Completing
+// a recurring todo also creates its next occurrence. Every real change bumps
+// the version. A missing todo yields a nil todo.
+func (t TodoRepository) Update(userID, todoID int64, opts repository.UpdateOptions, updates map[string]interface{}) (*entity.Todolist, int64, error) {
+	var todo entity.Todolist
+	var rowsAffected int64
+	err := inBatch(t.DB).Transaction(func(tx *gorm.DB) error {
+		if err := lockTodo(tx, userID, todoID, opts.Version, &todo); err != nil {
+			return err
+		}
+		before := todo
+
+		changes, err := changedColumns(tx, &todo, updates)
+		if err != nil {
+			return err
+		}
+		if len(changes) == 0 {
+			return nil
+		}
+		now := time.Now()
+		if status, ok := changes["status"].(bool); ok {
+			if status {
+				changes["completed_at"] = now
+			} else {
+				changes["completed_at"] = nil
+			}
+		}
+
+		// a new due date deserves a new reminder
+		if _, ok := changes["due_date"]; ok {
+			changes["reminded_at"] = nil
+			changes["remind_tries"] = 0
+			changes["remind_after"] = nil
+		}
+
+		rule, due, err := scheduleAfter(&todo, changes)
+		if err != nil {
+			return err
+		}
+		// the series moves on to the next occurrence, so the completed
+		// instance stops recurring and reopening it does not repeat twice
+		completesSeries := changes["status"] == true && rule != nil
+		if completesSeries {
+			changes["recurrence"] = nil
+		}
+
+		changes["version"] = gorm.Expr("version + 1")
+
+		result := tx.Model(&todo).Where("version = ?", todo.Version).Updates(changes)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return repository.ErrVersionMismatch
+		}
+		rowsAffected = result.RowsAffected
+
+		if changes["status"] == true && opts.CascadeSubtasks {
+			err := tx.Model(&entity.Subtask{}).
+				Where("todo_id = ? AND status = ?", todo.ID, false).
+				Update("status", true).Error
+			if err != nil {
+				return err
+			}
+		}
+		if err := tx.First(&todo, todo.ID).Error; err != nil {
+			return err
+		}
+		if err := recordActivity(tx, userID, &todo, entity.ActivityUpdated, todoChanges(&before, &todo)); err != nil {
+			return err
+		}
+		for _, event := range updateEvents(changes) {
+			if err := recordEvent(tx, userID, event, &todo); err != nil {
+				return err
+			}
+		}
+
+		if completesSeries {
+			next, err := nextOccurrence(&todo, *rule, *due, now)
+			if err != nil || next == nil {
+				return err
+			}
+			return createOccurrence(tx, todo.ID, next)
+		}
+		return nil
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, 0, nil
 	}
-	result := t.DB.Create(&todo)
-	return &todo, result.Error
+	if err != nil {
+		return nil, 0, err
+	}
+	return &todo, rowsAffected, nil
 }
 
-func (t TodoRepository) Update(todoID int64, updates map[string]interface{}) (*entity.Todolist, error) {
+// taggedTodoIDs selects the IDs of the user's todos carrying any of the named
+// tags, or all of them when matchAll is set.
+func (t TodoRepository) taggedTodoIDs(userID int64, names []string, matchAll bool) *gorm.DB {
+	sub := t.DB.Table("todo_tags").
+		Select("todo_tags.todo_id").
+		Joins("JOIN tags ON tags.id = todo_tags.tag_id").
+		Where("tags.user_id = ? AND tags.name IN ?", userID, names)
+	if matchAll {
+		sub = sub.Group("todo_tags.todo_id").
+			Having("COUNT(DISTINCT tags.id) = ?", len(names))
+	}
+	return sub
+}
+
+// Delete soft-deletes the todo; it stays in the trash until restored or purged.
+func (t TodoRepository) Delete(userID, todoID, version int64) (int64, error) {
+	var rowsAffected int64
+	err := inBatch(t.DB).Transaction(func(tx *gorm.DB) error {
+		var todo entity.Todolist
+		if err := lockTodo(tx, userID, todoID, version, &todo); err != nil {
+			return err
+		}
+		result := tx.Delete(&todo)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		rowsAffected = result.RowsAffected
+		if err := recordActivity(tx, userID, &todo, entity.ActivityDeleted, nil); err != nil {
+			return err
+		}
+		return recordEvent(tx, userID, entity.EventTodoDeleted, deletedTodo{ID: todo.ID, ListID: todo.ListID})
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, nil
+	}
+	return rowsAffected, err
+}
+
+// lockTodo loads the caller's todo FOR UPDATE and checks it is still at the
+// expected version, unless version is 0.
+func lockTodo(tx *gorm.DB, userID, todoID, version int64, todo *entity.Todolist) error {
+	result := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ?", todoID, userID).
+		First(todo)
+	if result.Error != nil {
+		return result.Error
+	}
+	if version != 0 && todo.Version != version {
+		return repository.ErrVersionMismatch
+	}
+	return nil
+}
+
+// touchTodo locks the caller's todo, bumps its version and records a
+// todo.updated event, since its subtasks or tags are about to change. It
+// returns the todo as stored afterwards, for the caller to log its change
+// against, and fails with gorm.ErrRecordNotFound when the todo is missing or
+// not the caller's.
+func touchTodo(tx *gorm.DB, userID, todoID int64) (*entity.Todolist, error) {
 	var todo entity.Todolist
-	result := t.DB.Model(&todo).Where("id = ?", todoID).Updates(updates)
-	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-		return nil, nil
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		Where("id = ? AND user_id = ?", todoID, userID).
+		First(&todo).Error
+	if err != nil {
+		return nil, err
 	}
-	return &todo, result.Error
+	if err := tx.Model(&todo).Update("version", gorm.Expr("version + 1")).Error; err != nil {
+		return nil, err
+	}
+	if err := tx.First(&todo, todo.ID).Error; err != nil {
+		return nil, err
+	}
+	return &todo, recordEvent(tx, userID, entity.EventTodoUpdated, &todo)
+}
+
+func (t TodoRepository) Search(userID int64, query string, limit int) ([]entity.TodoSearchResult, error) {
+	var results []entity.TodoSearchResult
+
+	match := "MATCH (title, description) AGAINST (? IN NATURAL LANGUAGE MODE)"
+	result := t.DB.Model(&entity.Todolist{}).
+		Select("todolists.*, "+match+" AS relevance", query).
+		Where("user_id = ?", userID).
+		Where(match, query).
+		Order("relevance DESC").
+		Order("id").
+		Limit(limit).
+		Scan(&results)
+	return results, result.Error
+}
+
+func (t TodoRepository) Restore(userID, todoID int64) (int64, error) {
+	var rowsAffected int64
+	err := inBatch(t.DB).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Model(&entity.Todolist{}).
+			Where("id = ? AND user_id = ? AND deleted_at IS NOT NULL", todoID, userID).
+			Update("deleted_at", nil)
+		if result.Error != nil || result.RowsAffected == 0 {
+			return result.Error
+		}
+		rowsAffected = result.RowsAffected
+
+		var todo entity.Todolist
+		if err := tx.First(&todo, todoID).Error; err != nil {
+			return err
+		}
+		if err := recordActivity(tx, userID, &todo, entity.ActivityRestored, nil); err != nil {
+			return err
+		}
+		return recordEvent(tx, userID, entity.EventTodoRestored, &todo)
+	})
+	return rowsAffected, err
 }
 
-func (t TodoRepository) Delete(todoID int64) (int64, error) {
-	todo := entity.Todolist{ID: todoID}
-	result := t.DB.Delete(&todo)
+// Purge permanently removes every todo, regardless of owner, that was
+// soft-deleted before deletedBefore.
+func (t TodoRepository) Purge(deletedBefore time.Time) (int64, error) {
+	result := t.DB.Unscoped().
+		Where("deleted_at IS NOT NULL AND deleted_at < ?", deletedBefore).
+		Delete(&entity.Todolist{})
 	return result.RowsAffected, result.Error
-	//if errors.Is(result.Error, gorm.ErrRecordNotFound) {
-	//	return false, nil
-	//}
-	//fmt.Printf("Id: %d", todoID)
-	//fmt.Println(result.Error)
-	//return true, result.Error
+}
+
+// LastModified returns the most recent change to any of the user's todos,
+// counting soft deletes, so that removing an item also moves the timestamp.
+// It is the zero time when the user has no todos.
+func (t TodoRepository) LastModified(userID int64) (time.Time, error) {
+	var last sql.NullTime
+	err := t.DB.Unscoped().Model(&entity.Todolist{}).
+		Select("MAX(GREATEST(updated_at, COALESCE(deleted_at, updated_at)))").
+		Where("user_id = ?", userID).
+		Row().Scan(&last)
+	if err != nil {
+		return time.Time{}, err
+	}
+	return last.Time, nil
 }
@@ -0,0 +1,42 @@
+package database
+
+import (
+	"errors"
+	"gorm.io/gorm"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+type UserRepository struct {
+	DB *gorm.DB
+}
+
+func NewUserRepository(dbClient *gorm.DB) repository.UserRepository {
+	return &UserRepository{
+		DB: dbClient,
+	}
+}
+
+func (u UserRepository) Create(username, password string, email *string) (*entity.User, error) {
+	user := entity.User{
+		Username: username,
+		Email:    email,
+		Password: password,
+	}
+	result := u.DB.Create(&user)
+	if isDuplicateKey(result.Error) {
+		// someone registered the name since the caller checked
+		return nil, repository.ErrUsernameTaken
+	}
+	return &user, result.Error
+}
+
+func (u UserRepository) GetByUsername(username string) (*entity.User, error) {
+	var user entity.User
+	result := u.DB.Where("username = ?", username).First(&user)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+
+	return &user, result.Error
+}
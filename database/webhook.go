@@ -0,0 +1,174 @@
+package database
+
+import (
+	"encoding/json"
+	"errors"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+	"time"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+type WebhookRepository struct {
+	DB *gorm.DB
+}
+
+func NewWebhookRepository(dbClient *gorm.DB) repository.WebhookRepository {
+	return &WebhookRepository{
+		DB: dbClient,
+	}
+}
+
+func (w WebhookRepository) GetAll(userID int64) ([]entity.Webhook, error) {
+	var webhooks []entity.Webhook
+	result := w.DB.Where("user_id = ?", userID).Order("id").Find(&webhooks)
+	return webhooks, result.Error
+}
+
+func (w WebhookRepository) GetByID(userID, webhookID int64) (*entity.Webhook, error) {
+	var webhook entity.Webhook
+	result := w.DB.Where("id = ? AND user_id = ?", webhookID, userID).First(&webhook)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	return &webhook, result.Error
+}
+
+func (w WebhookRepository) Create(webhook *entity.Webhook) (*entity.Webhook, error) {
+	result := w.DB.Create(webhook)
+	return webhook, result.Error
+}
+
+// Update changes the webhook's URL, events or active flag and returns it as
+// stored. A missing webhook yields a nil webhook.
+func (w WebhookRepository) Update(userID, webhookID int64, updates map[string]interface{}) (*entity.Webhook, error) {
+	// map updates bypass the JSON serializer of the events column
+	if events, ok := updates["events"].([]string); ok {
+		encoded, err := json.Marshal(events)
+		if err != nil {
+			return nil, err
+		}
+		updates["events"] = string(encoded)
+	}
+
+	var webhook entity.Webhook
+	err := w.DB.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND user_id = ?", webhookID, userID).First(&webhook).Error; err != nil {
+			return err
+		}
+		if len(updates) == 0 {
+			return nil
+		}
+		if err := tx.Model(&webhook).Updates(updates).Error; err != nil {
+			return err
+		}
+		return tx.First(&webhook, webhook.ID).Error
+	})
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// Delete removes the webhook together with its delivery log.
+func (w WebhookRepository) Delete(userID, webhookID int64) (int64, error) {
+	result := w.DB.Where("id = ? AND user_id = ?", webhookID, userID).Delete(&entity.Webhook{})
+	return result.RowsAffected, result.Error
+}
+
+// Enqueue queues the payload for every active webhook of the user that
+// subscribes to event, due immediately, and returns how many were queued.
+// Webhooks that already have a delivery of eventID are skipped, so the
+// outbox relay can publish an event again without repeating deliveries.
+func (w WebhookRepository) Enqueue(userID int64, eventID, event, payload string) (int64, error) {
+	var webhooks []entity.Webhook
+	if err := w.DB.Where("user_id = ? AND active = ?", userID, true).Find(&webhooks).Error; err != nil {
+		return 0, err
+	}
+
+	now := time.Now()
+	var deliveries []entity.WebhookDelivery
+	for _, webhook := range webhooks {
+		if webhook.Subscribes(event) {
+			deliveries = append(deliveries, entity.WebhookDelivery{
+				WebhookID:     webhook.ID,
+				EventID:       &eventID,
+				Event:         event,
+				Payload:       payload,
+				Status:        entity.DeliveryPending,
+				NextAttemptAt: &now,
+			})
+		}
+	}
+	if len(deliveries) == 0 {
+		return 0, nil
+	}
+	result := w.DB.Clauses(clause.OnConflict{DoNothing: true}).Create(&deliveries)
+	return result.RowsAffected, result.Error
+}
+
+// Deliveries returns the latest deliveries of the user's webhook, newest
+// first. A missing webhook yields no deliveries.
+func (w WebhookRepository) Deliveries(userID, webhookID int64, limit int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	result := w.DB.
+		Joins("JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id").
+		Where("webhook_deliveries.webhook_id = ? AND webhooks.user_id = ?", webhookID, userID).
+		Order("webhook_deliveries.id DESC").
+		Limit(limit).
+		Find(&deliveries)
+	return deliveries, result.Error
+}
+
+// Replay queues a fresh delivery with the payload of an earlier one, whatever
+// its outcome was. A missing delivery yields nil.
+func (w WebhookRepository) Replay(userID, webhookID, deliveryID int64) (*entity.WebhookDelivery, error) {
+	var original entity.WebhookDelivery
+	result := w.DB.
+		Joins("JOIN webhooks ON webhooks.id = webhook_deliveries.webhook_id").
+		Where("webhook_deliveries.id = ? AND webhook_deliveries.webhook_id = ? AND webhooks.user_id = ?", deliveryID, webhookID, userID).
+		First(&original)
+	if errors.Is(result.Error, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if result.Error != nil {
+		return nil, result.Error
+	}
+
+	now := time.Now()
+	replay := entity.WebhookDelivery{
+		WebhookID:     original.WebhookID,
+		Event:         original.Event,
+		Payload:       original.Payload,
+		Status:        entity.DeliveryPending,
+		NextAttemptAt: &now,
+	}
+	if err := w.DB.Create(&replay).Error; err != nil {
+		return nil, err
+	}
+	return &replay, nil
+}
+
+// DueDeliveries returns up to limit pending deliveries, of every user, whose
+// next attempt is due by now, oldest first and with their webhook loaded.
+func (w WebhookRepository) DueDeliveries(now time.Time, limit int) ([]entity.WebhookDelivery, error) {
+	var deliveries []entity.WebhookDelivery
+	result := w.DB.Preload("Webhook").
+		Where("status = ? AND next_attempt_at <= ?", entity.DeliveryPending, now).
+		Order("next_attempt_at").
+		Order("id").
+		Limit(limit).
+		Find(&deliveries)
+	return deliveries, result.Error
+}
+
+// SaveAttempt stores the outcome of a delivery attempt.
+func (w WebhookRepository) SaveAttempt(delivery *entity.WebhookDelivery) error {
+	return w.DB.Model(delivery).Select(
+		"status", "attempts", "response_code", "last_error", "next_attempt_at", "delivered_at",
+	).Updates(delivery).Error
+}
@@ -2,15 +2,29 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"github.com/gin-gonic/gin"
 	"github.com/kelseyhightower/envconfig"
 	log "github.com/sirupsen/logrus"
 	"io"
+	"net/http"
 	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
 	"todoGin/config"
 	"todoGin/database"
+	"todoGin/notifier"
+	"todoGin/outbox"
+	"todoGin/repository"
 	"todoGin/router"
 	"todoGin/service"
+	"todoGin/storage"
+	"todoGin/stream"
+	"todoGin/webhook"
+	"todoGin/worker"
 )
 
 func setupLogOutput() {
@@ -18,11 +32,50 @@ func setupLogOutput() {
 	gin.DefaultWriter = io.MultiWriter(f, os.Stdout)
 }
 
+func newNotifier(cfg *config.Config) (notifier.Notifier, error) {
+	switch cfg.ReminderNotifier {
+	case "log":
+		return notifier.NewLogNotifier(), nil
+	case "smtp":
+		return notifier.NewSMTPNotifier(cfg.SMTPAddr, cfg.SMTPUsername, cfg.SMTPPassword, cfg.SMTPFrom), nil
+	case "webhook":
+		if cfg.ReminderWebhookURL == "" {
+			return nil, errors.New("REMINDER_WEBHOOK_URL is required for the webhook notifier")
+		}
+		return notifier.NewWebhookNotifier(cfg.ReminderWebhookURL, cfg.ReminderTimeout), nil
+	}
+	return nil, fmt.Errorf("unknown REMINDER_NOTIFIER %q", cfg.ReminderNotifier)
+}
+
+// newOutboxSink returns the sink OUTBOX_SINK picks, or nil for none.
+func newOutboxSink(cfg *config.Config) (outbox.Sink, error) {
+	switch cfg.OutboxSink {
+	case "none":
+		return nil, nil
+	case "stdout":
+		return outbox.NewStdoutSink(), nil
+	case "file":
+		sink, err := outbox.NewFileSink(cfg.OutboxFile)
+		if err != nil {
+			return nil, err
+		}
+		return sink, nil
+	case "http":
+		if cfg.OutboxURL == "" {
+			return nil, errors.New("OUTBOX_URL is required for the http sink")
+		}
+		return outbox.NewHTTPSink(cfg.OutboxURL, &http.Client{Timeout: cfg.OutboxTimeout}), nil
+	}
+	return nil, fmt.Errorf("unknown OUTBOX_SINK %q", cfg.OutboxSink)
+}
+
 func main() {
 
 	setupLogOutput()
 
-	ctx := context.Background()
+	// cancelled on SIGINT/SIGTERM, which starts the graceful shutdown
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	log.SetFormatter(&log.JSONFormatter{})
 	log.SetLevel(log.InfoLevel)
@@ -34,6 +87,9 @@ func main() {
 	if err1 != nil {
 		log.Fatal("error", err1)
 	}
+	if cfg.JWTSecret == "" {
+		log.Fatal("JWT_SECRET is required")
+	}
 	// INITAL DATABASE
 	db, err := database.DatabaseInit(ctx, &cfg)
 	if err != nil {
@@ -47,13 +103,86 @@ func main() {
 
 	// initial repo
 	todoRepo := database.NewTodoRepository(db)
+	listRepo := database.NewListRepository(db)
+	subtaskRepo := database.NewSubtaskRepository(db)
+	tagRepo := database.NewTagRepository(db)
+	commentRepo := database.NewCommentRepository(db)
+	attachmentRepo := database.NewAttachmentRepository(db)
+	webhookRepo := database.NewWebhookRepository(db)
+	activityRepo := database.NewActivityRepository(db)
+	outboxRepo := database.NewOutboxRepository(db)
+	userRepo := database.NewUserRepository(db)
 	todoService := service.NewTodoService(todoRepo)
-	routeBuilder := router.NewRouteBuilder(todoService)
-	routeInit := routeBuilder.RouteInit()
-	//routeInit.Use(middleware.NewAuthMiddleware)
-	err = routeInit.Run(":8080")
+	listService := service.NewListService(listRepo, todoRepo)
+	subtaskService := service.NewSubtaskService(subtaskRepo)
+	tagService := service.NewTagService(tagRepo)
+	commentService := service.NewCommentService(commentRepo)
+	blobStore, err := storage.NewLocalStore(cfg.AttachmentDir)
+	if err != nil {
+		log.Fatal(err)
+	}
+	attachmentService := service.NewAttachmentService(attachmentRepo, blobStore, cfg.AttachmentMaxSize)
+	webhookService := service.NewWebhookService(webhookRepo)
+	activityService := service.NewActivityService(activityRepo, todoRepo, cfg.UndoWindow)
+	authService := service.NewAuthService(userRepo, cfg.JWTSecret, cfg.JWTExpiresIn)
+	purger := worker.NewPurger(todoRepo, time.Duration(cfg.PurgeAfterDays)*24*time.Hour, cfg.PurgeInterval)
+	sweeper := worker.NewAttachmentSweeper(attachmentRepo, blobStore, cfg.PurgeInterval)
+	reminderNotifier, err := newNotifier(&cfg)
 	if err != nil {
 		log.Fatal(err)
 	}
+	reminders := worker.NewReminderScheduler(todoRepo, reminderNotifier, cfg.ReminderLead, cfg.ReminderInterval, cfg.ReminderTimeout, cfg.ReminderBackoff, cfg.ReminderMaxBackoff)
+	deliverer := worker.NewWebhookDeliverer(webhookRepo, cfg.WebhookTimeout, cfg.WebhookInterval, cfg.WebhookMaxAttempts, cfg.WebhookBackoff, cfg.WebhookMaxBackoff)
+	broker := stream.NewBroker(cfg.StreamBuffer)
+	streamService := service.NewStreamService(broker, cfg.StreamHeartbeat)
+	hub := stream.NewHub()
+	wsService := service.NewWSService(todoRepo, listRepo, hub, cfg.WSAllowedOrigins)
+	// the in-process sinks get a relay of their own, so that an external
+	// sink being down does not hold back streams, WebSockets and webhooks
+	local := outbox.NewMultiSink(broker, hub, webhook.NewDispatcher(webhookRepo))
+	external, err := newOutboxSink(&cfg)
+	if err != nil {
+		log.Fatal(err)
+	}
+	runs := []func(context.Context){purger.Run, sweeper.Run, reminders.Run, deliverer.Run}
+	channels := []string{repository.OutboxLocal}
+	if external != nil {
+		channels = append(channels, repository.OutboxExternal)
+		// pruning is left to the local relay, which waits for both channels
+		externalRelay := worker.NewOutboxRelay(outboxRepo, repository.OutboxExternal, external, cfg.OutboxInterval, cfg.OutboxTimeout, cfg.OutboxMaxAttempts, cfg.OutboxBackoff, cfg.OutboxMaxBackoff, 0, nil)
+		runs = append(runs, externalRelay.Run)
+	}
+	relay := worker.NewOutboxRelay(outboxRepo, repository.OutboxLocal, local, cfg.OutboxInterval, cfg.OutboxTimeout, cfg.OutboxMaxAttempts, cfg.OutboxBackoff, cfg.OutboxMaxBackoff, cfg.OutboxRetention, channels)
+	runs = append(runs, relay.Run)
+
+	var workers sync.WaitGroup
+	for _, run := range runs {
+		workers.Add(1)
+		go func(run func(context.Context)) {
+			defer workers.Done()
+			run(ctx)
+		}(run)
+	}
+
+	routeBuilder := router.NewRouteBuilder(todoService, listService, subtaskService, tagService, commentService, attachmentService, webhookService, activityService, streamService, wsService, authService)
+	routeInit := routeBuilder.RouteInit()
+	server := &http.Server{Addr: ":8080", Handler: routeInit}
+	// open streams would otherwise keep Shutdown waiting until it times out
+	server.RegisterOnShutdown(broker.Close)
+	server.RegisterOnShutdown(hub.Close)
+	go func() {
+		if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			log.Fatal(err)
+		}
+	}()
+
+	<-ctx.Done()
+	log.Info("shutting down")
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), cfg.ShutdownTimeout)
+	defer cancel()
+	if err := server.Shutdown(shutdownCtx); err != nil {
+		log.Errorf("failed when shutting down server: %v", err)
+	}
+	workers.Wait()
 
 }
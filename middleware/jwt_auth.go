@@ -0,0 +1,91 @@
+package middleware
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/golang-jwt/jwt/v4"
+	"net/http"
+	"strings"
+	"time"
+	"todoGin/model/entity"
+)
+
+// UserIDKey is the gin context key holding the authenticated user's ID.
+const UserIDKey = "userID"
+
+type JWTClaims struct {
+	UserID   int64  `json:"user_id"`
+	Username string `json:"username"`
+	jwt.RegisteredClaims
+}
+
+// GenerateToken signs an HS256 access token for the given user.
+func GenerateToken(user *entity.User, secret string, ttl time.Duration) (string, time.Time, error) {
+	expiresAt := time.Now().Add(ttl)
+	claims := JWTClaims{
+		UserID:   user.ID,
+		Username: user.Username,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Subject:   fmt.Sprint(user.ID),
+			IssuedAt:  jwt.NewNumericDate(time.Now()),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+	token, err := jwt.NewWithClaims(jwt.SigningMethodHS256, claims).SignedString([]byte(secret))
+	return token, expiresAt, err
+}
+
+func JWTAuth(secret string) gin.HandlerFunc {
+	return func(ctx *gin.Context) {
+		tokenString, ok := bearerToken(ctx)
+		if !ok {
+			unauthorized(ctx)
+			return
+		}
+
+		claims := new(JWTClaims)
+		token, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+			if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
+				return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
+			}
+			return []byte(secret), nil
+		})
+		if err != nil || !token.Valid {
+			unauthorized(ctx)
+			return
+		}
+
+		ctx.Set(UserIDKey, claims.UserID)
+		ctx.Next()
+	}
+}
+
+// WebSocketTokenPrefix marks the Sec-WebSocket-Protocol value carrying the
+// token of a WebSocket handshake, since browsers cannot set Authorization on
+// one.
+const WebSocketTokenPrefix = "bearer."
+
+// bearerToken reads the token from "Authorization: Bearer <token>" or, for a
+// WebSocket handshake, from a "bearer.<token>" subprotocol.
+func bearerToken(ctx *gin.Context) (string, bool) {
+	authHeader := ctx.GetHeader("Authorization")
+	if token := strings.TrimPrefix(authHeader, "Bearer "); authHeader != "" && token != authHeader {
+		return token, true
+	}
+	if !strings.EqualFold(ctx.GetHeader("Upgrade"), "websocket") {
+		return "", false
+	}
+	for _, protocol := range strings.Split(ctx.GetHeader("Sec-WebSocket-Protocol"), ",") {
+		if token := strings.TrimPrefix(strings.TrimSpace(protocol), WebSocketTokenPrefix); token != strings.TrimSpace(protocol) && token != "" {
+			return token, true
+		}
+	}
+	return "", false
+}
+
+func unauthorized(ctx *gin.Context) {
+	ctx.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{
+		"status":  http.StatusUnauthorized,
+		"message": "UNAUTHORIZED",
+	})
+}
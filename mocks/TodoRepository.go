@@ -3,8 +3,10 @@
 package mocks
 
 import (
-	entity "todoGin/model/entity"
 	"github.com/stretchr/testify/mock"
+	time "time"
+	entity "todoGin/model/entity"
+	repository "todoGin/repository"
 )
 
 // TodoRepository is an autogenerated mock type for the TodoRepository type
@@ -12,25 +14,51 @@ type TodoRepository struct {
 	mock.Mock
 }
 
-// Create provides a mock function with given fields: title
-func (_m *TodoRepository) Create(title string) (*entity.Todolist, error) {
-	ret := _m.Called(title)
+// Bulk provides a mock function with given fields: userID, ops, atomic
+func (_m *TodoRepository) Bulk(userID int64, ops []repository.BulkOperation, atomic bool) ([]repository.BulkResult, error) {
+	ret := _m.Called(userID, ops, atomic)
+
+	var r0 []repository.BulkResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, []repository.BulkOperation, bool) ([]repository.BulkResult, error)); ok {
+		return rf(userID, ops, atomic)
+	}
+	if rf, ok := ret.Get(0).(func(int64, []repository.BulkOperation, bool) []repository.BulkResult); ok {
+		r0 = rf(userID, ops, atomic)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]repository.BulkResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, []repository.BulkOperation, bool) error); ok {
+		r1 = rf(userID, ops, atomic)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Create provides a mock function with given fields: todo
+func (_m *TodoRepository) Create(todo *entity.Todolist) (*entity.Todolist, error) {
+	ret := _m.Called(todo)
 
 	var r0 *entity.Todolist
 	var r1 error
-	if rf, ok := ret.Get(0).(func(string) (*entity.Todolist, error)); ok {
-		return rf(title)
+	if rf, ok := ret.Get(0).(func(*entity.Todolist) (*entity.Todolist, error)); ok {
+		return rf(todo)
 	}
-	if rf, ok := ret.Get(0).(func(string) *entity.Todolist); ok {
-		r0 = rf(title)
+	if rf, ok := ret.Get(0).(func(*entity.Todolist) *entity.Todolist); ok {
+		r0 = rf(todo)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.Todolist)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(string) error); ok {
-		r1 = rf(title)
+	if rf, ok := ret.Get(1).(func(*entity.Todolist) error); ok {
+		r1 = rf(todo)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -38,23 +66,23 @@ func (_m *TodoRepository) Create(title string) (*entity.Todolist, error) {
 	return r0, r1
 }
 
-// Delete provides a mock function with given fields: todoID
-func (_m *TodoRepository) Delete(todoID int64) (int64, error) {
-	ret := _m.Called(todoID)
+// Delete provides a mock function with given fields: userID, todoID, version
+func (_m *TodoRepository) Delete(userID int64, todoID int64, version int64) (int64, error) {
+	ret := _m.Called(userID, todoID, version)
 
 	var r0 int64
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (int64, error)); ok {
-		return rf(todoID)
+	if rf, ok := ret.Get(0).(func(int64, int64, int64) (int64, error)); ok {
+		return rf(userID, todoID, version)
 	}
-	if rf, ok := ret.Get(0).(func(int64) int64); ok {
-		r0 = rf(todoID)
+	if rf, ok := ret.Get(0).(func(int64, int64, int64) int64); ok {
+		r0 = rf(userID, todoID, version)
 	} else {
 		r0 = ret.Get(0).(int64)
 	}
 
-	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(todoID)
+	if rf, ok := ret.Get(1).(func(int64, int64, int64) error); ok {
+		r1 = rf(userID, todoID, version)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -62,25 +90,25 @@ func (_m *TodoRepository) Delete(todoID int64) (int64, error) {
 	return r0, r1
 }
 
-// GetAll provides a mock function with given fields:
-func (_m *TodoRepository) GetAll() ([]entity.Todolist, error) {
-	ret := _m.Called()
+// DueSoon provides a mock function with given fields: now, before, limit
+func (_m *TodoRepository) DueSoon(now time.Time, before time.Time, limit int) ([]entity.Reminder, error) {
+	ret := _m.Called(now, before, limit)
 
-	var r0 []entity.Todolist
+	var r0 []entity.Reminder
 	var r1 error
-	if rf, ok := ret.Get(0).(func() ([]entity.Todolist, error)); ok {
-		return rf()
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, int) ([]entity.Reminder, error)); ok {
+		return rf(now, before, limit)
 	}
-	if rf, ok := ret.Get(0).(func() []entity.Todolist); ok {
-		r0 = rf()
+	if rf, ok := ret.Get(0).(func(time.Time, time.Time, int) []entity.Reminder); ok {
+		r0 = rf(now, before, limit)
 	} else {
 		if ret.Get(0) != nil {
-			r0 = ret.Get(0).([]entity.Todolist)
+			r0 = ret.Get(0).([]entity.Reminder)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func() error); ok {
-		r1 = rf()
+	if rf, ok := ret.Get(1).(func(time.Time, time.Time, int) error); ok {
+		r1 = rf(now, before, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -88,25 +116,82 @@ func (_m *TodoRepository) GetAll() ([]entity.Todolist, error) {
 	return r0, r1
 }
 
-// GetByID provides a mock function with given fields: todoID
-func (_m *TodoRepository) GetByID(todoID int64) (*entity.Todolist, error) {
-	ret := _m.Called(todoID)
+// GetAll provides a mock function with given fields: userID, query
+func (_m *TodoRepository) GetAll(userID int64, query repository.TodoQuery) ([]entity.Todolist, int64, error) {
+	ret := _m.Called(userID, query)
+
+	var r0 []entity.Todolist
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int64, repository.TodoQuery) ([]entity.Todolist, int64, error)); ok {
+		return rf(userID, query)
+	}
+	if rf, ok := ret.Get(0).(func(int64, repository.TodoQuery) []entity.Todolist); ok {
+		r0 = rf(userID, query)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Todolist)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, repository.TodoQuery) int64); ok {
+		r1 = rf(userID, query)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(int64, repository.TodoQuery) error); ok {
+		r2 = rf(userID, query)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// GetByID provides a mock function with given fields: userID, todoID
+func (_m *TodoRepository) GetByID(userID int64, todoID int64) (*entity.Todolist, error) {
+	ret := _m.Called(userID, todoID)
 
 	var r0 *entity.Todolist
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64) (*entity.Todolist, error)); ok {
-		return rf(todoID)
+	if rf, ok := ret.Get(0).(func(int64, int64) (*entity.Todolist, error)); ok {
+		return rf(userID, todoID)
 	}
-	if rf, ok := ret.Get(0).(func(int64) *entity.Todolist); ok {
-		r0 = rf(todoID)
+	if rf, ok := ret.Get(0).(func(int64, int64) *entity.Todolist); ok {
+		r0 = rf(userID, todoID)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.Todolist)
 		}
 	}
 
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(userID, todoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// LastModified provides a mock function with given fields: userID
+func (_m *TodoRepository) LastModified(userID int64) (time.Time, error) {
+	ret := _m.Called(userID)
+
+	var r0 time.Time
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64) (time.Time, error)); ok {
+		return rf(userID)
+	}
+	if rf, ok := ret.Get(0).(func(int64) time.Time); ok {
+		r0 = rf(userID)
+	} else {
+		r0 = ret.Get(0).(time.Time)
+	}
+
 	if rf, ok := ret.Get(1).(func(int64) error); ok {
-		r1 = rf(todoID)
+		r1 = rf(userID)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -114,25 +199,196 @@ func (_m *TodoRepository) GetByID(todoID int64) (*entity.Todolist, error) {
 	return r0, r1
 }
 
-// Update provides a mock function with given fields: todoID, updates
-func (_m *TodoRepository) Update(todoID int64, updates map[string]interface{}) (*entity.Todolist, error) {
-	ret := _m.Called(todoID, updates)
+// MarkReminded provides a mock function with given fields: todoID, dueDate, at
+func (_m *TodoRepository) MarkReminded(todoID int64, dueDate time.Time, at time.Time) (int64, error) {
+	ret := _m.Called(todoID, dueDate, at)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, time.Time, time.Time) (int64, error)); ok {
+		return rf(todoID, dueDate, at)
+	}
+	if rf, ok := ret.Get(0).(func(int64, time.Time, time.Time) int64); ok {
+		r0 = rf(todoID, dueDate, at)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, time.Time, time.Time) error); ok {
+		r1 = rf(todoID, dueDate, at)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkReminderFailed provides a mock function with given fields: todoID, dueDate, retryAt
+func (_m *TodoRepository) MarkReminderFailed(todoID int64, dueDate time.Time, retryAt time.Time) error {
+	ret := _m.Called(todoID, dueDate, retryAt)
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(int64, time.Time, time.Time) error); ok {
+		r0 = rf(todoID, dueDate, retryAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// Move provides a mock function with given fields: userID, todoID, version, anchorID, after
+func (_m *TodoRepository) Move(userID int64, todoID int64, version int64, anchorID int64, after bool) (*entity.Todolist, error) {
+	ret := _m.Called(userID, todoID, version, anchorID, after)
 
 	var r0 *entity.Todolist
 	var r1 error
-	if rf, ok := ret.Get(0).(func(int64, map[string]interface{}) (*entity.Todolist, error)); ok {
-		return rf(todoID, updates)
+	if rf, ok := ret.Get(0).(func(int64, int64, int64, int64, bool) (*entity.Todolist, error)); ok {
+		return rf(userID, todoID, version, anchorID, after)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64, int64, int64, bool) *entity.Todolist); ok {
+		r0 = rf(userID, todoID, version, anchorID, after)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Todolist)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64, int64, int64, bool) error); ok {
+		r1 = rf(userID, todoID, version, anchorID, after)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Occurrences provides a mock function with given fields: userID, from, to
+func (_m *TodoRepository) Occurrences(userID int64, from time.Time, to time.Time) ([]entity.Occurrence, error) {
+	ret := _m.Called(userID, from, to)
+
+	var r0 []entity.Occurrence
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, time.Time, time.Time) ([]entity.Occurrence, error)); ok {
+		return rf(userID, from, to)
+	}
+	if rf, ok := ret.Get(0).(func(int64, time.Time, time.Time) []entity.Occurrence); ok {
+		r0 = rf(userID, from, to)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.Occurrence)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, time.Time, time.Time) error); ok {
+		r1 = rf(userID, from, to)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Purge provides a mock function with given fields: deletedBefore
+func (_m *TodoRepository) Purge(deletedBefore time.Time) (int64, error) {
+	ret := _m.Called(deletedBefore)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(time.Time) (int64, error)); ok {
+		return rf(deletedBefore)
+	}
+	if rf, ok := ret.Get(0).(func(time.Time) int64); ok {
+		r0 = rf(deletedBefore)
+	} else {
+		r0 = ret.Get(0).(int64)
 	}
-	if rf, ok := ret.Get(0).(func(int64, map[string]interface{}) *entity.Todolist); ok {
-		r0 = rf(todoID, updates)
+
+	if rf, ok := ret.Get(1).(func(time.Time) error); ok {
+		r1 = rf(deletedBefore)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Restore provides a mock function with given fields: userID, todoID
+func (_m *TodoRepository) Restore(userID int64, todoID int64) (int64, error) {
+	ret := _m.Called(userID, todoID)
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, int64) (int64, error)); ok {
+		return rf(userID, todoID)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64) int64); ok {
+		r0 = rf(userID, todoID)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64) error); ok {
+		r1 = rf(userID, todoID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Revert provides a mock function with given fields: userID, todoID, revision, opts
+func (_m *TodoRepository) Revert(userID int64, todoID int64, revision int64, opts repository.UpdateOptions) (*entity.Todolist, int64, error) {
+	ret := _m.Called(userID, todoID, revision, opts)
+
+	var r0 *entity.Todolist
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int64, int64, int64, repository.UpdateOptions) (*entity.Todolist, int64, error)); ok {
+		return rf(userID, todoID, revision, opts)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64, int64, repository.UpdateOptions) *entity.Todolist); ok {
+		r0 = rf(userID, todoID, revision, opts)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).(*entity.Todolist)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(int64, map[string]interface{}) error); ok {
-		r1 = rf(todoID, updates)
+	if rf, ok := ret.Get(1).(func(int64, int64, int64, repository.UpdateOptions) int64); ok {
+		r1 = rf(userID, todoID, revision, opts)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(int64, int64, int64, repository.UpdateOptions) error); ok {
+		r2 = rf(userID, todoID, revision, opts)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
+// Search provides a mock function with given fields: userID, query, limit
+func (_m *TodoRepository) Search(userID int64, query string, limit int) ([]entity.TodoSearchResult, error) {
+	ret := _m.Called(userID, query, limit)
+
+	var r0 []entity.TodoSearchResult
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, string, int) ([]entity.TodoSearchResult, error)); ok {
+		return rf(userID, query, limit)
+	}
+	if rf, ok := ret.Get(0).(func(int64, string, int) []entity.TodoSearchResult); ok {
+		r0 = rf(userID, query, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]entity.TodoSearchResult)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, string, int) error); ok {
+		r1 = rf(userID, query, limit)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -140,6 +396,65 @@ func (_m *TodoRepository) Update(todoID int64, updates map[string]interface{}) (
 	return r0, r1
 }
 
+// Undo provides a mock function with given fields: userID, since
+func (_m *TodoRepository) Undo(userID int64, since time.Time) ([]int64, error) {
+	ret := _m.Called(userID, since)
+
+	var r0 []int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(int64, time.Time) ([]int64, error)); ok {
+		return rf(userID, since)
+	}
+	if rf, ok := ret.Get(0).(func(int64, time.Time) []int64); ok {
+		r0 = rf(userID, since)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]int64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, time.Time) error); ok {
+		r1 = rf(userID, since)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// Update provides a mock function with given fields: userID, todoID, opts, updates
+func (_m *TodoRepository) Update(userID int64, todoID int64, opts repository.UpdateOptions, updates map[string]interface{}) (*entity.Todolist, int64, error) {
+	ret := _m.Called(userID, todoID, opts, updates)
+
+	var r0 *entity.Todolist
+	var r1 int64
+	var r2 error
+	if rf, ok := ret.Get(0).(func(int64, int64, repository.UpdateOptions, map[string]interface{}) (*entity.Todolist, int64, error)); ok {
+		return rf(userID, todoID, opts, updates)
+	}
+	if rf, ok := ret.Get(0).(func(int64, int64, repository.UpdateOptions, map[string]interface{}) *entity.Todolist); ok {
+		r0 = rf(userID, todoID, opts, updates)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*entity.Todolist)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(int64, int64, repository.UpdateOptions, map[string]interface{}) int64); ok {
+		r1 = rf(userID, todoID, opts, updates)
+	} else {
+		r1 = ret.Get(1).(int64)
+	}
+
+	if rf, ok := ret.Get(2).(func(int64, int64, repository.UpdateOptions, map[string]interface{}) error); ok {
+		r2 = rf(userID, todoID, opts, updates)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 type mockConstructorTestingTNewTodoRepository interface {
 	mock.TestingT
 	Cleanup(func())
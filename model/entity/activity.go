@@ -0,0 +1,51 @@
+package entity
+
+import "time"
+
+// Actions of the activity log.
+const (
+	ActivityCreated           = "created"
+	ActivityUpdated           = "updated"
+	ActivityDeleted           = "deleted"
+	ActivityRestored          = "restored"
+	ActivityMoved             = "moved"
+	ActivityUnlisted          = "unlisted"
+	ActivitySubtaskAdded      = "subtask_added"
+	ActivitySubtaskUpdated    = "subtask_updated"
+	ActivitySubtaskRemoved    = "subtask_removed"
+	ActivitySubtasksReordered = "subtasks_reordered"
+	ActivityTagged            = "tagged"
+	ActivityUntagged          = "untagged"
+	ActivityCommented         = "commented"
+	ActivityCommentEdited     = "comment_edited"
+	ActivityCommentDeleted    = "comment_deleted"
+	ActivityAttached          = "attached"
+	ActivityDetached          = "detached"
+)
+
+// Activity is one entry of the append-only audit log, written in the same
+// transaction as the change to the todo it describes. UserID is the actor;
+// todos are private, so it is their owner too. Revision is the todo's
+// version after the change. BatchID is shared by the entries of one request,
+// such as a bulk one, which are undone together. Entries are kept when the
+// todo is purged.
+type Activity struct {
+	ID        int64     `gorm:"primaryKey" json:"id"`
+	UserID    int64     `json:"user_id"`
+	TodoID    int64     `json:"todo_id"`
+	Revision  int64     `json:"revision"`
+	BatchID   string    `gorm:"type:char(36)" json:"batch_id"`
+	Action    string    `gorm:"type:varchar(50)" json:"action"`
+	Changes   Changes   `gorm:"type:text;serializer:json" json:"changes"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// Changes maps the changed fields to their values before and after. Changes
+// to a subtask are keyed "subtask.<field>", to a comment "comment.<field>",
+// to an attachment "attachment.<field>", and to the tags "tags".
+type Changes map[string]FieldChange
+
+type FieldChange struct {
+	From interface{} `json:"from"`
+	To   interface{} `json:"to"`
+}
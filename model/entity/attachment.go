@@ -0,0 +1,18 @@
+package entity
+
+import "time"
+
+// Attachment is a file attached to a todo. Its content is kept in a blob
+// store under StorageKey, which clients never see; Checksum is the hex
+// SHA-256 of the content and ContentType what the server sniffed from it.
+type Attachment struct {
+	ID          int64     `gorm:"primaryKey" json:"id"`
+	TodoID      int64     `gorm:"index" json:"todo_id"`
+	UserID      int64     `json:"user_id"`
+	Filename    string    `gorm:"type:varchar(255)" json:"filename"`
+	ContentType string    `gorm:"type:varchar(100)" json:"content_type"`
+	Size        int64     `json:"size"`
+	Checksum    string    `gorm:"type:char(64)" json:"sha256"`
+	StorageKey  string    `gorm:"type:varchar(100);uniqueIndex" json:"-"`
+	CreatedAt   time.Time `json:"created_at"`
+}
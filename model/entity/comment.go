@@ -0,0 +1,16 @@
+package entity
+
+import "time"
+
+// Comment is a message in the discussion of a todo. Body is markdown, stored
+// and returned as written; rendering it is up to the client. Author is the
+// username of UserID, filled in when comments are read.
+type Comment struct {
+	ID        int64     `gorm:"primaryKey" json:"id"`
+	TodoID    int64     `gorm:"index" json:"todo_id"`
+	UserID    int64     `json:"user_id"`
+	Author    string    `gorm:"->;-:migration" json:"author"`
+	Body      string    `gorm:"type:text" json:"body"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// List is a named backlog that groups a user's todos. Archived lists are
+// read-only and hidden from the default listing.
+type List struct {
+	ID        int64     `gorm:"primaryKey" json:"id"`
+	UserID    int64     `gorm:"index" json:"user_id"`
+	Name      string    `gorm:"type:varchar(100)" json:"name"`
+	Archived  bool      `gorm:"default:false" json:"archived"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,34 @@
+package entity
+
+import "time"
+
+// OutboxEvent is a todo event stored in the same transaction as the change
+// that caused it and published afterwards by the outbox relays. Publishing is
+// at-least-once, so consumers drop repeats by EventID. Attempts, LastError,
+// PublishedAt and FailedAt track the local channel, the External ones
+// OUTBOX_SINK. FailedAt is set once a channel has given up on the event.
+type OutboxEvent struct {
+	ID                  int64      `gorm:"primaryKey" json:"-"`
+	EventID             string     `gorm:"type:char(36);uniqueIndex" json:"event_id"`
+	UserID              int64      `json:"user_id"`
+	Event               string     `gorm:"type:varchar(50)" json:"event"`
+	Payload             string     `gorm:"type:mediumtext" json:"payload"`
+	Attempts            int        `json:"attempts"`
+	LastError           *string    `gorm:"type:text" json:"last_error"`
+	PublishedAt         *time.Time `json:"published_at"`
+	FailedAt            *time.Time `json:"failed_at"`
+	ExternalAttempts    int        `json:"external_attempts"`
+	ExternalLastError   *string    `gorm:"type:text" json:"external_last_error"`
+	ExternalPublishedAt *time.Time `json:"external_published_at"`
+	ExternalFailedAt    *time.Time `json:"external_failed_at"`
+	CreatedAt           time.Time  `json:"created_at"`
+}
+
+// EventPayload is the JSON document every published event carries, whatever
+// the sink.
+type EventPayload struct {
+	ID         string      `json:"id"`
+	Event      string      `json:"event"`
+	OccurredAt time.Time   `json:"occurred_at"`
+	Data       interface{} `json:"data"`
+}
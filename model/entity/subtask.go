@@ -0,0 +1,14 @@
+package entity
+
+import "time"
+
+// Subtask is a checklist item of a todo, kept in Position order.
+type Subtask struct {
+	ID        int64     `gorm:"primaryKey" json:"id"`
+	TodoID    int64     `gorm:"index" json:"todo_id"`
+	Title     string    `gorm:"type:varchar(300)" json:"title"`
+	Status    bool      `gorm:"default:false" json:"status"`
+	Position  int       `json:"position"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
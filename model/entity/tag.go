@@ -0,0 +1,23 @@
+package entity
+
+import "time"
+
+// Tag is a label a user attaches to any number of their todos.
+type Tag struct {
+	ID        int64     `gorm:"primaryKey" json:"id"`
+	UserID    int64     `gorm:"index" json:"user_id"`
+	Name      string    `gorm:"type:varchar(50)" json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// TodoTag is a row of the todo_tags join table.
+type TodoTag struct {
+	TodoID int64 `gorm:"primaryKey"`
+	TagID  int64 `gorm:"primaryKey"`
+}
+
+// TagCount is a tag with the number of live todos carrying it.
+type TagCount struct {
+	Tag
+	TodoCount int64 `json:"todo_count"`
+}
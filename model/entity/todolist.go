@@ -1,9 +1,69 @@
 package entity
 
+import (
+	"gorm.io/gorm"
+	"time"
+)
+
+const (
+	PriorityLow    = "low"
+	PriorityMedium = "medium"
+	PriorityHigh   = "high"
+	PriorityUrgent = "urgent"
+)
+
 type Todolist struct {
-	ID     int64  `gorm:"primaryKey" json:"id"`
-	Title  string `gorm:"type:varchar(300)" json:"title"`
-	Status bool   `gorm:"default:false" json:"status"`
+	ID          int64          `gorm:"primaryKey" json:"id"`
+	UserID      int64          `gorm:"index" json:"user_id"`
+	ListID      *int64         `gorm:"index" json:"list_id"`
+	Title       string         `gorm:"type:varchar(300)" json:"title"`
+	Description *string        `gorm:"type:text" json:"description"`
+	Status      bool           `gorm:"default:false" json:"status"`
+	DueDate     *time.Time     `json:"due_date"`
+	Priority    string         `gorm:"type:enum('low','medium','high','urgent');default:medium" json:"priority"`
+	CompletedAt *time.Time     `json:"completed_at"`
+	Recurrence  *string        `gorm:"type:varchar(255)" json:"recurrence"`
+	RemindedAt  *time.Time     `json:"-"`
+	RemindTries int            `gorm:"not null;default:0" json:"-"`
+	RemindAfter *time.Time     `json:"-"`
+	Position    float64        `gorm:"not null;default:0" json:"position"`
+	Version     int64          `gorm:"not null;default:1" json:"version"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"deleted_at"`
+	Subtasks    []Subtask      `gorm:"foreignKey:TodoID" json:"subtasks,omitempty"`
+	Tags        []Tag          `gorm:"many2many:todo_tags;joinForeignKey:TodoID;joinReferences:TagID" json:"tags,omitempty"`
+	// CommentCount is only filled in by listings
+	CommentCount *int64 `gorm:"->;-:migration" json:"comment_count,omitempty"`
+}
+
+// TodoSearchResult is a todo matched by a full-text search together with its
+// MySQL relevance score.
+type TodoSearchResult struct {
+	Todolist
+	Relevance float64 `json:"relevance"`
+}
+
+// Occurrence is one due date of an open todo, either the todo itself or a
+// future repetition of a recurring one that has not been generated yet.
+type Occurrence struct {
+	TodoID    int64     `json:"todo_id"`
+	Title     string    `json:"title"`
+	DueDate   time.Time `json:"due_date"`
+	Projected bool      `json:"projected"`
+}
+
+// Reminder is an open todo whose due date is close, with the username of its
+// owner, as handed to a notifier.
+type Reminder struct {
+	TodoID   int64     `json:"todo_id"`
+	UserID   int64     `json:"user_id"`
+	Username string    `json:"username"`
+	Email    *string   `json:"email"`
+	Title    string    `json:"title"`
+	DueDate  time.Time `json:"due_date"`
+	// Attempts counts the failed tries to send the reminder so far.
+	Attempts int `json:"-"`
 }
 
 //func (t Todolist) Read(p []byte) (n int, err error) {
@@ -0,0 +1,12 @@
+package entity
+
+import "time"
+
+type User struct {
+	ID        int64     `gorm:"primaryKey" json:"id"`
+	Username  string    `gorm:"type:varchar(100);uniqueIndex" json:"username"`
+	Email     *string   `gorm:"type:varchar(254)" json:"email"`
+	Password  string    `gorm:"type:varchar(255)" json:"-"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
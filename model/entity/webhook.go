@@ -0,0 +1,65 @@
+package entity
+
+import "time"
+
+// Todo lifecycle events a webhook can subscribe to.
+const (
+	EventTodoCreated   = "todo.created"
+	EventTodoUpdated   = "todo.updated"
+	EventTodoCompleted = "todo.completed"
+	EventTodoDeleted   = "todo.deleted"
+	EventTodoRestored  = "todo.restored"
+)
+
+const (
+	DeliveryPending   = "pending"
+	DeliverySucceeded = "succeeded"
+	DeliveryFailed    = "failed"
+)
+
+// Webhook is a URL the owner's todo events are POSTed to, signed with
+// Secret. An empty Events list subscribes to every event.
+type Webhook struct {
+	ID        int64     `gorm:"primaryKey" json:"id"`
+	UserID    int64     `gorm:"index" json:"user_id"`
+	URL       string    `gorm:"type:varchar(2048)" json:"url"`
+	Secret    string    `gorm:"type:varchar(64)" json:"-"`
+	Events    []string  `gorm:"type:varchar(512);serializer:json" json:"events"`
+	Active    bool      `gorm:"default:true" json:"active"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Subscribes reports whether the webhook wants the event.
+func (w *Webhook) Subscribes(event string) bool {
+	if len(w.Events) == 0 {
+		return true
+	}
+	for _, e := range w.Events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+// WebhookDelivery is one attempt series at POSTing an event to a webhook.
+// A pending delivery is retried at NextAttemptAt until it succeeds or runs
+// out of attempts. EventID is the outbox event it was queued for; replays
+// have none.
+type WebhookDelivery struct {
+	ID            int64      `gorm:"primaryKey" json:"id"`
+	WebhookID     int64      `gorm:"index" json:"webhook_id"`
+	EventID       *string    `gorm:"type:char(36)" json:"event_id"`
+	Event         string     `gorm:"type:varchar(50)" json:"event"`
+	Payload       string     `gorm:"type:text" json:"payload"`
+	Status        string     `gorm:"type:enum('pending','succeeded','failed');default:pending" json:"status"`
+	Attempts      int        `json:"attempts"`
+	ResponseCode  *int       `json:"response_code"`
+	LastError     *string    `gorm:"type:text" json:"last_error"`
+	NextAttemptAt *time.Time `json:"next_attempt_at"`
+	DeliveredAt   *time.Time `json:"delivered_at"`
+	CreatedAt     time.Time  `json:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at"`
+	Webhook       *Webhook   `json:"-"`
+}
@@ -0,0 +1,13 @@
+package request
+
+import "todoGin/model/entity"
+
+type ActivitiesResponse struct {
+	Message    string            `json:"message"`
+	Data       int               `json:"data"`
+	Total      int64             `json:"total"`
+	Limit      int               `json:"limit"`
+	Offset     int               `json:"offset"`
+	NextOffset *int              `json:"next_offset"`
+	Activities []entity.Activity `json:"activities"`
+}
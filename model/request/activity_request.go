@@ -0,0 +1,21 @@
+package request
+
+// ActivityQueryRequest pages through the activity log, newest first.
+type ActivityQueryRequest struct {
+	Limit  int `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset int `form:"offset" binding:"omitempty,min=0"`
+}
+
+// Page returns the limit and offset to read, defaulting to DefaultPageLimit.
+func (r *ActivityQueryRequest) Page() (int, int) {
+	if r.Limit == 0 {
+		return DefaultPageLimit, r.Offset
+	}
+	return r.Limit, r.Offset
+}
+
+// TodolistRevertRequest holds the query string of
+// POST /manage-todo/todo/:id/revert.
+type TodolistRevertRequest struct {
+	To int64 `form:"to" binding:"required,min=1"`
+}
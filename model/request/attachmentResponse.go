@@ -0,0 +1,15 @@
+package request
+
+import "todoGin/model/entity"
+
+type AttachmentResponse struct {
+	Status  int               `json:"status"`
+	Message string            `json:"message"`
+	Data    entity.Attachment `json:"data"`
+}
+
+type AttachmentsResponse struct {
+	Message     string              `json:"message"`
+	Data        int                 `json:"data"`
+	Attachments []entity.Attachment `json:"attachments"`
+}
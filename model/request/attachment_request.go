@@ -0,0 +1,11 @@
+package request
+
+import "mime/multipart"
+
+// AttachmentUploadRequest is the multipart form of an upload. SHA256, when
+// given, is the hex checksum the client computed; the upload is refused if
+// the content that arrived does not match it.
+type AttachmentUploadRequest struct {
+	File   *multipart.FileHeader `form:"file" binding:"required"`
+	SHA256 string                `form:"sha256" binding:"omitempty,len=64,hexadecimal"`
+}
@@ -0,0 +1,19 @@
+package request
+
+import (
+	"time"
+	"todoGin/model/entity"
+)
+
+type UserResponse struct {
+	Status  int         `json:"status"`
+	Message string      `json:"message"`
+	Data    entity.User `json:"data"`
+}
+
+type LoginResponse struct {
+	Status    int       `json:"status"`
+	Message   string    `json:"message"`
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
@@ -0,0 +1,12 @@
+package request
+
+type RegisterRequest struct {
+	Username string  `json:"username" binding:"required,min=3,max=100"`
+	Password string  `json:"password" binding:"required,min=8,max=72"`
+	Email    *string `json:"email" binding:"omitempty,max=254,email"`
+}
+
+type LoginRequest struct {
+	Username string `json:"username" binding:"required"`
+	Password string `json:"password" binding:"required"`
+}
@@ -0,0 +1,65 @@
+package request
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin/binding"
+	"todoGin/repository"
+)
+
+const (
+	BulkModeAtomic     = "atomic"
+	BulkModeBestEffort = "best_effort"
+)
+
+// TodolistBulkRequest is the body of POST /manage-todos/bulk. Mode defaults
+// to atomic (all-or-nothing).
+type TodolistBulkRequest struct {
+	Mode       string                  `json:"mode" binding:"omitempty,oneof=atomic best_effort"`
+	Operations []TodolistBulkOperation `json:"operations" binding:"required,min=1,max=100,dive"`
+}
+
+func (r *TodolistBulkRequest) Atomic() bool {
+	return r.Mode != BulkModeBestEffort
+}
+
+// TodolistBulkOperation is one step of a bulk request. Todo holds a
+// TodolistCreateRequest for create and a TodolistPatchRequest for update;
+// Version plays the part of If-Match for update and delete.
+type TodolistBulkOperation struct {
+	Op      string          `json:"op" binding:"required,oneof=create update delete"`
+	ID      int64           `json:"id" binding:"required_unless=Op create,min=0"`
+	Version int64           `json:"version" binding:"min=0"`
+	Todo    json.RawMessage `json:"todo"`
+}
+
+// ReqBulk validates the operation's todo against the rules of the single-item
+// endpoints and turns it into a repository operation.
+func (o *TodolistBulkOperation) ReqBulk(userID int64) (repository.BulkOperation, error) {
+	op := repository.BulkOperation{Op: o.Op, TodoID: o.ID, Version: o.Version}
+	switch o.Op {
+	case repository.BulkCreate:
+		create := new(TodolistCreateRequest)
+		if err := json.Unmarshal(o.Todo, create); err != nil {
+			return op, err
+		}
+		if err := binding.Validator.ValidateStruct(create); err != nil {
+			return op, err
+		}
+		op.Todo = create.ToTodo(userID)
+	case repository.BulkUpdate:
+		if len(o.Todo) == 0 {
+			return op, errors.New("update needs a todo patch")
+		}
+		patch := new(TodolistPatchRequest)
+		if err := json.Unmarshal(o.Todo, patch); err != nil {
+			return op, err
+		}
+		updates, err := patch.ReqTodo()
+		if err != nil {
+			return op, err
+		}
+		op.Updates = updates
+	}
+	return op, nil
+}
@@ -0,0 +1,15 @@
+package request
+
+import "todoGin/model/entity"
+
+type CommentResponse struct {
+	Status  int            `json:"status"`
+	Message string         `json:"message"`
+	Data    entity.Comment `json:"data"`
+}
+
+type CommentsResponse struct {
+	Message  string           `json:"message"`
+	Data     int              `json:"data"`
+	Comments []entity.Comment `json:"comments"`
+}
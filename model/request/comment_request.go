@@ -0,0 +1,13 @@
+package request
+
+import "strings"
+
+// CommentRequest creates or edits a comment. The markdown body is kept
+// exactly as sent, but must not be blank.
+type CommentRequest struct {
+	Body string `json:"body" binding:"required,max=10000"`
+}
+
+func (r *CommentRequest) Blank() bool {
+	return strings.TrimSpace(r.Body) == ""
+}
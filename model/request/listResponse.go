@@ -0,0 +1,15 @@
+package request
+
+import "todoGin/model/entity"
+
+type ListResponse struct {
+	Status  int         `json:"status"`
+	Message string      `json:"message"`
+	Data    entity.List `json:"data"`
+}
+
+type ListsResponse struct {
+	Message string        `json:"message"`
+	Data    int           `json:"data"`
+	Lists   []entity.List `json:"lists"`
+}
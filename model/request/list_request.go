@@ -0,0 +1,27 @@
+package request
+
+type ListCreateRequest struct {
+	Name string `json:"name" binding:"required,min=1,max=100"`
+}
+
+// ListUpdateRequest renames and/or (un)archives a list; absent fields are
+// left alone.
+type ListUpdateRequest struct {
+	Name     *string `json:"name" binding:"omitempty,min=1,max=100"`
+	Archived *bool   `json:"archived"`
+}
+
+func (r *ListUpdateRequest) ReqList() map[string]interface{} {
+	updates := make(map[string]interface{})
+	if r.Name != nil {
+		updates["name"] = *r.Name
+	}
+	if r.Archived != nil {
+		updates["archived"] = *r.Archived
+	}
+	return updates
+}
+
+type ListQueryRequest struct {
+	Archived bool `form:"archived"`
+}
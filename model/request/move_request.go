@@ -0,0 +1,21 @@
+package request
+
+import "errors"
+
+// TodolistMoveRequest names the todo to place the moved one right before or
+// right after; exactly one of them must be set.
+type TodolistMoveRequest struct {
+	Before *int64 `json:"before" binding:"omitempty,min=1"`
+	After  *int64 `json:"after" binding:"omitempty,min=1"`
+}
+
+// Anchor returns the anchor todo's ID and whether to place after it.
+func (r *TodolistMoveRequest) Anchor() (int64, bool, error) {
+	switch {
+	case r.Before != nil && r.After == nil:
+		return *r.Before, false, nil
+	case r.After != nil && r.Before == nil:
+		return *r.After, true, nil
+	}
+	return 0, false, errors.New("exactly one of before and after is required")
+}
@@ -0,0 +1,36 @@
+package request
+
+import "encoding/json"
+
+// Optional is a JSON member that records whether it was present in the body
+// and whether it was an explicit null.
+type Optional[T any] struct {
+	Set   bool
+	Null  bool
+	Value T
+}
+
+func (o *Optional[T]) UnmarshalJSON(data []byte) error {
+	o.Set = true
+	if string(data) == "null" {
+		o.Null = true
+		return nil
+	}
+	return json.Unmarshal(data, &o.Value)
+}
+
+// Ptr returns the value when it was given and not null, otherwise nil.
+func (o Optional[T]) Ptr() *T {
+	if !o.Set || o.Null {
+		return nil
+	}
+	return &o.Value
+}
+
+// OrNil returns the value, or an untyped nil for an explicit null.
+func (o Optional[T]) OrNil() interface{} {
+	if o.Null {
+		return nil
+	}
+	return o.Value
+}
@@ -0,0 +1,31 @@
+package request
+
+import "todoGin/model/entity"
+
+// Progress counts the completed subtasks of a todo, e.g. 3 of 5.
+type Progress struct {
+	Done  int `json:"done"`
+	Total int `json:"total"`
+}
+
+func ProgressOf(subtasks []entity.Subtask) Progress {
+	progress := Progress{Total: len(subtasks)}
+	for _, subtask := range subtasks {
+		if subtask.Status {
+			progress.Done++
+		}
+	}
+	return progress
+}
+
+type SubtaskResponse struct {
+	Status  int            `json:"status"`
+	Message string         `json:"message"`
+	Data    entity.Subtask `json:"data"`
+}
+
+type SubtasksResponse struct {
+	Message  string           `json:"message"`
+	Data     int              `json:"data"`
+	Subtasks []entity.Subtask `json:"subtasks"`
+}
@@ -0,0 +1,28 @@
+package request
+
+type SubtaskCreateRequest struct {
+	Title string `json:"title" binding:"required,min=1,max=300"`
+}
+
+// SubtaskUpdateRequest renames and/or (un)completes a subtask; absent fields
+// are left alone.
+type SubtaskUpdateRequest struct {
+	Title  *string `json:"title" binding:"omitempty,min=1,max=300"`
+	Status *bool   `json:"status"`
+}
+
+func (r *SubtaskUpdateRequest) ReqSubtask() map[string]interface{} {
+	updates := make(map[string]interface{})
+	if r.Title != nil {
+		updates["title"] = *r.Title
+	}
+	if r.Status != nil {
+		updates["status"] = *r.Status
+	}
+	return updates
+}
+
+// SubtaskReorderRequest lists every subtask ID of a todo in the new order.
+type SubtaskReorderRequest struct {
+	IDs []int64 `json:"ids" binding:"required,min=1"`
+}
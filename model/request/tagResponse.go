@@ -0,0 +1,15 @@
+package request
+
+import "todoGin/model/entity"
+
+type TagsResponse struct {
+	Message string       `json:"message"`
+	Data    int          `json:"data"`
+	Tags    []entity.Tag `json:"tags"`
+}
+
+type TagCountsResponse struct {
+	Message string            `json:"message"`
+	Data    int               `json:"data"`
+	Tags    []entity.TagCount `json:"tags"`
+}
@@ -0,0 +1,29 @@
+package request
+
+import "strings"
+
+type TagAttachRequest struct {
+	Tags []string `json:"tags" binding:"required,min=1,max=20,dive,min=1,max=50"`
+}
+
+// Names returns the requested tag names trimmed, without blanks or repeats.
+// Tag names are compared case-insensitively, like the database does, so only
+// the first spelling of a name is kept.
+func (r *TagAttachRequest) Names() []string {
+	return tagNames(r.Tags)
+}
+
+func tagNames(tags []string) []string {
+	seen := make(map[string]bool, len(tags))
+	names := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		key := strings.ToLower(tag)
+		if tag == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		names = append(names, tag)
+	}
+	return names
+}
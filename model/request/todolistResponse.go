@@ -1,17 +1,27 @@
 package request
 
-import "todoGin/model/entity"
+import (
+	"time"
+	"todoGin/model/entity"
+)
 
+// TodoResponse carries a single todo. Progress is set when the todo was
+// loaded with its subtasks.
 type TodoResponse struct {
-	Status  interface{}     `json:"status"`
-	Message interface{}     `json:"message"`
-	Data    entity.Todolist `json:"data"`
+	Status   interface{}     `json:"status"`
+	Message  interface{}     `json:"message"`
+	Data     entity.Todolist `json:"data"`
+	Progress *Progress       `json:"progress,omitempty"`
 }
 
 type TodoResponseToGetAll struct {
-	Message string            `json:"message"`
-	Data    int               `json:"data"`
-	Todos   []entity.Todolist `json:"todos"`
+	Message    string            `json:"message"`
+	Data       int               `json:"data"`
+	Total      int64             `json:"total"`
+	Limit      int               `json:"limit"`
+	Offset     int               `json:"offset"`
+	NextOffset *int              `json:"next_offset"`
+	Todos      []entity.Todolist `json:"todos"`
 }
 
 type TodoIDResponse struct {
@@ -29,3 +39,55 @@ type TodoUpdateResponse struct {
 	Message string      `json:"data"`
 	Todos   interface{} `json:"todos"`
 }
+
+// TodoSearchItem is a search hit with HTML snippets of the matched fields,
+// keyed by field name, where matches are wrapped in <mark> tags.
+type TodoSearchItem struct {
+	entity.Todolist
+	Relevance  float64           `json:"relevance"`
+	Highlights map[string]string `json:"highlights"`
+}
+
+type TodoSearchResponse struct {
+	Message string           `json:"message"`
+	Data    int              `json:"data"`
+	Todos   []TodoSearchItem `json:"todos"`
+}
+
+// TodoBulkResult reports one operation of a bulk request, in request order,
+// with the HTTP status the single-item endpoint would have answered.
+type TodoBulkResult struct {
+	Index   int              `json:"index"`
+	Op      string           `json:"op"`
+	ID      int64            `json:"id,omitempty"`
+	Status  int              `json:"status"`
+	Message string           `json:"message"`
+	Data    *entity.Todolist `json:"data,omitempty"`
+}
+
+type TodoBulkResponse struct {
+	Status    int              `json:"status"`
+	Message   string           `json:"message"`
+	Mode      string           `json:"mode"`
+	Succeeded int              `json:"succeeded"`
+	Failed    int              `json:"failed"`
+	Results   []TodoBulkResult `json:"results"`
+}
+
+type TodoOccurrencesResponse struct {
+	Message     string              `json:"message"`
+	Data        int                 `json:"data"`
+	From        time.Time           `json:"from"`
+	To          time.Time           `json:"to"`
+	Occurrences []entity.Occurrence `json:"occurrences"`
+}
+
+// WSReply answers a WSCommand with the status and message the matching HTTP
+// endpoint would have answered.
+type WSReply struct {
+	Type    string           `json:"type"`
+	ID      string           `json:"id,omitempty"`
+	Status  int              `json:"status"`
+	Message string           `json:"message"`
+	Data    *entity.Todolist `json:"data,omitempty"`
+}
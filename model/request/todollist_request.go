@@ -1,29 +1,210 @@
 package request
 
+import (
+	"errors"
+	"github.com/gin-gonic/gin/binding"
+	"strings"
+	"time"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+const DefaultPageLimit = 20
+
+// TodolistCreateRequest is the body of POST. A recurrence rule, such as
+// "weekly" or "FREQ=MONTHLY;BYMONTHDAY=1", needs a due date to count from.
 type TodolistCreateRequest struct {
-	Title string `json:"title" binding:"required,min=2"`
+	Title       string     `json:"title" binding:"required,min=2"`
+	Description *string    `json:"description" binding:"omitempty,max=65535"`
+	DueDate     *time.Time `json:"due_date" binding:"required_with=Recurrence"`
+	Priority    string     `json:"priority" binding:"omitempty,oneof=low medium high urgent"`
+	Recurrence  *string    `json:"recurrence" binding:"omitempty,max=255,rrule"`
+}
+
+func (r *TodolistCreateRequest) ToTodo(userID int64) *entity.Todolist {
+	priority := r.Priority
+	if priority == "" {
+		priority = entity.PriorityMedium
+	}
+	todo := &entity.Todolist{
+		UserID:      userID,
+		Title:       r.Title,
+		Description: r.Description,
+		DueDate:     r.DueDate,
+		Priority:    priority,
+	}
+	if r.Recurrence != nil {
+		rule := canonicalRule(*r.Recurrence)
+		todo.Recurrence = &rule
+	}
+	return todo
 }
 
+// TodolistUpdateRequest is the body of PUT, a full replacement: every field is
+// written, so omitted optional fields are cleared and priority falls back to medium.
 type TodolistUpdateRequest struct {
-	Title  string `json:"title"`
-	Status bool   `json:"status"`
+	Title       string     `json:"title" binding:"required,min=2"`
+	Description *string    `json:"description" binding:"omitempty,max=65535"`
+	Status      *bool      `json:"status" binding:"required"`
+	DueDate     *time.Time `json:"due_date" binding:"required_with=Recurrence"`
+	Priority    string     `json:"priority" binding:"omitempty,oneof=low medium high urgent"`
+	Recurrence  *string    `json:"recurrence" binding:"omitempty,max=255,rrule"`
 }
 
 func (r *TodolistUpdateRequest) ReqTodo() map[string]interface{} {
-	updates := make(map[string]interface{})
-	if r.Title != "" {
-		updates["title"] = r.Title
+	updates := map[string]interface{}{
+		"title":       r.Title,
+		"description": nil,
+		"status":      *r.Status,
+		"due_date":    nil,
+		"priority":    entity.PriorityMedium,
+		"recurrence":  nil,
+	}
+	if r.Description != nil {
+		updates["description"] = *r.Description
+	}
+	if r.DueDate != nil {
+		updates["due_date"] = *r.DueDate
+	}
+	if r.Priority != "" {
+		updates["priority"] = r.Priority
+	}
+	if r.Recurrence != nil {
+		updates["recurrence"] = canonicalRule(*r.Recurrence)
 	}
-	updates["status"] = r.Status
-
-	//if r.Status != "" {
-	//	updates["status"] = r.Status
-	//
-	//}
 
 	return updates
 }
 
+// TodolistPatchRequest is the body of PATCH, applied with JSON Merge Patch
+// (RFC 7396) semantics: absent members are left alone and null clears a field.
+type TodolistPatchRequest struct {
+	Title       Optional[string]    `json:"title"`
+	Description Optional[string]    `json:"description"`
+	Status      Optional[bool]      `json:"status"`
+	DueDate     Optional[time.Time] `json:"due_date"`
+	Priority    Optional[string]    `json:"priority"`
+	Recurrence  Optional[string]    `json:"recurrence"`
+}
+
+// todolistPatchValues holds the non-null members of a patch so they are checked
+// against the same binding rules as TodolistCreateRequest.
+type todolistPatchValues struct {
+	Title       *string `binding:"omitempty,min=2"`
+	Description *string `binding:"omitempty,max=65535"`
+	Priority    *string `binding:"omitempty,oneof=low medium high urgent"`
+	Recurrence  *string `binding:"omitempty,max=255,rrule"`
+}
+
+// ReqTodo validates the patch and turns it into column updates. Title and
+// status cannot be null; a null priority resets it to medium.
+func (r *TodolistPatchRequest) ReqTodo() (map[string]interface{}, error) {
+	if r.Title.Null || r.Status.Null {
+		return nil, errors.New("title and status cannot be null")
+	}
+	values := todolistPatchValues{
+		Title:       r.Title.Ptr(),
+		Description: r.Description.Ptr(),
+		Priority:    r.Priority.Ptr(),
+		Recurrence:  r.Recurrence.Ptr(),
+	}
+	if err := binding.Validator.ValidateStruct(values); err != nil {
+		return nil, err
+	}
+
+	updates := make(map[string]interface{})
+	if r.Title.Set {
+		updates["title"] = r.Title.Value
+	}
+	if r.Description.Set {
+		updates["description"] = r.Description.OrNil()
+	}
+	if r.Status.Set {
+		updates["status"] = r.Status.Value
+	}
+	if r.DueDate.Set {
+		updates["due_date"] = r.DueDate.OrNil()
+	}
+	if r.Priority.Set {
+		updates["priority"] = entity.PriorityMedium
+		if !r.Priority.Null {
+			updates["priority"] = r.Priority.Value
+		}
+	}
+	if r.Recurrence.Set {
+		updates["recurrence"] = nil
+		if !r.Recurrence.Null {
+			updates["recurrence"] = canonicalRule(r.Recurrence.Value)
+		}
+	}
+
+	return updates, nil
+}
+
 //type TodolistStatusRequest struct {
 //	Status bool `gorm:"default:false" json:"status"`
 //}
+
+// TodolistQueryRequest holds the query string of GET /manage-todos.
+// Sort takes a column name, prefixed with "-" for descending order. Repeated
+// tag parameters must all match, or any of them with tag_mode=or.
+type TodolistQueryRequest struct {
+	Limit   int      `form:"limit" binding:"omitempty,min=1,max=100"`
+	Offset  int      `form:"offset" binding:"omitempty,min=0"`
+	Sort    string   `form:"sort" binding:"omitempty,oneof=position -position id -id title -title created_at -created_at due_date -due_date priority -priority"`
+	Status  *bool    `form:"status"`
+	Tags    []string `form:"tag" binding:"omitempty,max=10,dive,max=50"`
+	TagMode string   `form:"tag_mode" binding:"omitempty,oneof=and or"`
+}
+
+func (r *TodolistQueryRequest) Query() repository.TodoQuery {
+	query := repository.TodoQuery{
+		Limit:  r.Limit,
+		Offset: r.Offset,
+		Sort:   strings.TrimPrefix(r.Sort, "-"),
+		Desc:   strings.HasPrefix(r.Sort, "-"),
+		Status: r.Status,
+	}
+	if query.Limit == 0 {
+		query.Limit = DefaultPageLimit
+	}
+	if tags := tagNames(r.Tags); len(tags) > 0 {
+		query.Tags = tags
+		query.MatchAllTags = r.TagMode != "or"
+	}
+	return query
+}
+
+type TodolistSearchRequest struct {
+	Q     string `form:"q" binding:"required,min=2,max=200"`
+	Limit int    `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// MaxOccurrenceRange bounds the window of GET /manage-todos/occurrences.
+const MaxOccurrenceRange = 366 * 24 * time.Hour
+
+// TodolistOccurrencesRequest holds the RFC 3339 window of
+// GET /manage-todos/occurrences; it defaults to the next 30 days.
+type TodolistOccurrencesRequest struct {
+	From time.Time `form:"from"`
+	To   time.Time `form:"to"`
+}
+
+// Range fills in the defaults relative to now and checks the window is
+// ordered and no longer than MaxOccurrenceRange.
+func (r *TodolistOccurrencesRequest) Range(now time.Time) (time.Time, time.Time, error) {
+	from, to := r.From, r.To
+	if from.IsZero() {
+		from = now
+	}
+	if to.IsZero() {
+		to = from.AddDate(0, 0, 30)
+	}
+	if !to.After(from) {
+		return from, to, errors.New("to must be after from")
+	}
+	if to.Sub(from) > MaxOccurrenceRange {
+		return from, to, errors.New("range is too long")
+	}
+	return from, to, nil
+}
@@ -0,0 +1,39 @@
+package request
+
+import (
+	"github.com/gin-gonic/gin/binding"
+	"github.com/go-playground/validator/v10"
+	"net/url"
+	"todoGin/recurrence"
+	"todoGin/webhook"
+)
+
+// init registers the custom binding rules used by the request structs:
+// "rrule" accepts a recurrence rule understood by recurrence.Parse and
+// "http_url" an absolute http or https URL and "public_host" a URL whose
+// host is not a loopback, private or link-local address or localhost.
+func init() {
+	if v, ok := binding.Validator.Engine().(*validator.Validate); ok {
+		_ = v.RegisterValidation("rrule", func(fl validator.FieldLevel) bool {
+			_, err := recurrence.Parse(fl.Field().String())
+			return err == nil
+		})
+		_ = v.RegisterValidation("http_url", func(fl validator.FieldLevel) bool {
+			u, err := url.Parse(fl.Field().String())
+			return err == nil && (u.Scheme == "http" || u.Scheme == "https") && u.Host != ""
+		})
+		_ = v.RegisterValidation("public_host", func(fl validator.FieldLevel) bool {
+			u, err := url.Parse(fl.Field().String())
+			return err == nil && webhook.IsPublicHost(u.Hostname())
+		})
+	}
+}
+
+// canonicalRule rewrites a validated recurrence rule in the form it is stored.
+func canonicalRule(rule string) string {
+	parsed, err := recurrence.Parse(rule)
+	if err != nil {
+		return rule
+	}
+	return parsed.String()
+}
@@ -0,0 +1,36 @@
+package request
+
+import "todoGin/model/entity"
+
+type WebhookResponse struct {
+	Status  int            `json:"status"`
+	Message string         `json:"message"`
+	Data    entity.Webhook `json:"data"`
+}
+
+// WebhookCreatedResponse is the only response that reveals the signing
+// secret.
+type WebhookCreatedResponse struct {
+	Status  int            `json:"status"`
+	Message string         `json:"message"`
+	Data    entity.Webhook `json:"data"`
+	Secret  string         `json:"secret"`
+}
+
+type WebhooksResponse struct {
+	Message  string           `json:"message"`
+	Data     int              `json:"data"`
+	Webhooks []entity.Webhook `json:"webhooks"`
+}
+
+type WebhookDeliveryResponse struct {
+	Status  int                    `json:"status"`
+	Message string                 `json:"message"`
+	Data    entity.WebhookDelivery `json:"data"`
+}
+
+type WebhookDeliveriesResponse struct {
+	Message    string                   `json:"message"`
+	Data       int                      `json:"data"`
+	Deliveries []entity.WebhookDelivery `json:"deliveries"`
+}
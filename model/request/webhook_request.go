@@ -0,0 +1,58 @@
+package request
+
+import "todoGin/model/entity"
+
+// WebhookCreateRequest registers a webhook; no events means every event.
+type WebhookCreateRequest struct {
+	URL    string   `json:"url" binding:"required,max=2048,http_url,public_host"`
+	Events []string `json:"events" binding:"omitempty,max=5,dive,oneof=todo.created todo.updated todo.completed todo.deleted todo.restored"`
+}
+
+func (r *WebhookCreateRequest) ToWebhook(userID int64, secret string) *entity.Webhook {
+	return &entity.Webhook{
+		UserID: userID,
+		URL:    r.URL,
+		Secret: secret,
+		Events: eventNames(r.Events),
+		Active: true,
+	}
+}
+
+// WebhookUpdateRequest changes a webhook; absent fields are left alone and an
+// empty events list subscribes to every event.
+type WebhookUpdateRequest struct {
+	URL    *string  `json:"url" binding:"omitempty,max=2048,http_url,public_host"`
+	Events []string `json:"events" binding:"omitempty,max=5,dive,oneof=todo.created todo.updated todo.completed todo.deleted todo.restored"`
+	Active *bool    `json:"active"`
+}
+
+func (r *WebhookUpdateRequest) ReqWebhook() map[string]interface{} {
+	updates := make(map[string]interface{})
+	if r.URL != nil {
+		updates["url"] = *r.URL
+	}
+	if r.Events != nil {
+		updates["events"] = eventNames(r.Events)
+	}
+	if r.Active != nil {
+		updates["active"] = *r.Active
+	}
+	return updates
+}
+
+type WebhookDeliveriesRequest struct {
+	Limit int `form:"limit" binding:"omitempty,min=1,max=100"`
+}
+
+// eventNames drops repeated events, keeping the first occurrence.
+func eventNames(events []string) []string {
+	names := make([]string, 0, len(events))
+	seen := make(map[string]bool, len(events))
+	for _, event := range events {
+		if !seen[event] {
+			seen[event] = true
+			names = append(names, event)
+		}
+	}
+	return names
+}
@@ -0,0 +1,46 @@
+package request
+
+import (
+	"encoding/json"
+	"todoGin/repository"
+)
+
+// Commands a collaboration client can send over the WebSocket.
+const (
+	WSSubscribe   = "subscribe"
+	WSUnsubscribe = "unsubscribe"
+	WSCreate      = "create"
+	WSUpdate      = "update"
+	WSComplete    = "complete"
+)
+
+// WSCommand is one message from a collaboration client. ID is echoed in the
+// reply. ListID picks the list to (un)subscribe or create in; without it
+// the command is about the todos outside any list. Update takes a
+// TodolistPatchRequest in Todo, and Version plays the part of If-Match for
+// update and complete.
+type WSCommand struct {
+	Type    string          `json:"type" binding:"required,oneof=subscribe unsubscribe create update complete"`
+	ID      string          `json:"id" binding:"max=64"`
+	ListID  *int64          `json:"list_id" binding:"omitempty,min=1"`
+	TodoID  int64           `json:"todo_id" binding:"required_if=Type update,required_if=Type complete,min=0"`
+	Version int64           `json:"version" binding:"min=0"`
+	Todo    json.RawMessage `json:"todo"`
+}
+
+// ReqBulk validates a create, update or complete command like the matching
+// bulk operation and turns it into a repository operation.
+func (c *WSCommand) ReqBulk(userID int64) (repository.BulkOperation, error) {
+	operation := TodolistBulkOperation{Op: repository.BulkUpdate, ID: c.TodoID, Version: c.Version, Todo: c.Todo}
+	switch c.Type {
+	case WSCreate:
+		operation.Op = repository.BulkCreate
+	case WSComplete:
+		operation.Todo = json.RawMessage(`{"status": true}`)
+	}
+	op, err := operation.ReqBulk(userID)
+	if err == nil && op.Todo != nil {
+		op.Todo.ListID = c.ListID
+	}
+	return op, err
+}
@@ -0,0 +1,37 @@
+package notifier
+
+import (
+	"context"
+	"sync"
+	"todoGin/model/entity"
+)
+
+// Fake records reminders in memory instead of sending them. Err, when set,
+// is returned for every reminder, which is then not recorded.
+type Fake struct {
+	Err error
+
+	mu        sync.Mutex
+	reminders []entity.Reminder
+}
+
+func NewFake() *Fake {
+	return &Fake{}
+}
+
+func (f *Fake) Notify(ctx context.Context, reminder entity.Reminder) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.Err != nil {
+		return f.Err
+	}
+	f.reminders = append(f.reminders, reminder)
+	return nil
+}
+
+// Reminders returns a copy of what has been delivered so far.
+func (f *Fake) Reminders() []entity.Reminder {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return append([]entity.Reminder(nil), f.reminders...)
+}
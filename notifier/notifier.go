@@ -0,0 +1,38 @@
+// Package notifier delivers due-date reminders to the outside world.
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"time"
+	"todoGin/model/entity"
+)
+
+// Notifier sends a single reminder. An error means the reminder was not
+// delivered and should be tried again later.
+type Notifier interface {
+	Notify(ctx context.Context, reminder entity.Reminder) error
+}
+
+// LogNotifier writes reminders to the application log.
+type LogNotifier struct{}
+
+func NewLogNotifier() *LogNotifier {
+	return &LogNotifier{}
+}
+
+func (n *LogNotifier) Notify(ctx context.Context, reminder entity.Reminder) error {
+	logrus.WithFields(logrus.Fields{
+		"todo_id":  reminder.TodoID,
+		"user_id":  reminder.UserID,
+		"username": reminder.Username,
+		"due_date": reminder.DueDate,
+	}).Info("Reminder: ", reminder.Title)
+	return nil
+}
+
+// subject is the one-line summary shared by the notifiers.
+func subject(reminder entity.Reminder) string {
+	return fmt.Sprintf("Reminder: %q is due %s", reminder.Title, reminder.DueDate.Format(time.RFC1123))
+}
@@ -0,0 +1,96 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"testing"
+	"time"
+	"todoGin/model/entity"
+)
+
+var testReminder = entity.Reminder{
+	TodoID:   1,
+	UserID:   7,
+	Username: "raihan",
+	Title:    "deploy checklist",
+	DueDate:  time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC),
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	testCases := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "Success", status: http.StatusNoContent},
+		{name: "Rejected", status: http.StatusInternalServerError, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var payload webhookPayload
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				assert.Equal(t, http.MethodPost, r.Method)
+				assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+				assert.NoError(t, json.NewDecoder(r.Body).Decode(&payload))
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			err := NewWebhookNotifier(server.URL, time.Second).Notify(context.Background(), testReminder)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, "todo.due_soon", payload.Event)
+			assert.Equal(t, testReminder, payload.Reminder)
+		})
+	}
+}
+
+func TestSMTPNotifier(t *testing.T) {
+	n := NewSMTPNotifier("mail.example.com:587", "bot", "secret", "todo@example.com")
+	require.NotNil(t, n.Auth)
+
+	var sentTo []string
+	var msg string
+	n.send = func(addr string, a smtp.Auth, from string, to []string, body []byte) error {
+		assert.Equal(t, "mail.example.com:587", addr)
+		assert.Equal(t, "todo@example.com", from)
+		sentTo, msg = to, string(body)
+		return nil
+	}
+
+	// no address, no mail
+	require.NoError(t, n.Notify(context.Background(), testReminder))
+	assert.Nil(t, sentTo)
+
+	email := "raihan@example.com"
+	reminder := testReminder
+	reminder.Email = &email
+	require.NoError(t, n.Notify(context.Background(), reminder))
+	assert.Equal(t, []string{"raihan@example.com"}, sentTo)
+	assert.Contains(t, msg, "To: raihan@example.com\r\n")
+	assert.Contains(t, msg, `Subject: Reminder: "deploy checklist" is due`)
+	assert.Contains(t, msg, "Hi raihan,")
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.ErrorIs(t, n.Notify(ctx, testReminder), context.Canceled)
+}
+
+func TestFake(t *testing.T) {
+	fake := NewFake()
+	require.NoError(t, fake.Notify(context.Background(), testReminder))
+	assert.Equal(t, []entity.Reminder{testReminder}, fake.Reminders())
+
+	fake.Err = assert.AnError
+	assert.ErrorIs(t, fake.Notify(context.Background(), testReminder), assert.AnError)
+	assert.Len(t, fake.Reminders(), 1)
+}
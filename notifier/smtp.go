@@ -0,0 +1,67 @@
+package notifier
+
+import (
+	"context"
+	"fmt"
+	"github.com/sirupsen/logrus"
+	"net"
+	"net/smtp"
+	"strings"
+	"time"
+	"todoGin/model/entity"
+)
+
+// SMTPNotifier mails each reminder to the owner of the todo. Users who gave
+// no e-mail address are skipped.
+type SMTPNotifier struct {
+	Addr string
+	Auth smtp.Auth
+	From string
+
+	// send is smtp.SendMail, swapped out in tests.
+	send func(addr string, a smtp.Auth, from string, to []string, msg []byte) error
+}
+
+// NewSMTPNotifier authenticates with PLAIN auth when username is set.
+func NewSMTPNotifier(addr, username, password, from string) *SMTPNotifier {
+	var auth smtp.Auth
+	if username != "" {
+		host, _, _ := net.SplitHostPort(addr)
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+	return &SMTPNotifier{
+		Addr: addr,
+		Auth: auth,
+		From: from,
+		send: smtp.SendMail,
+	}
+}
+
+// Notify sends the mail. net/smtp takes no context, so cancellation is only
+// honoured before the connection is made.
+func (n *SMTPNotifier) Notify(ctx context.Context, reminder entity.Reminder) error {
+	if err := ctx.Err(); err != nil {
+		return err
+	}
+	if reminder.Email == nil {
+		logrus.Infof("smtp notifier: user %d has no e-mail address, skipping reminder for todo %d", reminder.UserID, reminder.TodoID)
+		return nil
+	}
+	if err := n.send(n.Addr, n.Auth, n.From, []string{*reminder.Email}, n.message(reminder)); err != nil {
+		return fmt.Errorf("smtp notifier: %w", err)
+	}
+	return nil
+}
+
+func (n *SMTPNotifier) message(reminder entity.Reminder) []byte {
+	var b strings.Builder
+	fmt.Fprintf(&b, "From: %s\r\n", n.From)
+	fmt.Fprintf(&b, "To: %s\r\n", *reminder.Email)
+	fmt.Fprintf(&b, "Subject: %s\r\n", subject(reminder))
+	b.WriteString("MIME-Version: 1.0\r\n")
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	fmt.Fprintf(&b, "Hi %s,\r\n\r\n", reminder.Username)
+	fmt.Fprintf(&b, "your todo %q (#%d) is due %s.\r\n", reminder.Title, reminder.TodoID, reminder.DueDate.Format(time.RFC1123))
+	return []byte(b.String())
+}
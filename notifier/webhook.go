@@ -0,0 +1,57 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+	"todoGin/model/entity"
+)
+
+// WebhookNotifier POSTs each reminder as JSON to a URL. Any response other
+// than 2xx counts as a failed delivery.
+type WebhookNotifier struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewWebhookNotifier(url string, timeout time.Duration) *WebhookNotifier {
+	return &WebhookNotifier{
+		URL:    url,
+		Client: &http.Client{Timeout: timeout},
+	}
+}
+
+type webhookPayload struct {
+	Event    string          `json:"event"`
+	Subject  string          `json:"subject"`
+	Reminder entity.Reminder `json:"reminder"`
+}
+
+func (n *WebhookNotifier) Notify(ctx context.Context, reminder entity.Reminder) error {
+	body, err := json.Marshal(webhookPayload{
+		Event:    "todo.due_soon",
+		Subject:  subject(reminder),
+		Reminder: reminder,
+	})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, n.URL, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := n.Client.Do(req)
+	if err != nil {
+		return fmt.Errorf("webhook notifier: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook notifier: %s answered %s", n.URL, resp.Status)
+	}
+	return nil
+}
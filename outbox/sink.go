@@ -0,0 +1,131 @@
+// Package outbox holds the sinks the outbox relay publishes todo events to.
+// Events may reach a sink more than once; each carries its event ID so that
+// consumers can drop the repeats.
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sync"
+	"todoGin/model/entity"
+)
+
+// Headers sent with every event POSTed by HTTPSink.
+const (
+	HeaderEvent          = "X-Todo-Event"
+	HeaderIdempotencyKey = "Idempotency-Key"
+)
+
+// maxErrorBody bounds how much of a failed response ends up in the error.
+const maxErrorBody = 1024
+
+// Sink publishes an event. A nil error means the sink has taken it over.
+type Sink interface {
+	Publish(ctx context.Context, event entity.OutboxEvent) error
+}
+
+// WriterSink writes each event's payload as one line of JSON.
+type WriterSink struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func NewWriterSink(w io.Writer) *WriterSink {
+	return &WriterSink{w: w}
+}
+
+// NewStdoutSink writes the events to standard output.
+func NewStdoutSink() *WriterSink {
+	return NewWriterSink(os.Stdout)
+}
+
+// NewFileSink appends the events to the file at path, creating it if needed.
+func NewFileSink(path string) (*WriterSink, error) {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0o644)
+	if err != nil {
+		return nil, err
+	}
+	return NewWriterSink(f), nil
+}
+
+func (s *WriterSink) Publish(ctx context.Context, event entity.OutboxEvent) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, err := io.WriteString(s.w, event.Payload+"\n"); err != nil {
+		return err
+	}
+	// a file only counts as published once it is on disk
+	if f, ok := s.w.(*os.File); ok && f != os.Stdout {
+		return f.Sync()
+	}
+	return nil
+}
+
+// HTTPSink POSTs each event's payload to URL with the event ID as
+// Idempotency-Key. Anything but a 2xx response is an error.
+type HTTPSink struct {
+	URL    string
+	Client *http.Client
+}
+
+func NewHTTPSink(url string, client *http.Client) *HTTPSink {
+	return &HTTPSink{
+		URL:    url,
+		Client: client,
+	}
+}
+
+func (s *HTTPSink) Publish(ctx context.Context, event entity.OutboxEvent) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.URL, bytes.NewBufferString(event.Payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(HeaderEvent, event.Event)
+	req.Header.Set(HeaderIdempotencyKey, event.EventID)
+
+	resp, err := s.Client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		snippet, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+		return fmt.Errorf("%s: %s", resp.Status, snippet)
+	}
+	return nil
+}
+
+// MultiSink publishes to every sink in order and stops at the first failure.
+// It remembers which sinks took the failed event, so that when the relay
+// retries it only the others get it. That memory does not outlive the
+// process, so after a restart each sink may see the event again.
+type MultiSink struct {
+	sinks []Sink
+
+	mu      sync.Mutex
+	eventID string
+	taken   int
+}
+
+func NewMultiSink(sinks ...Sink) *MultiSink {
+	return &MultiSink{sinks: sinks}
+}
+
+func (m *MultiSink) Publish(ctx context.Context, event entity.OutboxEvent) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if event.EventID != m.eventID {
+		m.eventID, m.taken = event.EventID, 0
+	}
+	for ; m.taken < len(m.sinks); m.taken++ {
+		if err := m.sinks[m.taken].Publish(ctx, event); err != nil {
+			return err
+		}
+	}
+	return nil
+}
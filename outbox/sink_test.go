@@ -0,0 +1,104 @@
+package outbox
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+	"todoGin/model/entity"
+)
+
+var testEvent = entity.OutboxEvent{
+	ID:      1,
+	EventID: "0b5f4e0c-4a8e-4d3b-9a52-8f9d6c1e2a3b",
+	UserID:  7,
+	Event:   entity.EventTodoCreated,
+	Payload: `{"id":"0b5f4e0c-4a8e-4d3b-9a52-8f9d6c1e2a3b","event":"todo.created"}`,
+}
+
+func TestWriterSink(t *testing.T) {
+	var buf bytes.Buffer
+	sink := NewWriterSink(&buf)
+	require.NoError(t, sink.Publish(context.Background(), testEvent))
+	require.NoError(t, sink.Publish(context.Background(), testEvent))
+	assert.Equal(t, testEvent.Payload+"\n"+testEvent.Payload+"\n", buf.String())
+}
+
+func TestFileSink(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "events.jsonl")
+	require.NoError(t, os.WriteFile(path, []byte("earlier\n"), 0o644))
+
+	sink, err := NewFileSink(path)
+	require.NoError(t, err)
+	require.NoError(t, sink.Publish(context.Background(), testEvent))
+
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	assert.Equal(t, "earlier\n"+testEvent.Payload+"\n", string(content))
+}
+
+func TestHTTPSink(t *testing.T) {
+	status := http.StatusAccepted
+	var header http.Header
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header = r.Header
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	sink := NewHTTPSink(server.URL, server.Client())
+	require.NoError(t, sink.Publish(context.Background(), testEvent))
+	assert.Equal(t, testEvent.Payload, string(body))
+	assert.Equal(t, testEvent.EventID, header.Get(HeaderIdempotencyKey))
+	assert.Equal(t, entity.EventTodoCreated, header.Get(HeaderEvent))
+
+	status = http.StatusInternalServerError
+	assert.Error(t, sink.Publish(context.Background(), testEvent))
+}
+
+// sinkFunc adapts a function to Sink.
+type sinkFunc func(ctx context.Context, event entity.OutboxEvent) error
+
+func (f sinkFunc) Publish(ctx context.Context, event entity.OutboxEvent) error {
+	return f(ctx, event)
+}
+
+func TestMultiSink(t *testing.T) {
+	var calls []string
+	var down error
+	sink := func(name string, err *error) Sink {
+		return sinkFunc(func(ctx context.Context, event entity.OutboxEvent) error {
+			calls = append(calls, name)
+			if err != nil {
+				return *err
+			}
+			return nil
+		})
+	}
+	m := NewMultiSink(sink("a", nil), sink("b", &down), sink("c", nil))
+
+	assert.NoError(t, m.Publish(context.Background(), testEvent))
+	assert.Equal(t, []string{"a", "b", "c"}, calls)
+
+	calls = nil
+	down = errors.New("down")
+	next := testEvent
+	next.EventID = "next"
+	assert.Error(t, m.Publish(context.Background(), next))
+	assert.Equal(t, []string{"a", "b"}, calls)
+
+	// the retry skips the sinks that already took the event
+	calls = nil
+	down = nil
+	assert.NoError(t, m.Publish(context.Background(), next))
+	assert.Equal(t, []string{"b", "c"}, calls)
+}
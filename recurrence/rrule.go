@@ -0,0 +1,381 @@
+// Package recurrence parses and expands the subset of iCalendar (RFC 5545)
+// RRULEs used for recurring todos: FREQ, INTERVAL, BYDAY, BYMONTHDAY, COUNT
+// and UNTIL. The shorthands "daily", "weekly", "monthly" and "yearly" are
+// accepted as well.
+package recurrence
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Frequency string
+
+const (
+	Daily   Frequency = "DAILY"
+	Weekly  Frequency = "WEEKLY"
+	Monthly Frequency = "MONTHLY"
+	Yearly  Frequency = "YEARLY"
+)
+
+// maxPeriods bounds how many days, weeks, months or years an expansion walks,
+// so that rules which never match (BYMONTHDAY=30 in February only) end.
+// Daily and weekly series skip ahead to the time asked about rather than
+// walk there, so the bound holds however old the series.
+const maxPeriods = 10000
+
+var weekdays = map[string]time.Weekday{
+	"MO": time.Monday,
+	"TU": time.Tuesday,
+	"WE": time.Wednesday,
+	"TH": time.Thursday,
+	"FR": time.Friday,
+	"SA": time.Saturday,
+	"SU": time.Sunday,
+}
+
+// Rule is a parsed recurrence rule. Occurrences are counted from a start
+// time (DTSTART), which is always the first occurrence, as in RFC 5545, even
+// when the rule would not produce it, and keeps its time of day for the ones
+// after it.
+type Rule struct {
+	Freq       Frequency
+	Interval   int
+	ByDay      []time.Weekday
+	ByMonthDay []int
+	Count      int
+	Until      time.Time
+}
+
+// Parse reads an RRULE such as "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", with or
+// without the "RRULE:" prefix, or one of the shorthands.
+func Parse(s string) (*Rule, error) {
+	s = strings.TrimSpace(s)
+	switch strings.ToLower(s) {
+	case "daily", "weekly", "monthly", "yearly":
+		s = "FREQ=" + s
+	}
+	s = strings.TrimPrefix(strings.ToUpper(s), "RRULE:")
+
+	rule := &Rule{Interval: 1}
+	seen := make(map[string]bool)
+	for _, part := range strings.Split(s, ";") {
+		key, value, ok := strings.Cut(part, "=")
+		if !ok || value == "" {
+			return nil, fmt.Errorf("recurrence: malformed part %q", part)
+		}
+		if seen[key] {
+			return nil, fmt.Errorf("recurrence: %s given twice", key)
+		}
+		seen[key] = true
+
+		var err error
+		switch key {
+		case "FREQ":
+			rule.Freq = Frequency(value)
+			if rule.Freq != Daily && rule.Freq != Weekly && rule.Freq != Monthly && rule.Freq != Yearly {
+				err = fmt.Errorf("unsupported FREQ %s", value)
+			}
+		case "INTERVAL":
+			rule.Interval, err = positiveInt(value)
+		case "COUNT":
+			rule.Count, err = positiveInt(value)
+		case "UNTIL":
+			rule.Until, err = parseUntil(value)
+		case "BYDAY":
+			rule.ByDay, err = parseByDay(value)
+		case "BYMONTHDAY":
+			rule.ByMonthDay, err = parseByMonthDay(value)
+		default:
+			err = fmt.Errorf("unsupported part %s", key)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("recurrence: %w", err)
+		}
+	}
+
+	switch {
+	case rule.Freq == "":
+		return nil, errors.New("recurrence: FREQ is required")
+	case rule.Count > 0 && !rule.Until.IsZero():
+		return nil, errors.New("recurrence: COUNT and UNTIL cannot be combined")
+	case len(rule.ByDay) > 0 && rule.Freq != Weekly:
+		return nil, errors.New("recurrence: BYDAY is only supported with FREQ=WEEKLY")
+	case len(rule.ByMonthDay) > 0 && rule.Freq != Monthly:
+		return nil, errors.New("recurrence: BYMONTHDAY is only supported with FREQ=MONTHLY")
+	}
+	return rule, nil
+}
+
+// String returns the rule in canonical RRULE form, without the prefix.
+func (r *Rule) String() string {
+	parts := []string{"FREQ=" + string(r.Freq)}
+	if r.Interval > 1 {
+		parts = append(parts, "INTERVAL="+strconv.Itoa(r.Interval))
+	}
+	if len(r.ByDay) > 0 {
+		days := make([]string, len(r.ByDay))
+		for i, day := range r.ByDay {
+			days[i] = strings.ToUpper(day.String()[:2])
+		}
+		parts = append(parts, "BYDAY="+strings.Join(days, ","))
+	}
+	if len(r.ByMonthDay) > 0 {
+		days := make([]string, len(r.ByMonthDay))
+		for i, day := range r.ByMonthDay {
+			days[i] = strconv.Itoa(day)
+		}
+		parts = append(parts, "BYMONTHDAY="+strings.Join(days, ","))
+	}
+	if r.Count > 0 {
+		parts = append(parts, "COUNT="+strconv.Itoa(r.Count))
+	}
+	if !r.Until.IsZero() {
+		parts = append(parts, "UNTIL="+r.Until.UTC().Format("20060102T150405Z"))
+	}
+	return strings.Join(parts, ";")
+}
+
+// Next returns the first occurrence of the series starting at start that is
+// strictly after t, and false once the series has ended.
+func (r *Rule) Next(start, t time.Time) (time.Time, bool) {
+	var next time.Time
+	found := false
+	r.each(start, t, func(occurrence time.Time, _ int) bool {
+		if occurrence.After(t) {
+			next, found = occurrence, true
+			return false
+		}
+		return true
+	})
+	return next, found
+}
+
+// Advance moves the series starting at start past t. It returns the first
+// occurrence after t together with the rule for the series that begins
+// there, its COUNT reduced by the occurrences left behind, and false once the
+// series has ended.
+func (r *Rule) Advance(start, t time.Time) (time.Time, *Rule, bool) {
+	var next time.Time
+	passed := 0
+	found := false
+	r.each(start, t, func(occurrence time.Time, index int) bool {
+		if occurrence.After(t) {
+			next, passed, found = occurrence, index, true
+			return false
+		}
+		return true
+	})
+	if !found {
+		return time.Time{}, nil, false
+	}
+	rest := *r
+	if rest.Count > 0 {
+		rest.Count -= passed
+	}
+	return next, &rest, true
+}
+
+// Between returns the occurrences of the series starting at start that fall
+// in [from, to), at most limit of them.
+func (r *Rule) Between(start, from, to time.Time, limit int) []time.Time {
+	var occurrences []time.Time
+	r.each(start, from, func(occurrence time.Time, _ int) bool {
+		if !occurrence.Before(to) || len(occurrences) >= limit {
+			return false
+		}
+		if !occurrence.Before(from) {
+			occurrences = append(occurrences, occurrence)
+		}
+		return true
+	})
+	return occurrences
+}
+
+// each calls fn with every occurrence in order, along with how many came
+// before it, until fn returns false or the series ends. Occurrences before
+// from may be left out.
+func (r *Rule) each(start, from time.Time, fn func(occurrence time.Time, index int) bool) {
+	emitted := 0
+	emit := func(occurrence time.Time) bool {
+		if r.Count > 0 && emitted >= r.Count {
+			return false
+		}
+		if !r.Until.IsZero() && occurrence.After(r.Until) {
+			return false
+		}
+		emitted++
+		return fn(occurrence, emitted-1)
+	}
+
+	if !emit(start) {
+		return
+	}
+	first, before := r.skip(start, from)
+	if first > 0 {
+		emitted = before
+	}
+	for period := first; period < first+maxPeriods; period++ {
+		for _, occurrence := range r.period(start, period*r.Interval) {
+			if !occurrence.After(start) {
+				continue
+			}
+			if !emit(occurrence) {
+				return
+			}
+		}
+	}
+}
+
+// skip returns the period a daily or weekly expansion can start at to reach
+// from, kept one period short of it, and how many occurrences, start
+// included, come before that period. Other series start at period 0.
+func (r *Rule) skip(start, from time.Time) (int, int) {
+	if !from.After(start) {
+		return 0, 0
+	}
+	days := daysBetween(start, from)
+	switch {
+	case r.Freq == Daily || (r.Freq == Weekly && len(r.ByDay) == 0):
+		length := r.Interval
+		if r.Freq == Weekly {
+			length *= 7
+		}
+		period := days/length - 1
+		if period < 1 {
+			return 0, 0
+		}
+		// one occurrence a period, the one of period 0 being start
+		return period, period
+	case r.Freq == Weekly:
+		offset := (int(start.Weekday()) + 6) % 7
+		period := (days+offset)/(7*r.Interval) - 1
+		if period < 1 {
+			return 0, 0
+		}
+		later := 0
+		for _, weekday := range r.ByDay {
+			if (int(weekday)+6)%7 > offset {
+				later++
+			}
+		}
+		return period, 1 + later + (period-1)*len(r.ByDay)
+	}
+	return 0, 0
+}
+
+// daysBetween counts the calendar days from a to b, in a's time zone.
+func daysBetween(a, b time.Time) int {
+	ay, am, ad := a.Date()
+	by, bm, bd := b.In(a.Location()).Date()
+	return int(time.Date(by, bm, bd, 0, 0, 0, 0, time.UTC).Sub(time.Date(ay, am, ad, 0, 0, 0, 0, time.UTC)).Hours() / 24)
+}
+
+// period returns the candidate occurrences, in order, of the n-th day, week,
+// month or year counted from start.
+func (r *Rule) period(start time.Time, n int) []time.Time {
+	year, month, day := start.Date()
+	hour, min, sec := start.Clock()
+	at := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, hour, min, sec, start.Nanosecond(), start.Location())
+	}
+
+	switch r.Freq {
+	case Daily:
+		return []time.Time{at(year, month, day+n)}
+	case Weekly:
+		if len(r.ByDay) == 0 {
+			return []time.Time{at(year, month, day+7*n)}
+		}
+		// weeks start on Monday, as RFC 5545 WKST defaults to MO
+		monday := day - (int(start.Weekday())+6)%7 + 7*n
+		days := make([]time.Time, 0, len(r.ByDay))
+		for _, weekday := range r.ByDay {
+			days = append(days, at(year, month, monday+(int(weekday)+6)%7))
+		}
+		sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+		return days
+	case Monthly:
+		first := at(year, month+time.Month(n), 1)
+		length := daysIn(first.Year(), first.Month())
+		monthDays := r.ByMonthDay
+		if len(monthDays) == 0 {
+			monthDays = []int{day}
+		}
+		days := make([]time.Time, 0, len(monthDays))
+		for _, monthDay := range monthDays {
+			if monthDay < 0 {
+				monthDay = length + monthDay + 1
+			}
+			// months without that day are skipped, not clamped
+			if monthDay >= 1 && monthDay <= length {
+				days = append(days, at(first.Year(), first.Month(), monthDay))
+			}
+		}
+		sort.Slice(days, func(i, j int) bool { return days[i].Before(days[j]) })
+		return days
+	case Yearly:
+		if day > daysIn(year+n, month) {
+			return nil
+		}
+		return []time.Time{at(year+n, month, day)}
+	}
+	return nil
+}
+
+func daysIn(year int, month time.Month) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, time.UTC).Day()
+}
+
+func positiveInt(value string) (int, error) {
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 1 {
+		return 0, fmt.Errorf("%q is not a positive number", value)
+	}
+	return n, nil
+}
+
+func parseUntil(value string) (time.Time, error) {
+	for _, layout := range []string{"20060102T150405Z", "20060102"} {
+		if until, err := time.Parse(layout, value); err == nil {
+			if layout == "20060102" {
+				// a date-only UNTIL includes that whole day
+				until = until.Add(24*time.Hour - time.Second)
+			}
+			return until, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("invalid UNTIL %q", value)
+}
+
+func parseByDay(value string) ([]time.Weekday, error) {
+	var days []time.Weekday
+	seen := make(map[time.Weekday]bool)
+	for _, name := range strings.Split(value, ",") {
+		day, ok := weekdays[name]
+		if !ok {
+			return nil, fmt.Errorf("invalid BYDAY %q", name)
+		}
+		if !seen[day] {
+			seen[day] = true
+			days = append(days, day)
+		}
+	}
+	return days, nil
+}
+
+func parseByMonthDay(value string) ([]int, error) {
+	var days []int
+	for _, part := range strings.Split(value, ",") {
+		day, err := strconv.Atoi(part)
+		if err != nil || day == 0 || day < -31 || day > 31 {
+			return nil, fmt.Errorf("invalid BYMONTHDAY %q", part)
+		}
+		days = append(days, day)
+	}
+	return days, nil
+}
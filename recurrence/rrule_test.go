@@ -0,0 +1,182 @@
+package recurrence
+
+import (
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"time"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		canonical string
+		wantErr   bool
+	}{
+		{name: "Shorthand", input: "weekly", canonical: "FREQ=WEEKLY"},
+		{name: "Prefix and case", input: "rrule:freq=daily;interval=3", canonical: "FREQ=DAILY;INTERVAL=3"},
+		{name: "By day", input: "FREQ=WEEKLY;BYDAY=MO,FR,MO", canonical: "FREQ=WEEKLY;BYDAY=MO,FR"},
+		{name: "By month day", input: "FREQ=MONTHLY;BYMONTHDAY=1,-1;COUNT=6", canonical: "FREQ=MONTHLY;BYMONTHDAY=1,-1;COUNT=6"},
+		{name: "Until date", input: "FREQ=DAILY;UNTIL=20230601", canonical: "FREQ=DAILY;UNTIL=20230601T235959Z"},
+		{name: "Missing FREQ", input: "INTERVAL=2", wantErr: true},
+		{name: "Unknown FREQ", input: "FREQ=HOURLY", wantErr: true},
+		{name: "Unsupported part", input: "FREQ=DAILY;BYHOUR=9", wantErr: true},
+		{name: "Zero interval", input: "FREQ=DAILY;INTERVAL=0", wantErr: true},
+		{name: "Count and until", input: "FREQ=DAILY;COUNT=2;UNTIL=20230601", wantErr: true},
+		{name: "By day on monthly", input: "FREQ=MONTHLY;BYDAY=MO", wantErr: true},
+		{name: "Invalid weekday", input: "FREQ=WEEKLY;BYDAY=XX", wantErr: true},
+		{name: "Duplicate part", input: "FREQ=DAILY;FREQ=WEEKLY", wantErr: true},
+		{name: "Empty", input: "", wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := Parse(tc.input)
+			if tc.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.canonical, rule.String())
+
+			// the canonical form parses back to the same rule
+			again, err := Parse(rule.String())
+			require.NoError(t, err)
+			assert.Equal(t, rule, again)
+		})
+	}
+}
+
+func TestNext(t *testing.T) {
+	day := func(y int, m time.Month, d int) time.Time {
+		return time.Date(y, m, d, 9, 0, 0, 0, time.UTC)
+	}
+
+	testCases := []struct {
+		name     string
+		rule     string
+		start    time.Time
+		after    time.Time
+		expected time.Time
+		ended    bool
+	}{
+		{name: "Daily", rule: "daily", start: day(2023, 5, 1), after: day(2023, 5, 1), expected: day(2023, 5, 2)},
+		{name: "Every other day", rule: "FREQ=DAILY;INTERVAL=2", start: day(2023, 5, 1), after: day(2023, 5, 2), expected: day(2023, 5, 3)},
+		{name: "Weekly", rule: "weekly", start: day(2023, 5, 1), after: day(2023, 5, 1), expected: day(2023, 5, 8)},
+		{name: "Weekdays across a weekend", rule: "FREQ=WEEKLY;BYDAY=MO,TU,WE,TH,FR", start: day(2023, 5, 1), after: day(2023, 5, 5), expected: day(2023, 5, 8)},
+		{name: "Biweekly by day", rule: "FREQ=WEEKLY;INTERVAL=2;BYDAY=MO,FR", start: day(2023, 5, 1), after: day(2023, 5, 5), expected: day(2023, 5, 15)},
+		{name: "Monthly skips short months", rule: "monthly", start: day(2023, 1, 31), after: day(2023, 1, 31), expected: day(2023, 3, 31)},
+		{name: "Last day of month", rule: "FREQ=MONTHLY;BYMONTHDAY=-1", start: day(2023, 1, 31), after: day(2023, 1, 31), expected: day(2023, 2, 28)},
+		{name: "Yearly leap day", rule: "yearly", start: day(2024, 2, 29), after: day(2024, 2, 29), expected: day(2028, 2, 29)},
+		{name: "After a gap", rule: "daily", start: day(2023, 5, 1), after: day(2023, 5, 10).Add(time.Hour), expected: day(2023, 5, 11)},
+		{name: "Count exhausted", rule: "FREQ=DAILY;COUNT=3", start: day(2023, 5, 1), after: day(2023, 5, 3), ended: true},
+		{name: "Until reached", rule: "FREQ=WEEKLY;UNTIL=20230510", start: day(2023, 5, 1), after: day(2023, 5, 8), ended: true},
+		{name: "Never matches", rule: "FREQ=MONTHLY;BYMONTHDAY=31;INTERVAL=12", start: day(2023, 2, 1), after: day(2023, 2, 1), ended: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			rule, err := Parse(tc.rule)
+			require.NoError(t, err)
+
+			next, ok := rule.Next(tc.start, tc.after)
+			assert.Equal(t, !tc.ended, ok)
+			if !tc.ended {
+				assert.Equal(t, tc.expected, next)
+			}
+		})
+	}
+}
+
+func TestBetween(t *testing.T) {
+	rule, err := Parse("FREQ=WEEKLY;BYDAY=TU,TH")
+	require.NoError(t, err)
+
+	start := time.Date(2023, 5, 4, 8, 30, 0, 0, time.UTC) // a Thursday
+	from := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+	to := time.Date(2023, 5, 16, 0, 0, 0, 0, time.UTC)
+
+	assert.Equal(t, []time.Time{
+		time.Date(2023, 5, 4, 8, 30, 0, 0, time.UTC),
+		time.Date(2023, 5, 9, 8, 30, 0, 0, time.UTC),
+		time.Date(2023, 5, 11, 8, 30, 0, 0, time.UTC),
+	}, rule.Between(start, from, to, 10))
+
+	assert.Len(t, rule.Between(start, from, to, 2), 2)
+}
+
+func TestAdvance(t *testing.T) {
+	rule, err := Parse("FREQ=DAILY;COUNT=5")
+	require.NoError(t, err)
+	start := time.Date(2023, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	next, rest, ok := rule.Advance(start, start)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2023, 5, 2, 9, 0, 0, 0, time.UTC), next)
+	assert.Equal(t, "FREQ=DAILY;COUNT=4", rest.String())
+
+	// missed occurrences are skipped and still count against COUNT
+	next, rest, ok = rule.Advance(start, time.Date(2023, 5, 3, 12, 0, 0, 0, time.UTC))
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2023, 5, 4, 9, 0, 0, 0, time.UTC), next)
+	assert.Equal(t, "FREQ=DAILY;COUNT=2", rest.String())
+
+	_, _, ok = rule.Advance(start, time.Date(2023, 5, 5, 9, 0, 0, 0, time.UTC))
+	assert.False(t, ok)
+}
+
+func TestStartIsFirstOccurrence(t *testing.T) {
+	rule, err := Parse("FREQ=WEEKLY;BYDAY=TU,TH;COUNT=3")
+	require.NoError(t, err)
+
+	start := time.Date(2023, 5, 1, 9, 0, 0, 0, time.UTC) // a Monday
+	assert.Equal(t, []time.Time{
+		start,
+		time.Date(2023, 5, 2, 9, 0, 0, 0, time.UTC),
+		time.Date(2023, 5, 4, 9, 0, 0, 0, time.UTC),
+	}, rule.Between(start, start, start.AddDate(0, 1, 0), 10))
+}
+
+func TestSkipAhead(t *testing.T) {
+	start := time.Date(1996, 3, 13, 9, 0, 0, 0, time.UTC) // a Wednesday
+	rules := []string{
+		"daily",
+		"FREQ=DAILY;INTERVAL=3;COUNT=5000",
+		"weekly",
+		"FREQ=WEEKLY;INTERVAL=2;COUNT=900",
+		"FREQ=WEEKLY;BYDAY=MO,WE,FR",
+		"FREQ=WEEKLY;INTERVAL=3;BYDAY=TU,SU;COUNT=700",
+		"FREQ=WEEKLY;BYDAY=MO;UNTIL=20100101",
+	}
+	for _, s := range rules {
+		rule, err := Parse(s)
+		require.NoError(t, err)
+
+		// walking every period from start, as a reference
+		var walked []time.Time
+		rule.each(start, start, func(occurrence time.Time, _ int) bool {
+			walked = append(walked, occurrence)
+			return len(walked) < 2000
+		})
+
+		for _, i := range []int{0, 1, 5, 99, len(walked) / 2, len(walked) - 2} {
+			next, rest, ok := rule.Advance(start, walked[i])
+			if !assert.True(t, ok, "%s after %s", s, walked[i]) {
+				continue
+			}
+			assert.Equal(t, walked[i+1], next, "%s after %s", s, walked[i])
+			if rule.Count > 0 {
+				assert.Equal(t, rule.Count-i-1, rest.Count, "%s after %s", s, walked[i])
+			}
+		}
+	}
+
+	// a series older than maxPeriods days still has a next occurrence
+	rule, err := Parse("daily")
+	require.NoError(t, err)
+	now := time.Date(2023, 6, 1, 12, 0, 0, 0, time.UTC)
+	next, ok := rule.Next(start, now)
+	require.True(t, ok)
+	assert.Equal(t, time.Date(2023, 6, 2, 9, 0, 0, 0, time.UTC), next)
+}
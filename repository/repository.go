@@ -1,13 +1,234 @@
 package repository
 
 import (
+	"errors"
+	"time"
 	"todoGin/model/entity"
 )
 
+// ErrVersionMismatch is returned by conditional writes when the todo has been
+// changed since the version the caller expected.
+var ErrVersionMismatch = errors.New("todo version mismatch")
+
+// Per-operation errors reported by TodoRepository.Bulk.
+var (
+	ErrTodoNotFound   = errors.New("todo not found")
+	ErrBulkRolledBack = errors.New("rolled back because another operation failed")
+	ErrBulkSkipped    = errors.New("skipped because another operation failed")
+)
+
+// ErrAnchorNotFound is returned by TodoRepository.Move when the todo to move
+// next to is missing, trashed, not the caller's or the moved todo itself.
+var ErrAnchorNotFound = errors.New("anchor todo not found")
+
+// ErrInvalidOrder is returned by SubtaskRepository.Reorder when the given IDs
+// are not exactly the todo's subtasks.
+var ErrInvalidOrder = errors.New("subtask order must list every subtask once")
+
+// ErrRecurrenceWithoutDueDate is returned by TodoRepository.Update when the
+// todo would be left with a recurrence rule but no due date to count from.
+var ErrRecurrenceWithoutDueDate = errors.New("recurring todo needs a due date")
+
+// ErrRevisionNotFound is returned by TodoRepository.Revert when the todo's
+// history does not record the revision to go back to.
+var ErrRevisionNotFound = errors.New("revision not found")
+
+// Errors returned by TodoRepository.Undo. Changes to subtasks and tags cannot
+// be undone; comments and attachments are passed over.
+var (
+	ErrNothingToUndo = errors.New("nothing to undo")
+	ErrCannotUndo    = errors.New("change cannot be undone")
+)
+
+// TodoQuery narrows and orders the rows returned by TodoRepository.GetAll.
+// A zero Limit means no limit; an empty Sort falls back to the user's manual
+// order, "position". Trashed switches the listing from live todos to
+// soft-deleted ones, and a non-nil ListID keeps only the todos of that list.
+// Tags keeps the todos carrying all of the named tags, or any of them unless
+// MatchAllTags is set.
+type TodoQuery struct {
+	Limit        int
+	Offset       int
+	Sort         string
+	Desc         bool
+	Status       *bool
+	Trashed      bool
+	ListID       *int64
+	Tags         []string
+	MatchAllTags bool
+}
+
+// UpdateOptions tunes TodoRepository.Update. Version is the version the
+// caller last saw, or 0 to write unconditionally. CascadeSubtasks completes
+// every subtask when the update completes the todo.
+type UpdateOptions struct {
+	Version         int64
+	CascadeSubtasks bool
+}
+
+const (
+	BulkCreate = "create"
+	BulkUpdate = "update"
+	BulkDelete = "delete"
+)
+
+// BulkOperation is one step of TodoRepository.Bulk. Create inserts Todo;
+// update applies Updates to TodoID and delete soft-deletes it, both
+// conditional on Version unless it is 0.
+type BulkOperation struct {
+	Op      string
+	TodoID  int64
+	Version int64
+	Todo    *entity.Todolist
+	Updates map[string]interface{}
+}
+
+// BulkResult is the outcome of the BulkOperation at the same index, with the
+// todo as persisted for creates and updates.
+type BulkResult struct {
+	Todo         *entity.Todolist
+	RowsAffected int64
+	Err          error
+}
+
+// TodoRepository methods are scoped to the owning user; a todo belonging to
+// someone else behaves exactly like a missing one. Delete takes the version
+// the caller last saw, or 0 to delete unconditionally. Completing a recurring
+// todo through Update creates its next occurrence as a new todo, and
+// Occurrences lists the due dates of open todos falling in [from, to).
+// DueSoon, MarkReminded and MarkReminderFailed, like Purge, work across all
+// users for the background workers. Every change records its todo events in
+// the outbox and its entry in the activity log within the same transaction.
+// Revert and Undo replay that log backwards, through Update for the todo's
+// fields.
 type TodoRepository interface {
-	GetAll() ([]entity.Todolist, error)
-	GetByID(todoID int64) (*entity.Todolist, error)
-	Create(title string) (*entity.Todolist, error)
-	Update(todoID int64, updates map[string]interface{}) (*entity.Todolist, error)
-	Delete(todoID int64) (int64, error)
+	GetAll(userID int64, query TodoQuery) ([]entity.Todolist, int64, error)
+	GetByID(userID, todoID int64) (*entity.Todolist, error)
+	Create(todo *entity.Todolist) (*entity.Todolist, error)
+	Update(userID, todoID int64, opts UpdateOptions, updates map[string]interface{}) (*entity.Todolist, int64, error)
+	Delete(userID, todoID, version int64) (int64, error)
+	Search(userID int64, query string, limit int) ([]entity.TodoSearchResult, error)
+	Restore(userID, todoID int64) (int64, error)
+	Purge(deletedBefore time.Time) (int64, error)
+	LastModified(userID int64) (time.Time, error)
+	Bulk(userID int64, ops []BulkOperation, atomic bool) ([]BulkResult, error)
+	Move(userID, todoID, version, anchorID int64, after bool) (*entity.Todolist, error)
+	Occurrences(userID int64, from, to time.Time) ([]entity.Occurrence, error)
+	DueSoon(now, before time.Time, limit int) ([]entity.Reminder, error)
+	MarkReminded(todoID int64, dueDate, at time.Time) (int64, error)
+	MarkReminderFailed(todoID int64, dueDate, retryAt time.Time) error
+	Revert(userID, todoID, revision int64, opts UpdateOptions) (*entity.Todolist, int64, error)
+	Undo(userID int64, since time.Time) ([]int64, error)
+}
+
+// ListRepository methods are scoped to the owning user like TodoRepository.
+// Deleting a list moves its todos to the trash.
+type ListRepository interface {
+	GetAll(userID int64, archived bool) ([]entity.List, error)
+	GetByID(userID, listID int64) (*entity.List, error)
+	Create(list *entity.List) (*entity.List, error)
+	Update(userID, listID int64, updates map[string]interface{}) (*entity.List, error)
+	Delete(userID, listID int64) (int64, error)
+}
+
+// SubtaskRepository methods are scoped to the user owning the parent todo.
+// Every change also bumps the parent's version; an update that changes
+// nothing leaves it alone. A missing todo or subtask yields nil or zero rows.
+type SubtaskRepository interface {
+	Create(userID, todoID int64, title string) (*entity.Subtask, error)
+	Update(userID, todoID, subtaskID int64, updates map[string]interface{}) (*entity.Subtask, error)
+	Delete(userID, todoID, subtaskID int64) (int64, error)
+	Reorder(userID, todoID int64, subtaskIDs []int64) ([]entity.Subtask, error)
+}
+
+// TagRepository methods are scoped to the owning user. Attaching and
+// detaching bump the todo's version when its tags actually change; a missing
+// todo yields nil or zero rows. Tag names compare case-insensitively.
+type TagRepository interface {
+	GetAll(userID int64) ([]entity.TagCount, error)
+	Attach(userID, todoID int64, names []string) ([]entity.Tag, error)
+	Detach(userID, todoID int64, name string) (int64, error)
+}
+
+// CommentRepository methods are scoped to the user owning the todo, and only
+// a comment's author may edit or delete it. Comments do not bump the todo's
+// version, but move its updated_at. A missing todo or comment yields nil or
+// zero rows; GetAll lists a todo's comments oldest first.
+type CommentRepository interface {
+	GetAll(userID, todoID int64) ([]entity.Comment, error)
+	Create(userID, todoID int64, body string) (*entity.Comment, error)
+	Update(userID, todoID, commentID int64, body string) (*entity.Comment, error)
+	Delete(userID, todoID, commentID int64) (int64, error)
+}
+
+// AttachmentRepository keeps the metadata of the files attached to todos,
+// scoped to the user owning the todo; the content is in a blob store. A
+// missing todo or attachment yields nil. Attachments outlive a soft delete but
+// not a purge: Orphans then lists the ones left behind, across all users, for
+// a sweeper to remove the content of and Forget.
+type AttachmentRepository interface {
+	GetAll(userID, todoID int64) ([]entity.Attachment, error)
+	GetByID(userID, todoID, attachmentID int64) (*entity.Attachment, error)
+	Create(attachment *entity.Attachment) (*entity.Attachment, error)
+	// Delete returns the attachment it removed, whose content is then the
+	// caller's to delete.
+	Delete(userID, todoID, attachmentID int64) (*entity.Attachment, error)
+	Orphans(limit int) ([]entity.Attachment, error)
+	Forget(attachmentID int64) error
+}
+
+// WebhookRepository methods taking a userID are scoped to the owner like
+// ListRepository. Enqueue queues a pending delivery of an event to each of
+// the user's active webhooks subscribed to it, at most once per event ID;
+// DueDeliveries and SaveAttempt serve the background deliverer across all
+// users.
+type WebhookRepository interface {
+	GetAll(userID int64) ([]entity.Webhook, error)
+	GetByID(userID, webhookID int64) (*entity.Webhook, error)
+	Create(webhook *entity.Webhook) (*entity.Webhook, error)
+	Update(userID, webhookID int64, updates map[string]interface{}) (*entity.Webhook, error)
+	Delete(userID, webhookID int64) (int64, error)
+	Enqueue(userID int64, eventID, event, payload string) (int64, error)
+	Deliveries(userID, webhookID int64, limit int) ([]entity.WebhookDelivery, error)
+	Replay(userID, webhookID, deliveryID int64) (*entity.WebhookDelivery, error)
+	DueDeliveries(now time.Time, limit int) ([]entity.WebhookDelivery, error)
+	SaveAttempt(delivery *entity.WebhookDelivery) error
+}
+
+// Outbox channels. Each has a relay of its own and keeps its own progress
+// through the events, so a sink that is down holds back only its channel.
+const (
+	// OutboxLocal feeds the in-process sinks: streams, WebSockets, webhooks.
+	OutboxLocal = "local"
+	// OutboxExternal feeds the sink OUTBOX_SINK configures.
+	OutboxExternal = "external"
+)
+
+// OutboxRepository serves the relays publishing the events todo changes
+// record in the outbox, across all users. MarkFailed gives up on the event
+// for the channel once it has failed maxAttempts times, which it reports.
+// Prune deletes the events every one of the given channels published or gave
+// up on before publishedBefore.
+type OutboxRepository interface {
+	Pending(channel string, limit int) ([]entity.OutboxEvent, error)
+	MarkPublished(channel string, id int64, at time.Time) error
+	MarkFailed(channel string, id int64, message string, at time.Time, maxAttempts int) (bool, error)
+	Prune(publishedBefore time.Time, channels []string) (int64, error)
+}
+
+// ActivityRepository reads the audit log the todo, list, subtask and tag
+// repositories append to in the transaction of each change. Pages are scoped
+// to the acting user, newest first, and come with the total count.
+type ActivityRepository interface {
+	History(userID, todoID int64, limit, offset int) ([]entity.Activity, int64, error)
+	Feed(userID int64, limit, offset int) ([]entity.Activity, int64, error)
+}
+
+// ErrUsernameTaken is returned by UserRepository.Create when another user
+// already has the username.
+var ErrUsernameTaken = errors.New("username already taken")
+
+type UserRepository interface {
+	Create(username, password string, email *string) (*entity.User, error)
+	GetByUsername(username string) (*entity.User, error)
 }
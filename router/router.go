@@ -7,23 +7,94 @@ import (
 )
 
 type RouteBuilder struct {
-	todoService *todoservice.Handler
+	todoService       *todoservice.Handler
+	listService       *todoservice.ListHandler
+	subtaskService    *todoservice.SubtaskHandler
+	tagService        *todoservice.TagHandler
+	commentService    *todoservice.CommentHandler
+	attachmentService *todoservice.AttachmentHandler
+	webhookService    *todoservice.WebhookHandler
+	activityService   *todoservice.ActivityHandler
+	streamService     *todoservice.StreamHandler
+	wsService         *todoservice.WSHandler
+	authService       *todoservice.AuthHandler
 }
 
-func NewRouteBuilder(todoService *todoservice.Handler) *RouteBuilder {
-	return &RouteBuilder{todoService: todoService}
+func NewRouteBuilder(todoService *todoservice.Handler, listService *todoservice.ListHandler, subtaskService *todoservice.SubtaskHandler, tagService *todoservice.TagHandler, commentService *todoservice.CommentHandler, attachmentService *todoservice.AttachmentHandler, webhookService *todoservice.WebhookHandler, activityService *todoservice.ActivityHandler, streamService *todoservice.StreamHandler, wsService *todoservice.WSHandler, authService *todoservice.AuthHandler) *RouteBuilder {
+	return &RouteBuilder{
+		todoService:       todoService,
+		listService:       listService,
+		subtaskService:    subtaskService,
+		tagService:        tagService,
+		commentService:    commentService,
+		attachmentService: attachmentService,
+		webhookService:    webhookService,
+		activityService:   activityService,
+		streamService:     streamService,
+		wsService:         wsService,
+		authService:       authService,
+	}
 }
 
 func (rb *RouteBuilder) RouteInit() *gin.Engine {
 
 	r := gin.New()
-	r.Use(gin.Recovery(), middleware.Logger(), middleware.BasicAuth())
+	r.Use(gin.Recovery(), middleware.Logger())
 
-	r.GET("/manage-todos", rb.todoService.TodolistHandlerGetAll)
-	r.POST("/manage-todo", rb.todoService.TodolistHandlerCreate)
-	r.GET("/manage-todo/todo/:id", rb.todoService.TodolistHandlerGetByID)
-	r.PUT("/manage-todo/todo/:id", rb.todoService.TodolistHandlerUpdate)
-	r.DELETE("/manage-todo/todo/:id", rb.todoService.TodolistHandlerDelete)
+	r.POST("/register", rb.authService.AuthHandlerRegister)
+	r.POST("/login", rb.authService.AuthHandlerLogin)
+
+	auth := r.Group("/", middleware.JWTAuth(rb.authService.JWTSecret))
+	auth.GET("/manage-todos", rb.todoService.TodolistHandlerGetAll)
+	auth.GET("/manage-todos/search", rb.todoService.TodolistHandlerSearch)
+	auth.GET("/manage-todos/trash", rb.todoService.TodolistHandlerGetTrash)
+	auth.GET("/manage-todos/occurrences", rb.todoService.TodolistHandlerOccurrences)
+	auth.GET("/manage-todos/stream", rb.streamService.StreamHandlerTodos)
+	auth.POST("/manage-todos/bulk", rb.todoService.TodolistHandlerBulk)
+	auth.POST("/manage-todo", rb.todoService.TodolistHandlerCreate)
+	auth.GET("/manage-todo/todo/:id", rb.todoService.TodolistHandlerGetByID)
+	auth.PUT("/manage-todo/todo/:id", rb.todoService.TodolistHandlerUpdate)
+	auth.PATCH("/manage-todo/todo/:id", rb.todoService.TodolistHandlerPatch)
+	auth.DELETE("/manage-todo/todo/:id", rb.todoService.TodolistHandlerDelete)
+	auth.POST("/manage-todo/todo/:id/restore", rb.todoService.TodolistHandlerRestore)
+	auth.POST("/manage-todo/todo/:id/move", rb.todoService.TodolistHandlerMove)
+	auth.POST("/manage-todo/todo/:id/subtasks", rb.subtaskService.SubtaskHandlerCreate)
+	auth.PUT("/manage-todo/todo/:id/subtasks/order", rb.subtaskService.SubtaskHandlerReorder)
+	auth.PATCH("/manage-todo/todo/:id/subtasks/:subtaskID", rb.subtaskService.SubtaskHandlerUpdate)
+	auth.DELETE("/manage-todo/todo/:id/subtasks/:subtaskID", rb.subtaskService.SubtaskHandlerDelete)
+	auth.POST("/manage-todo/todo/:id/tags", rb.tagService.TagHandlerAttach)
+	auth.DELETE("/manage-todo/todo/:id/tags/:tag", rb.tagService.TagHandlerDetach)
+	auth.GET("/manage-todo/todo/:id/comments", rb.commentService.CommentHandlerGetAll)
+	auth.POST("/manage-todo/todo/:id/comments", rb.commentService.CommentHandlerCreate)
+	auth.PATCH("/manage-todo/todo/:id/comments/:commentID", rb.commentService.CommentHandlerUpdate)
+	auth.DELETE("/manage-todo/todo/:id/comments/:commentID", rb.commentService.CommentHandlerDelete)
+	auth.GET("/manage-todo/todo/:id/attachments", rb.attachmentService.AttachmentHandlerGetAll)
+	auth.POST("/manage-todo/todo/:id/attachments", rb.attachmentService.AttachmentHandlerUpload)
+	auth.GET("/manage-todo/todo/:id/attachments/:attachmentID", rb.attachmentService.AttachmentHandlerDownload)
+	auth.DELETE("/manage-todo/todo/:id/attachments/:attachmentID", rb.attachmentService.AttachmentHandlerDelete)
+	auth.GET("/manage-todo/todo/:id/history", rb.activityService.ActivityHandlerHistory)
+	auth.POST("/manage-todo/todo/:id/revert", rb.activityService.ActivityHandlerRevert)
+	auth.GET("/activity", rb.activityService.ActivityHandlerFeed)
+	auth.POST("/undo", rb.activityService.ActivityHandlerUndo)
+	auth.GET("/tags", rb.tagService.TagHandlerGetAll)
+
+	auth.GET("/lists", rb.listService.ListHandlerGetAll)
+	auth.POST("/lists", rb.listService.ListHandlerCreate)
+	auth.GET("/lists/:listID", rb.listService.ListHandlerGetByID)
+	auth.PATCH("/lists/:listID", rb.listService.ListHandlerUpdate)
+	auth.DELETE("/lists/:listID", rb.listService.ListHandlerDelete)
+	auth.GET("/lists/:listID/todos", rb.listService.ListHandlerGetTodos)
+	auth.POST("/lists/:listID/todos", rb.listService.ListHandlerCreateTodo)
+
+	auth.GET("/ws", rb.wsService.WSHandlerConnect)
+
+	auth.GET("/webhooks", rb.webhookService.WebhookHandlerGetAll)
+	auth.POST("/webhooks", rb.webhookService.WebhookHandlerCreate)
+	auth.GET("/webhooks/:webhookID", rb.webhookService.WebhookHandlerGetByID)
+	auth.PATCH("/webhooks/:webhookID", rb.webhookService.WebhookHandlerUpdate)
+	auth.DELETE("/webhooks/:webhookID", rb.webhookService.WebhookHandlerDelete)
+	auth.GET("/webhooks/:webhookID/deliveries", rb.webhookService.WebhookHandlerDeliveries)
+	auth.POST("/webhooks/:webhookID/deliveries/:deliveryID/replay", rb.webhookService.WebhookHandlerReplay)
 
 	return r
 }
@@ -0,0 +1,192 @@
+package service
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+	"todoGin/model/entity"
+	"todoGin/model/request"
+	"todoGin/model/respErr"
+	"todoGin/repository"
+)
+
+type ActivityHandler struct {
+	ActivityRepository repository.ActivityRepository
+	TodoRepository     repository.TodoRepository
+	UndoWindow         time.Duration
+}
+
+// NewActivityService builds the history handler. POST /undo reverts changes
+// made up to undoWindow ago.
+func NewActivityService(activityRepo repository.ActivityRepository, todoRepo repository.TodoRepository, undoWindow time.Duration) *ActivityHandler {
+	return &ActivityHandler{
+		ActivityRepository: activityRepo,
+		TodoRepository:     todoRepo,
+		UndoWindow:         undoWindow,
+	}
+}
+
+// ActivityHandlerHistory lists what happened to a todo, newest first. The
+// history of a deleted or purged todo stays readable.
+func (h *ActivityHandler) ActivityHandlerHistory(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	queryReq, ok := bindActivityQuery(ctx)
+	if !ok {
+		return
+	}
+	limit, offset := queryReq.Page()
+	activities, total, err := h.ActivityRepository.History(currentUserID(ctx), todoID, limit, offset)
+	if err != nil {
+		logrus.Errorf("failed when get todo history: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if total == 0 {
+		// todos from before the activity log have no history yet
+		todo, err := h.TodoRepository.GetByID(currentUserID(ctx), todoID)
+		if err != nil {
+			logrus.Errorf("failed when get todo by id: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+				Message: "Internal Server Error",
+				Status:  http.StatusInternalServerError,
+			})
+			return
+		}
+		if todo == nil {
+			ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+				Message: "Not Found",
+				Status:  http.StatusNotFound,
+			})
+			return
+		}
+	}
+	renderActivities(ctx, activities, total, limit, offset, "Success Get History")
+}
+
+// ActivityHandlerFeed lists the activity across all of the caller's todos,
+// newest first.
+func (h *ActivityHandler) ActivityHandlerFeed(ctx *gin.Context) {
+	queryReq, ok := bindActivityQuery(ctx)
+	if !ok {
+		return
+	}
+	limit, offset := queryReq.Page()
+	activities, total, err := h.ActivityRepository.Feed(currentUserID(ctx), limit, offset)
+	if err != nil {
+		logrus.Errorf("failed when get activity feed: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	renderActivities(ctx, activities, total, limit, offset, "Success Get Activity")
+}
+
+// ActivityHandlerRevert puts the todo's fields back the way they were at
+// ?to=<revision>, as a new change. If-Match makes it conditional like PATCH.
+func (h *ActivityHandler) ActivityHandlerRevert(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	queryReq := new(request.TodolistRevertRequest)
+	if err := ctx.ShouldBindQuery(queryReq); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid query",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+	opts := repository.UpdateOptions{Version: version}
+	todo, rowsAffected, err := h.TodoRepository.Revert(currentUserID(ctx), todoID, queryReq.To, opts)
+	if errors.Is(err, repository.ErrRevisionNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Revision not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	respondUpdate(ctx, todo, rowsAffected, err, "Success Revert Todo")
+}
+
+// ActivityHandlerUndo reverts the caller's last change, all of a bulk
+// request at once, if it is recent enough.
+func (h *ActivityHandler) ActivityHandlerUndo(ctx *gin.Context) {
+	todoIDs, err := h.TodoRepository.Undo(currentUserID(ctx), time.Now().Add(-h.UndoWindow))
+	switch {
+	case errors.Is(err, repository.ErrNothingToUndo):
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Nothing to undo",
+			Status:  http.StatusNotFound,
+		})
+		return
+	case errors.Is(err, repository.ErrCannotUndo):
+		ctx.AbortWithStatusJSON(http.StatusConflict, respErr.ErrorResponse{
+			Message: "Change cannot be undone",
+			Status:  http.StatusConflict,
+		})
+		return
+	case errors.Is(err, repository.ErrTodoNotFound):
+		ctx.AbortWithStatusJSON(http.StatusConflict, respErr.ErrorResponse{
+			Message: "Todo no longer exists",
+			Status:  http.StatusConflict,
+		})
+		return
+	case err != nil:
+		logrus.Errorf("failed when undoing: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Undo")
+	ctx.JSON(http.StatusOK, request.TodoIDResponse{
+		Message: "Success Undo",
+		Data:    todoIDs,
+	})
+}
+
+func bindActivityQuery(ctx *gin.Context) (*request.ActivityQueryRequest, bool) {
+	queryReq := new(request.ActivityQueryRequest)
+	if err := ctx.ShouldBindQuery(queryReq); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid query",
+			Status:  http.StatusBadRequest,
+		})
+		return nil, false
+	}
+	return queryReq, true
+}
+
+func renderActivities(ctx *gin.Context, activities []entity.Activity, total int64, limit, offset int, message string) {
+	logrus.Info(http.StatusOK, " ", message)
+	var nextOffset *int
+	if next := offset + len(activities); int64(next) < total {
+		nextOffset = &next
+	}
+	ctx.JSON(http.StatusOK, request.ActivitiesResponse{
+		Message:    message,
+		Data:       len(activities),
+		Total:      total,
+		Limit:      limit,
+		Offset:     offset,
+		NextOffset: nextOffset,
+		Activities: activities,
+	})
+}
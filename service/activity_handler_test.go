@@ -0,0 +1,251 @@
+package service
+
+import (
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todoGin/mocks"
+	"todoGin/model/entity"
+	"todoGin/model/request"
+	"todoGin/model/respErr"
+	"todoGin/repository"
+)
+
+func TestActivityHandlers(t *testing.T) {
+	page := []entity.Activity{
+		{ID: 12, UserID: testUserID, TodoID: 1, Revision: 3, Action: entity.ActivityUpdated, Changes: entity.Changes{
+			"title": {From: "deploy", To: "deploy v2"},
+		}},
+		{ID: 10, UserID: testUserID, TodoID: 1, Revision: 2, Action: entity.ActivityTagged},
+	}
+	next := 2
+
+	testCases := []struct {
+		name               string
+		url                string
+		mock               func(activityRepo *mocks.ActivityRepository, todoRepo *mocks.TodoRepository)
+		expectedStatus     int
+		expectedMessage    string
+		expectedNextOffset *int
+	}{
+		{
+			name: "History",
+			url:  "/manage-todo/todo/1/history?limit=2",
+			mock: func(activityRepo *mocks.ActivityRepository, todoRepo *mocks.TodoRepository) {
+				activityRepo.On("History", testUserID, int64(1), 2, 0).Return(page, int64(3), nil)
+			},
+			expectedStatus:     http.StatusOK,
+			expectedMessage:    "Success Get History",
+			expectedNextOffset: &next,
+		},
+		{
+			name: "History of a todo from before the log",
+			url:  "/manage-todo/todo/1/history",
+			mock: func(activityRepo *mocks.ActivityRepository, todoRepo *mocks.TodoRepository) {
+				activityRepo.On("History", testUserID, int64(1), request.DefaultPageLimit, 0).Return(nil, int64(0), nil)
+				todoRepo.On("GetByID", testUserID, int64(1)).Return(&entity.Todolist{ID: 1}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Get History",
+		},
+		{
+			name: "History of missing todo",
+			url:  "/manage-todo/todo/2/history",
+			mock: func(activityRepo *mocks.ActivityRepository, todoRepo *mocks.TodoRepository) {
+				activityRepo.On("History", testUserID, int64(2), request.DefaultPageLimit, 0).Return(nil, int64(0), nil)
+				todoRepo.On("GetByID", testUserID, int64(2)).Return(nil, nil)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "Not Found",
+		},
+		{
+			name:            "History with invalid limit",
+			url:             "/manage-todo/todo/1/history?limit=500",
+			mock:            func(activityRepo *mocks.ActivityRepository, todoRepo *mocks.TodoRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid query",
+		},
+		{
+			name: "Feed last page",
+			url:  "/activity?limit=2&offset=1",
+			mock: func(activityRepo *mocks.ActivityRepository, todoRepo *mocks.TodoRepository) {
+				activityRepo.On("Feed", testUserID, 2, 1).Return(page, int64(3), nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Get Activity",
+		},
+		{
+			name: "Feed error",
+			url:  "/activity",
+			mock: func(activityRepo *mocks.ActivityRepository, todoRepo *mocks.TodoRepository) {
+				activityRepo.On("Feed", testUserID, request.DefaultPageLimit, 0).Return(nil, int64(0), errors.New("some error"))
+			},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "Internal Server Error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			activityRepo := mocks.NewActivityRepository(t)
+			todoRepo := mocks.NewTodoRepository(t)
+			tc.mock(activityRepo, todoRepo)
+			handler := NewActivityService(activityRepo, todoRepo, 5*time.Minute)
+
+			router := gin.Default()
+			router.GET("/manage-todo/todo/:id/history", withUser, handler.ActivityHandlerHistory)
+			router.GET("/activity", withUser, handler.ActivityHandlerFeed)
+
+			w := httptest.NewRecorder()
+			r, err := http.NewRequest(http.MethodGet, tc.url, nil)
+			require.NoError(t, err)
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var resp request.ActivitiesResponse
+			err = json.Unmarshal(w.Body.Bytes(), &resp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMessage, resp.Message)
+			assert.Equal(t, tc.expectedNextOffset, resp.NextOffset)
+		})
+	}
+}
+
+func TestRevertAndUndo(t *testing.T) {
+	testCases := []struct {
+		name            string
+		url             string
+		header          map[string]string
+		mock            func(todoRepo *mocks.TodoRepository)
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:   "Revert",
+			url:    "/manage-todo/todo/1/revert?to=2",
+			header: map[string]string{"If-Match": `"4"`},
+			mock: func(todoRepo *mocks.TodoRepository) {
+				todoRepo.On("Revert", testUserID, int64(1), int64(2), repository.UpdateOptions{Version: 4}).
+					Return(&entity.Todolist{ID: 1, Version: 5}, int64(1), nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Revert Todo",
+		},
+		{
+			name: "Revert to current state",
+			url:  "/manage-todo/todo/1/revert?to=4",
+			mock: func(todoRepo *mocks.TodoRepository) {
+				todoRepo.On("Revert", testUserID, int64(1), int64(4), repository.UpdateOptions{}).
+					Return(&entity.Todolist{ID: 1, Version: 4}, int64(0), nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Not Change",
+		},
+		{
+			name:            "Revert without revision",
+			url:             "/manage-todo/todo/1/revert",
+			mock:            func(todoRepo *mocks.TodoRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid query",
+		},
+		{
+			name: "Revert to unknown revision",
+			url:  "/manage-todo/todo/1/revert?to=9",
+			mock: func(todoRepo *mocks.TodoRepository) {
+				todoRepo.On("Revert", testUserID, int64(1), int64(9), repository.UpdateOptions{}).
+					Return(nil, int64(0), repository.ErrRevisionNotFound)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "Revision not Found",
+		},
+		{
+			name:   "Revert stale version",
+			url:    "/manage-todo/todo/1/revert?to=2",
+			header: map[string]string{"If-Match": `"3"`},
+			mock: func(todoRepo *mocks.TodoRepository) {
+				todoRepo.On("Revert", testUserID, int64(1), int64(2), repository.UpdateOptions{Version: 3}).
+					Return(nil, int64(0), repository.ErrVersionMismatch)
+			},
+			expectedStatus:  http.StatusPreconditionFailed,
+			expectedMessage: "Precondition Failed",
+		},
+		{
+			name: "Undo",
+			url:  "/undo",
+			mock: func(todoRepo *mocks.TodoRepository) {
+				todoRepo.On("Undo", testUserID, mock.AnythingOfType("time.Time")).Return([]int64{3, 1}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Undo",
+		},
+		{
+			name: "Undo with nothing recent",
+			url:  "/undo",
+			mock: func(todoRepo *mocks.TodoRepository) {
+				todoRepo.On("Undo", testUserID, mock.AnythingOfType("time.Time")).Return(nil, repository.ErrNothingToUndo)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "Nothing to undo",
+		},
+		{
+			name: "Undo subtask change",
+			url:  "/undo",
+			mock: func(todoRepo *mocks.TodoRepository) {
+				todoRepo.On("Undo", testUserID, mock.AnythingOfType("time.Time")).Return(nil, repository.ErrCannotUndo)
+			},
+			expectedStatus:  http.StatusConflict,
+			expectedMessage: "Change cannot be undone",
+		},
+		{
+			name: "Undo purged todo",
+			url:  "/undo",
+			mock: func(todoRepo *mocks.TodoRepository) {
+				todoRepo.On("Undo", testUserID, mock.AnythingOfType("time.Time")).Return(nil, repository.ErrTodoNotFound)
+			},
+			expectedStatus:  http.StatusConflict,
+			expectedMessage: "Todo no longer exists",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			todoRepo := mocks.NewTodoRepository(t)
+			tc.mock(todoRepo)
+			handler := NewActivityService(mocks.NewActivityRepository(t), todoRepo, 5*time.Minute)
+
+			router := gin.Default()
+			router.POST("/manage-todo/todo/:id/revert", withUser, handler.ActivityHandlerRevert)
+			router.POST("/undo", withUser, handler.ActivityHandlerUndo)
+
+			w := httptest.NewRecorder()
+			r, err := http.NewRequest(http.MethodPost, tc.url, nil)
+			require.NoError(t, err)
+			for key, value := range tc.header {
+				r.Header.Set(key, value)
+			}
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.expectedMessage == "Success Revert Todo" {
+				var resp request.TodoUpdateResponse
+				err = json.Unmarshal(w.Body.Bytes(), &resp)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedMessage, resp.Message)
+				return
+			}
+			var resp respErr.ErrorResponse
+			err = json.Unmarshal(w.Body.Bytes(), &resp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMessage, resp.Message)
+		})
+	}
+}
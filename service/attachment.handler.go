@@ -0,0 +1,322 @@
+package service
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strconv"
+	"strings"
+	"todoGin/model/entity"
+	"todoGin/model/request"
+	"todoGin/model/respErr"
+	"todoGin/repository"
+	"todoGin/storage"
+)
+
+// multipartOverhead is how much bigger than the file itself an upload's
+// body may be, for the multipart framing and the other form fields.
+const multipartOverhead = 64 << 10
+
+type AttachmentHandler struct {
+	AttachmentRepository repository.AttachmentRepository
+	BlobStore            storage.BlobStore
+	MaxSize              int64
+}
+
+// NewAttachmentService builds the attachment handler, which keeps file
+// contents in store and takes files of up to maxSize bytes.
+func NewAttachmentService(attachmentRepo repository.AttachmentRepository, store storage.BlobStore, maxSize int64) *AttachmentHandler {
+	return &AttachmentHandler{
+		AttachmentRepository: attachmentRepo,
+		BlobStore:            store,
+		MaxSize:              maxSize,
+	}
+}
+
+func (h *AttachmentHandler) AttachmentHandlerGetAll(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	attachments, err := h.AttachmentRepository.GetAll(currentUserID(ctx), todoID)
+	if err != nil {
+		logrus.Errorf("failed when get attachments: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if attachments == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "ID not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Get Attachments")
+	ctx.JSON(http.StatusOK, request.AttachmentsResponse{
+		Message:     "Success Get Attachments",
+		Data:        len(attachments),
+		Attachments: attachments,
+	})
+}
+
+// AttachmentHandlerUpload stores the "file" of a multipart form. The content
+// type is sniffed from the content rather than taken from the client, and
+// the content is checked against the optional "sha256" field.
+func (h *AttachmentHandler) AttachmentHandlerUpload(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	limit := h.MaxSize + multipartOverhead
+	if ctx.Request.ContentLength > limit {
+		tooLarge(ctx, h.MaxSize)
+		return
+	}
+	ctx.Request.Body = http.MaxBytesReader(ctx.Writer, ctx.Request.Body, limit)
+	reqBody := new(request.AttachmentUploadRequest)
+	if err := ctx.ShouldBind(reqBody); err != nil {
+		var maxBytesErr *http.MaxBytesError
+		if errors.As(err, &maxBytesErr) {
+			tooLarge(ctx, h.MaxSize)
+			return
+		}
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid input",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	file, err := reqBody.File.Open()
+	if err != nil {
+		logrus.Errorf("failed when opening upload: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	defer file.Close()
+
+	key, err := storage.NewKey()
+	if err != nil {
+		logrus.Errorf("failed when creating attachment key: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	blob, err := storage.Save(ctx.Request.Context(), h.BlobStore, key, file, h.MaxSize)
+	if errors.Is(err, storage.ErrTooLarge) {
+		tooLarge(ctx, h.MaxSize)
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when storing attachment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if reqBody.SHA256 != "" && !strings.EqualFold(reqBody.SHA256, blob.Checksum) {
+		h.deleteBlob(key)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Checksum mismatch",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	attachment, err := h.AttachmentRepository.Create(&entity.Attachment{
+		TodoID:      todoID,
+		UserID:      currentUserID(ctx),
+		Filename:    cleanFilename(reqBody.File.Filename),
+		ContentType: blob.ContentType,
+		Size:        blob.Size,
+		Checksum:    blob.Checksum,
+		StorageKey:  key,
+	})
+	if err != nil {
+		h.deleteBlob(key)
+		logrus.Errorf("failed when creating attachment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if attachment == nil {
+		h.deleteBlob(key)
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "ID not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusCreated, " Success Upload Attachment")
+	ctx.JSON(http.StatusCreated, request.AttachmentResponse{
+		Status:  http.StatusCreated,
+		Message: "New Attachment Created",
+		Data:    *attachment,
+	})
+}
+
+// AttachmentHandlerDownload sends the content of an attachment, verifying it
+// against its checksum on the way. Its ETag is the quoted checksum, which the
+// Digest header carries too.
+func (h *AttachmentHandler) AttachmentHandlerDownload(ctx *gin.Context) {
+	attachment, ok := h.findAttachment(ctx)
+	if !ok {
+		return
+	}
+	if notModified(ctx, strconv.Quote(attachment.Checksum), attachment.CreatedAt) {
+		return
+	}
+	content, err := h.BlobStore.Get(ctx.Request.Context(), attachment.StorageKey)
+	if err != nil {
+		logrus.Errorf("failed when opening attachment %d: %v", attachment.ID, err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	defer content.Close()
+
+	ctx.Header("Content-Type", attachment.ContentType)
+	ctx.Header("Content-Length", strconv.FormatInt(attachment.Size, 10))
+	ctx.Header("Content-Disposition", contentDisposition(attachment.Filename))
+	ctx.Header("X-Content-Type-Options", "nosniff")
+	if sum, err := hex.DecodeString(attachment.Checksum); err == nil {
+		ctx.Header("Digest", "sha-256="+base64.StdEncoding.EncodeToString(sum))
+	}
+	ctx.Status(http.StatusOK)
+	// written by hand rather than rendered: gin panics on a failed render,
+	// and once the headers are out all that is left is to cut the body short
+	if _, err := io.Copy(ctx.Writer, storage.Verify(content, attachment.Size, attachment.Checksum)); err != nil {
+		logrus.Errorf("failed when sending attachment %d: %v", attachment.ID, err)
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Download Attachment")
+}
+
+// AttachmentHandlerDelete removes an attachment and then its content.
+func (h *AttachmentHandler) AttachmentHandlerDelete(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	attachmentID, ok := int64Param(ctx, "attachmentID")
+	if !ok {
+		return
+	}
+	attachment, err := h.AttachmentRepository.Delete(currentUserID(ctx), todoID, attachmentID)
+	if err != nil {
+		logrus.Errorf("failed when deleting attachment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if attachment == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	h.deleteBlob(attachment.StorageKey)
+	logrus.Info(http.StatusOK, " Success Delete Attachment")
+	ctx.JSON(http.StatusOK, request.TodoDeleteResponse{
+		Status:  http.StatusOK,
+		Message: "Success Delete Attachment",
+	})
+}
+
+func (h *AttachmentHandler) findAttachment(ctx *gin.Context) (*entity.Attachment, bool) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return nil, false
+	}
+	attachmentID, ok := int64Param(ctx, "attachmentID")
+	if !ok {
+		return nil, false
+	}
+	attachment, err := h.AttachmentRepository.GetByID(currentUserID(ctx), todoID, attachmentID)
+	if err != nil {
+		logrus.Errorf("failed when get attachment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	if attachment == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, false
+	}
+	return attachment, true
+}
+
+// deleteBlob drops content no attachment refers to. A failure only leaves an
+// unreferenced blob behind, so it is logged rather than reported.
+func (h *AttachmentHandler) deleteBlob(key string) {
+	if err := h.BlobStore.Delete(context.Background(), key); err != nil {
+		logrus.Errorf("failed when deleting blob %s: %v", key, err)
+	}
+}
+
+func tooLarge(ctx *gin.Context, maxSize int64) {
+	ctx.AbortWithStatusJSON(http.StatusRequestEntityTooLarge, respErr.ErrorResponse{
+		Message: fmt.Sprintf("File larger than %d bytes", maxSize),
+		Status:  http.StatusRequestEntityTooLarge,
+	})
+}
+
+// maxFilename is the length of the filename column, in characters.
+const maxFilename = 255
+
+// cleanFilename keeps only the last element of the name a client sent,
+// whichever separator it used, without control characters and cut to fit.
+func cleanFilename(name string) string {
+	name = path.Base(strings.ReplaceAll(name, `\`, "/"))
+	name = strings.Map(func(r rune) rune {
+		if r < 0x20 || r == 0x7f {
+			return -1
+		}
+		return r
+	}, name)
+	if runes := []rune(name); len(runes) > maxFilename {
+		name = string(runes[:maxFilename])
+	}
+	if name == "" || name == "." || name == ".." || name == "/" {
+		return "attachment"
+	}
+	return name
+}
+
+// contentDisposition makes the browser save the file rather than show it,
+// so that an uploaded page cannot run as part of the API's origin.
+func contentDisposition(filename string) string {
+	if disposition := mime.FormatMediaType("attachment", map[string]string{"filename": filename}); disposition != "" {
+		return disposition
+	}
+	return "attachment"
+}
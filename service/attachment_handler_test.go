@@ -0,0 +1,309 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"todoGin/mocks"
+	"todoGin/model/entity"
+	"todoGin/model/respErr"
+	"todoGin/storage"
+)
+
+const pngHeader = "\x89PNG\r\n\x1a\n"
+
+// uploadBody builds a multipart upload of content as the "file" field, with
+// the given extra form fields.
+func uploadBody(t *testing.T, filename, content string, fields map[string]string) (*bytes.Buffer, string) {
+	body := new(bytes.Buffer)
+	w := multipart.NewWriter(body)
+	for name, value := range fields {
+		require.NoError(t, w.WriteField(name, value))
+	}
+	if filename != "" {
+		part, err := w.CreateFormFile("file", filename)
+		require.NoError(t, err)
+		_, err = part.Write([]byte(content))
+		require.NoError(t, err)
+	}
+	require.NoError(t, w.Close())
+	return body, w.FormDataContentType()
+}
+
+func checksumOf(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+func TestAttachmentHandlerUpload(t *testing.T) {
+	content := pngHeader + strings.Repeat("x", 100)
+
+	testCases := []struct {
+		name            string
+		filename        string
+		content         string
+		fields          map[string]string
+		mock            func(repo *mocks.AttachmentRepository)
+		expectedStatus  int
+		expectedMessage string
+		expectedBlobs   int
+	}{
+		{
+			name:     "Success",
+			filename: `C:\Users\raihan\receipt.png`,
+			content:  content,
+			fields:   map[string]string{"sha256": strings.ToUpper(checksumOf(content))},
+			mock: func(repo *mocks.AttachmentRepository) {
+				repo.On("Create", mock.MatchedBy(func(a *entity.Attachment) bool {
+					return a.TodoID == 1 && a.UserID == testUserID && a.Filename == "receipt.png" &&
+						a.ContentType == "image/png" && a.Size == int64(len(content)) &&
+						a.Checksum == checksumOf(content) && a.StorageKey != ""
+				})).Return(func(a *entity.Attachment) (*entity.Attachment, error) {
+					a.ID = 9
+					return a, nil
+				})
+			},
+			expectedStatus:  http.StatusCreated,
+			expectedMessage: "New Attachment Created",
+			expectedBlobs:   1,
+		},
+		{
+			name:            "Checksum mismatch",
+			filename:        "receipt.png",
+			content:         content,
+			fields:          map[string]string{"sha256": checksumOf("something else")},
+			mock:            func(repo *mocks.AttachmentRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Checksum mismatch",
+		},
+		{
+			name:            "Malformed checksum",
+			filename:        "receipt.png",
+			content:         content,
+			fields:          map[string]string{"sha256": "abc"},
+			mock:            func(repo *mocks.AttachmentRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+		{
+			name:            "Too large",
+			filename:        "big.bin",
+			content:         strings.Repeat("x", 2000),
+			mock:            func(repo *mocks.AttachmentRepository) {},
+			expectedStatus:  http.StatusRequestEntityTooLarge,
+			expectedMessage: "File larger than 1024 bytes",
+		},
+		{
+			name:            "Missing file",
+			mock:            func(repo *mocks.AttachmentRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+		{
+			name:     "Missing todo",
+			filename: "receipt.png",
+			content:  content,
+			mock: func(repo *mocks.AttachmentRepository) {
+				repo.On("Create", mock.Anything).Return(nil, nil)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "ID not Found",
+		},
+		{
+			name:     "Repository error",
+			filename: "receipt.png",
+			content:  content,
+			mock: func(repo *mocks.AttachmentRepository) {
+				repo.On("Create", mock.Anything).Return(nil, errors.New("some error"))
+			},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "Internal Server Error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			store, err := storage.NewLocalStore(dir)
+			require.NoError(t, err)
+			repo := mocks.NewAttachmentRepository(t)
+			tc.mock(repo)
+			handler := NewAttachmentService(repo, store, 1024)
+
+			router := gin.Default()
+			router.POST("/manage-todo/todo/:id/attachments", withUser, handler.AttachmentHandlerUpload)
+
+			body, contentType := uploadBody(t, tc.filename, tc.content, tc.fields)
+			w := httptest.NewRecorder()
+			r, err := http.NewRequest(http.MethodPost, "/manage-todo/todo/1/attachments", body)
+			require.NoError(t, err)
+			r.Header.Set("Content-Type", contentType)
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var resp respErr.ErrorResponse
+			err = json.Unmarshal(w.Body.Bytes(), &resp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMessage, resp.Message)
+			// refused uploads leave no content behind
+			assert.Equal(t, tc.expectedBlobs, countBlobs(t, dir))
+		})
+	}
+}
+
+func TestAttachmentHandlerUploadRejectsOversizedBody(t *testing.T) {
+	store, err := storage.NewLocalStore(t.TempDir())
+	require.NoError(t, err)
+	handler := NewAttachmentService(mocks.NewAttachmentRepository(t), store, 1024)
+
+	router := gin.Default()
+	router.POST("/manage-todo/todo/:id/attachments", withUser, handler.AttachmentHandlerUpload)
+
+	body, contentType := uploadBody(t, "big.bin", strings.Repeat("x", 1024+multipartOverhead), nil)
+	w := httptest.NewRecorder()
+	r, err := http.NewRequest(http.MethodPost, "/manage-todo/todo/1/attachments", body)
+	require.NoError(t, err)
+	r.Header.Set("Content-Type", contentType)
+	// a body of unannounced length is cut off as it is read
+	r.ContentLength = -1
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+}
+
+func TestAttachmentHandlerDownload(t *testing.T) {
+	content := pngHeader + strings.Repeat("x", 100)
+	attachment := &entity.Attachment{
+		ID: 9, TodoID: 1, UserID: testUserID, Filename: "receipt (1).png", ContentType: "image/png",
+		Size: int64(len(content)), Checksum: checksumOf(content), StorageKey: "abcdef",
+	}
+
+	testCases := []struct {
+		name           string
+		stored         string
+		ifNoneMatch    string
+		attachment     *entity.Attachment
+		expectedStatus int
+		expectedBody   string
+	}{
+		{name: "Success", stored: content, attachment: attachment, expectedStatus: http.StatusOK, expectedBody: content},
+		{name: "Not modified", stored: content, ifNoneMatch: `"` + checksumOf(content) + `"`, attachment: attachment, expectedStatus: http.StatusNotModified},
+		// the last chunk is withheld, leaving the body short of its length
+		{name: "Corrupted", stored: pngHeader + strings.Repeat("y", 100), attachment: attachment, expectedStatus: http.StatusOK},
+		{name: "Not found", expectedStatus: http.StatusNotFound},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store, err := storage.NewLocalStore(t.TempDir())
+			require.NoError(t, err)
+			require.NoError(t, store.Put(context.Background(), "abcdef", strings.NewReader(tc.stored)))
+			repo := mocks.NewAttachmentRepository(t)
+			repo.On("GetByID", testUserID, int64(1), int64(9)).Return(tc.attachment, nil)
+			handler := NewAttachmentService(repo, store, 1024)
+
+			router := gin.Default()
+			router.GET("/manage-todo/todo/:id/attachments/:attachmentID", withUser, handler.AttachmentHandlerDownload)
+
+			w := httptest.NewRecorder()
+			r, err := http.NewRequest(http.MethodGet, "/manage-todo/todo/1/attachments/9", nil)
+			require.NoError(t, err)
+			if tc.ifNoneMatch != "" {
+				r.Header.Set("If-None-Match", tc.ifNoneMatch)
+			}
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+			assert.Equal(t, tc.expectedBody, w.Body.String())
+			assert.Equal(t, "image/png", w.Header().Get("Content-Type"))
+			assert.Equal(t, strconv.Itoa(len(content)), w.Header().Get("Content-Length"))
+			assert.Equal(t, `attachment; filename="receipt (1).png"`, w.Header().Get("Content-Disposition"))
+			assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+			assert.True(t, strings.HasPrefix(w.Header().Get("Digest"), "sha-256="))
+		})
+	}
+}
+
+func TestAttachmentHandlerDelete(t *testing.T) {
+	testCases := []struct {
+		name            string
+		deleted         *entity.Attachment
+		deleteErr       error
+		expectedStatus  int
+		expectedMessage string
+		expectedBlobs   int
+	}{
+		{name: "Success", deleted: &entity.Attachment{ID: 9, StorageKey: "abcdef"}, expectedStatus: http.StatusOK, expectedMessage: "Success Delete Attachment"},
+		{name: "Not found", expectedStatus: http.StatusNotFound, expectedMessage: "Not Found", expectedBlobs: 1},
+		{name: "Repository error", deleteErr: errors.New("some error"), expectedStatus: http.StatusInternalServerError, expectedMessage: "Internal Server Error", expectedBlobs: 1},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			store, err := storage.NewLocalStore(dir)
+			require.NoError(t, err)
+			require.NoError(t, store.Put(context.Background(), "abcdef", strings.NewReader("content")))
+			repo := mocks.NewAttachmentRepository(t)
+			repo.On("Delete", testUserID, int64(1), int64(9)).Return(tc.deleted, tc.deleteErr)
+			handler := NewAttachmentService(repo, store, 1024)
+
+			router := gin.Default()
+			router.DELETE("/manage-todo/todo/:id/attachments/:attachmentID", withUser, handler.AttachmentHandlerDelete)
+
+			w := httptest.NewRecorder()
+			r, err := http.NewRequest(http.MethodDelete, "/manage-todo/todo/1/attachments/9", nil)
+			require.NoError(t, err)
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var resp respErr.ErrorResponse
+			err = json.Unmarshal(w.Body.Bytes(), &resp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMessage, resp.Message)
+			assert.Equal(t, tc.expectedBlobs, countBlobs(t, dir))
+		})
+	}
+}
+
+func TestCleanFilename(t *testing.T) {
+	testCases := map[string]string{
+		"report.pdf":                "report.pdf",
+		"../../etc/passwd":          "passwd",
+		`C:\Users\raihan\notes.txt`: "notes.txt",
+		"line\nbreak.txt":           "linebreak.txt",
+		"..":                        "attachment",
+		"":                          "attachment",
+		strings.Repeat("é", 300):    strings.Repeat("é", 255),
+		"dir/":                      "dir",
+	}
+	for name, expected := range testCases {
+		assert.Equal(t, expected, cleanFilename(name), name)
+	}
+}
+
+// countBlobs counts the files a LocalStore rooted at dir holds.
+func countBlobs(t *testing.T, dir string) int {
+	matches, err := filepath.Glob(filepath.Join(dir, "*", "*"))
+	require.NoError(t, err)
+	return len(matches)
+}
@@ -0,0 +1,135 @@
+package service
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"golang.org/x/crypto/bcrypt"
+	"net/http"
+	"time"
+	"todoGin/middleware"
+	"todoGin/model/request"
+	"todoGin/model/respErr"
+	"todoGin/repository"
+)
+
+type AuthHandler struct {
+	UserRepository repository.UserRepository
+	JWTSecret      string
+	TokenTTL       time.Duration
+}
+
+func NewAuthService(userRepo repository.UserRepository, jwtSecret string, tokenTTL time.Duration) *AuthHandler {
+	return &AuthHandler{
+		UserRepository: userRepo,
+		JWTSecret:      jwtSecret,
+		TokenTTL:       tokenTTL,
+	}
+}
+
+func (h *AuthHandler) AuthHandlerRegister(ctx *gin.Context) {
+	reqBody := new(request.RegisterRequest)
+	if err := ctx.ShouldBindJSON(reqBody); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid input",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	existing, err := h.UserRepository.GetByUsername(reqBody.Username)
+	if err != nil {
+		logrus.Errorf("failed when get user by username: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if existing != nil {
+		ctx.AbortWithStatusJSON(http.StatusConflict, respErr.ErrorResponse{
+			Message: "Username already taken",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+
+	hashed, err := bcrypt.GenerateFromPassword([]byte(reqBody.Password), bcrypt.DefaultCost)
+	if err != nil {
+		logrus.Errorf("failed when hashing password: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	user, err := h.UserRepository.Create(reqBody.Username, string(hashed), reqBody.Email)
+	if errors.Is(err, repository.ErrUsernameTaken) {
+		ctx.AbortWithStatusJSON(http.StatusConflict, respErr.ErrorResponse{
+			Message: "Username already taken",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when creating user: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	logrus.Info(http.StatusCreated, " Success Register User")
+	ctx.JSON(http.StatusCreated, request.UserResponse{
+		Status:  http.StatusCreated,
+		Message: "User Registered",
+		Data:    *user,
+	})
+}
+
+func (h *AuthHandler) AuthHandlerLogin(ctx *gin.Context) {
+	reqBody := new(request.LoginRequest)
+	if err := ctx.ShouldBindJSON(reqBody); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid input",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	user, err := h.UserRepository.GetByUsername(reqBody.Username)
+	if err != nil {
+		logrus.Errorf("failed when get user by username: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if user == nil || bcrypt.CompareHashAndPassword([]byte(user.Password), []byte(reqBody.Password)) != nil {
+		ctx.AbortWithStatusJSON(http.StatusUnauthorized, respErr.ErrorResponse{
+			Message: "Invalid username or password",
+			Status:  http.StatusUnauthorized,
+		})
+		return
+	}
+
+	token, expiresAt, err := middleware.GenerateToken(user, h.JWTSecret, h.TokenTTL)
+	if err != nil {
+		logrus.Errorf("failed when signing token: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	logrus.Info(http.StatusOK, " Success Login")
+	ctx.JSON(http.StatusOK, request.LoginResponse{
+		Status:    http.StatusOK,
+		Message:   "Success Login",
+		Token:     token,
+		ExpiresAt: expiresAt,
+	})
+}
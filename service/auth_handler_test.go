@@ -0,0 +1,191 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"golang.org/x/crypto/bcrypt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todoGin/middleware"
+	"todoGin/mocks"
+	"todoGin/model/entity"
+	"todoGin/model/request"
+	"todoGin/model/respErr"
+	"todoGin/repository"
+)
+
+func TestAuthRegister(t *testing.T) {
+	testCases := []struct {
+		name            string
+		body            string
+		mock            func(repo *mocks.UserRepository)
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name: "Success",
+			body: `{"username": "raihan", "password": "pastibisa"}`,
+			mock: func(repo *mocks.UserRepository) {
+				repo.On("GetByUsername", "raihan").Return(nil, nil)
+				repo.On("Create", "raihan", mock.AnythingOfType("string"), (*string)(nil)).Return(&entity.User{ID: 1, Username: "raihan"}, nil)
+			},
+			expectedStatus:  http.StatusCreated,
+			expectedMessage: "User Registered",
+		},
+		{
+			name: "Success with an email",
+			body: `{"username": "raihan", "password": "pastibisa", "email": "raihan@example.com"}`,
+			mock: func(repo *mocks.UserRepository) {
+				repo.On("GetByUsername", "raihan").Return(nil, nil)
+				repo.On("Create", "raihan", mock.AnythingOfType("string"), mock.MatchedBy(func(email *string) bool {
+					return email != nil && *email == "raihan@example.com"
+				})).Return(&entity.User{ID: 1, Username: "raihan"}, nil)
+			},
+			expectedStatus:  http.StatusCreated,
+			expectedMessage: "User Registered",
+		},
+		{
+			name:            "Invalid email",
+			body:            `{"username": "raihan", "password": "pastibisa", "email": "raihan"}`,
+			mock:            func(repo *mocks.UserRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+		{
+			name:            "Invalid input",
+			body:            `{"username": "raihan", "password": "short"}`,
+			mock:            func(repo *mocks.UserRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+		{
+			name: "Username taken",
+			body: `{"username": "raihan", "password": "pastibisa"}`,
+			mock: func(repo *mocks.UserRepository) {
+				repo.On("GetByUsername", "raihan").Return(&entity.User{ID: 1, Username: "raihan"}, nil)
+			},
+			expectedStatus:  http.StatusConflict,
+			expectedMessage: "Username already taken",
+		},
+		{
+			name: "Username taken concurrently",
+			body: `{"username": "raihan", "password": "pastibisa"}`,
+			mock: func(repo *mocks.UserRepository) {
+				repo.On("GetByUsername", "raihan").Return(nil, nil)
+				repo.On("Create", "raihan", mock.AnythingOfType("string"), (*string)(nil)).Return(nil, repository.ErrUsernameTaken)
+			},
+			expectedStatus:  http.StatusConflict,
+			expectedMessage: "Username already taken",
+		},
+		{
+			name: "Internal Server Error",
+			body: `{"username": "raihan", "password": "pastibisa"}`,
+			mock: func(repo *mocks.UserRepository) {
+				repo.On("GetByUsername", "raihan").Return(nil, errors.New("some error"))
+			},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "Internal Server Error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewUserRepository(t)
+			tc.mock(repo)
+			handler := NewAuthService(repo, "secret", time.Hour)
+
+			req, err := http.NewRequest(http.MethodPost, "/register", bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := gin.New()
+			r.POST("/register", handler.AuthHandlerRegister)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var resp respErr.ErrorResponse
+			err = json.Unmarshal(w.Body.Bytes(), &resp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMessage, resp.Message)
+		})
+	}
+}
+
+func TestAuthLogin(t *testing.T) {
+	hashed, err := bcrypt.GenerateFromPassword([]byte("pastibisa"), bcrypt.MinCost)
+	require.NoError(t, err)
+	user := &entity.User{ID: 7, Username: "raihan", Password: string(hashed)}
+
+	testCases := []struct {
+		name           string
+		body           string
+		mockUser       *entity.User
+		expectedStatus int
+	}{
+		{
+			name:           "Success",
+			body:           `{"username": "raihan", "password": "pastibisa"}`,
+			mockUser:       user,
+			expectedStatus: http.StatusOK,
+		},
+		{
+			name:           "Wrong password",
+			body:           `{"username": "raihan", "password": "salah"}`,
+			mockUser:       user,
+			expectedStatus: http.StatusUnauthorized,
+		},
+		{
+			name:           "Unknown user",
+			body:           `{"username": "raihan", "password": "pastibisa"}`,
+			mockUser:       nil,
+			expectedStatus: http.StatusUnauthorized,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewUserRepository(t)
+			repo.On("GetByUsername", "raihan").Return(tc.mockUser, nil)
+			handler := NewAuthService(repo, "secret", time.Hour)
+
+			req, err := http.NewRequest(http.MethodPost, "/login", bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+
+			w := httptest.NewRecorder()
+			r := gin.New()
+			r.POST("/login", handler.AuthHandlerLogin)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp request.LoginResponse
+			err = json.Unmarshal(w.Body.Bytes(), &resp)
+			require.NoError(t, err)
+			assert.NotEmpty(t, resp.Token)
+
+			// the issued token must be accepted by the JWT middleware
+			var userID interface{}
+			r.GET("/me", middleware.JWTAuth("secret"), func(ctx *gin.Context) {
+				userID, _ = ctx.Get(middleware.UserIDKey)
+			})
+			me := httptest.NewRecorder()
+			meReq, _ := http.NewRequest(http.MethodGet, "/me", nil)
+			meReq.Header.Set("Authorization", "Bearer "+resp.Token)
+			r.ServeHTTP(me, meReq)
+
+			assert.Equal(t, http.StatusOK, me.Code)
+			assert.Equal(t, user.ID, userID)
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package service
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"todoGin/model/request"
+	"todoGin/model/respErr"
+	"todoGin/repository"
+)
+
+// TodolistHandlerBulk applies a batch of create, update and delete operations
+// and reports each of them. In atomic mode they run in one transaction and
+// nothing is kept unless every operation succeeds; in best_effort mode each
+// commits on its own and failures are skipped.
+func (h *Handler) TodolistHandlerBulk(ctx *gin.Context) {
+	reqBody := new(request.TodolistBulkRequest)
+	if err := ctx.ShouldBindJSON(reqBody); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid input",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	userID := currentUserID(ctx)
+	atomic := reqBody.Atomic()
+
+	results := make([]request.TodoBulkResult, len(reqBody.Operations))
+	ops := make([]repository.BulkOperation, 0, len(reqBody.Operations))
+	indexes := make([]int, 0, len(reqBody.Operations))
+	invalid := false
+	for i, operation := range reqBody.Operations {
+		results[i] = request.TodoBulkResult{Index: i, Op: operation.Op, ID: operation.ID}
+		op, err := operation.ReqBulk(userID)
+		if err != nil {
+			logrus.Error(err)
+			results[i].Status = http.StatusBadRequest
+			results[i].Message = "Invalid input"
+			invalid = true
+			continue
+		}
+		ops = append(ops, op)
+		indexes = append(indexes, i)
+	}
+
+	switch {
+	case invalid && atomic:
+		for _, i := range indexes {
+			setBulkResult(&results[i], repository.BulkResult{Err: repository.ErrBulkSkipped})
+		}
+	case len(ops) > 0:
+		repoResults, err := h.TodoRepository.Bulk(userID, ops, atomic)
+		if err != nil {
+			logrus.Errorf("failed when running bulk operations: %v", err)
+			ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+				Message: "Internal Server Error",
+				Status:  http.StatusInternalServerError,
+			})
+			return
+		}
+		for j, res := range repoResults {
+			setBulkResult(&results[indexes[j]], res)
+		}
+	}
+
+	resp := request.TodoBulkResponse{
+		Status:  http.StatusOK,
+		Mode:    request.BulkModeBestEffort,
+		Results: results,
+	}
+	if atomic {
+		resp.Mode = request.BulkModeAtomic
+	}
+	for _, result := range results {
+		if result.Status < http.StatusBadRequest {
+			resp.Succeeded++
+		} else {
+			resp.Failed++
+		}
+	}
+	switch {
+	case resp.Failed == 0:
+		resp.Message = "Success Bulk"
+	case atomic:
+		resp.Message = "Bulk Rolled Back"
+	default:
+		resp.Message = "Bulk Partially Applied"
+	}
+
+	logrus.Info(http.StatusOK, " ", resp.Message)
+	ctx.JSON(http.StatusOK, resp)
+}
+
+// setBulkResult fills in the status and message the single-item endpoint
+// would have answered for the operation.
+func setBulkResult(result *request.TodoBulkResult, res repository.BulkResult) {
+	result.Data = res.Todo
+	switch {
+	case res.Err == nil && result.Op == repository.BulkCreate:
+		result.Status, result.Message = http.StatusCreated, "New Todo Created"
+		result.ID = res.Todo.ID
+	case res.Err == nil && result.Op == repository.BulkDelete:
+		result.Status, result.Message = http.StatusOK, "Success Delete"
+	case res.Err == nil && res.RowsAffected == 0:
+		result.Status, result.Message = http.StatusOK, "Not Change"
+	case res.Err == nil:
+		result.Status, result.Message = http.StatusOK, "Success Update Todo"
+	case errors.Is(res.Err, repository.ErrTodoNotFound):
+		result.Status, result.Message = http.StatusNotFound, "ID not Found"
+	case errors.Is(res.Err, repository.ErrVersionMismatch):
+		result.Status, result.Message = http.StatusPreconditionFailed, "Precondition Failed"
+	case errors.Is(res.Err, repository.ErrRecurrenceWithoutDueDate):
+		result.Status, result.Message = http.StatusBadRequest, "Recurring todo needs a due date"
+	case errors.Is(res.Err, repository.ErrBulkRolledBack):
+		result.Status, result.Message = http.StatusFailedDependency, "Rolled Back"
+	case errors.Is(res.Err, repository.ErrBulkSkipped):
+		result.Status, result.Message = http.StatusFailedDependency, "Skipped"
+	default:
+		logrus.Errorf("failed when running bulk %s: %v", result.Op, res.Err)
+		result.Status, result.Message = http.StatusInternalServerError, "Internal Server Error"
+	}
+}
@@ -0,0 +1,144 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todoGin/mocks"
+	"todoGin/model/entity"
+	"todoGin/model/request"
+	"todoGin/repository"
+)
+
+func TestBulk(t *testing.T) {
+	testCases := []struct {
+		name             string
+		body             string
+		mock             func(repo *mocks.TodoRepository)
+		expectedStatus   int
+		expectedMessage  string
+		expectedStatuses []int
+	}{
+		{
+			name: "Atomic success",
+			body: `{"operations": [
+				{"op": "create", "todo": {"title": "Write docs"}},
+				{"op": "update", "id": 2, "version": 3, "todo": {"status": true}},
+				{"op": "delete", "id": 3}
+			]}`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Bulk", testUserID, mock.MatchedBy(func(ops []repository.BulkOperation) bool {
+					return len(ops) == 3 &&
+						ops[0].Op == repository.BulkCreate && ops[0].Todo.Title == "Write docs" &&
+						ops[1].Op == repository.BulkUpdate && ops[1].TodoID == 2 && ops[1].Version == 3 &&
+						ops[1].Updates["status"] == true &&
+						ops[2].Op == repository.BulkDelete && ops[2].TodoID == 3
+				}), true).Return([]repository.BulkResult{
+					{Todo: &entity.Todolist{ID: 9, Title: "Write docs"}, RowsAffected: 1},
+					{Todo: &entity.Todolist{ID: 2, Status: true}, RowsAffected: 1},
+					{RowsAffected: 1},
+				}, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedMessage:  "Success Bulk",
+			expectedStatuses: []int{http.StatusCreated, http.StatusOK, http.StatusOK},
+		},
+		{
+			name: "Atomic failure rolls back",
+			body: `{"mode": "atomic", "operations": [
+				{"op": "delete", "id": 1},
+				{"op": "delete", "id": 2, "version": 7},
+				{"op": "delete", "id": 3}
+			]}`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Bulk", testUserID, mock.Anything, true).Return([]repository.BulkResult{
+					{Err: repository.ErrBulkRolledBack},
+					{Err: repository.ErrVersionMismatch},
+					{Err: repository.ErrBulkSkipped},
+				}, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedMessage:  "Bulk Rolled Back",
+			expectedStatuses: []int{http.StatusFailedDependency, http.StatusPreconditionFailed, http.StatusFailedDependency},
+		},
+		{
+			name: "Atomic with invalid operation never reaches the database",
+			body: `{"operations": [
+				{"op": "create", "todo": {"title": "x"}},
+				{"op": "delete", "id": 2}
+			]}`,
+			mock:             func(repo *mocks.TodoRepository) {},
+			expectedStatus:   http.StatusOK,
+			expectedMessage:  "Bulk Rolled Back",
+			expectedStatuses: []int{http.StatusBadRequest, http.StatusFailedDependency},
+		},
+		{
+			name: "Best effort skips invalid and missing",
+			body: `{"mode": "best_effort", "operations": [
+				{"op": "update", "id": 1, "todo": {"title": null}},
+				{"op": "delete", "id": 2},
+				{"op": "update", "id": 3, "todo": {"title": "Renamed"}}
+			]}`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Bulk", testUserID, mock.MatchedBy(func(ops []repository.BulkOperation) bool {
+					return len(ops) == 2 && ops[0].TodoID == 2 && ops[1].TodoID == 3
+				}), false).Return([]repository.BulkResult{
+					{Err: repository.ErrTodoNotFound},
+					{Todo: &entity.Todolist{ID: 3, Title: "Renamed"}, RowsAffected: 1},
+				}, nil)
+			},
+			expectedStatus:   http.StatusOK,
+			expectedMessage:  "Bulk Partially Applied",
+			expectedStatuses: []int{http.StatusBadRequest, http.StatusNotFound, http.StatusOK},
+		},
+		{
+			name:            "Update without id",
+			body:            `{"operations": [{"op": "update", "todo": {"status": true}}]}`,
+			mock:            func(repo *mocks.TodoRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+		{
+			name:            "No operations",
+			body:            `{"operations": []}`,
+			mock:            func(repo *mocks.TodoRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTodoRepository(t)
+			tc.mock(repo)
+			handler := NewTodoService(repo)
+
+			w := httptest.NewRecorder()
+			r, err := http.NewRequest(http.MethodPost, "/manage-todos/bulk", bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+			router := gin.Default()
+			router.POST("/manage-todos/bulk", withUser, handler.TodolistHandlerBulk)
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var resp request.TodoBulkResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tc.expectedMessage, resp.Message)
+			statuses := make([]int, 0, len(resp.Results))
+			for i, result := range resp.Results {
+				assert.Equal(t, i, result.Index)
+				statuses = append(statuses, result.Status)
+			}
+			if tc.expectedStatuses != nil {
+				assert.Equal(t, tc.expectedStatuses, statuses)
+			}
+		})
+	}
+}
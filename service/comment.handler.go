@@ -0,0 +1,174 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"todoGin/model/request"
+	"todoGin/model/respErr"
+	"todoGin/repository"
+)
+
+type CommentHandler struct {
+	CommentRepository repository.CommentRepository
+}
+
+func NewCommentService(commentRepo repository.CommentRepository) *CommentHandler {
+	return &CommentHandler{
+		CommentRepository: commentRepo,
+	}
+}
+
+// CommentHandlerGetAll lists the discussion of a todo, oldest first.
+func (h *CommentHandler) CommentHandlerGetAll(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	comments, err := h.CommentRepository.GetAll(currentUserID(ctx), todoID)
+	if err != nil {
+		logrus.Errorf("failed when get comments: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if comments == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "ID not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Get Comments")
+	ctx.JSON(http.StatusOK, request.CommentsResponse{
+		Message:  "Success Get Comments",
+		Data:     len(comments),
+		Comments: comments,
+	})
+}
+
+func (h *CommentHandler) CommentHandlerCreate(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	reqBody, ok := bindComment(ctx)
+	if !ok {
+		return
+	}
+	comment, err := h.CommentRepository.Create(currentUserID(ctx), todoID, reqBody.Body)
+	if err != nil {
+		logrus.Errorf("failed when creating comment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if comment == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "ID not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusCreated, " Success Create Comment")
+	ctx.JSON(http.StatusCreated, request.CommentResponse{
+		Status:  http.StatusCreated,
+		Message: "New Comment Created",
+		Data:    *comment,
+	})
+}
+
+// CommentHandlerUpdate edits one of the caller's own comments.
+func (h *CommentHandler) CommentHandlerUpdate(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	commentID, ok := int64Param(ctx, "commentID")
+	if !ok {
+		return
+	}
+	reqBody, ok := bindComment(ctx)
+	if !ok {
+		return
+	}
+	comment, err := h.CommentRepository.Update(currentUserID(ctx), todoID, commentID, reqBody.Body)
+	if err != nil {
+		logrus.Errorf("failed when updating comment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if comment == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "ID not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Update Comment")
+	ctx.JSON(http.StatusOK, request.CommentResponse{
+		Status:  http.StatusOK,
+		Message: "Success Update Comment",
+		Data:    *comment,
+	})
+}
+
+// CommentHandlerDelete removes one of the caller's own comments.
+func (h *CommentHandler) CommentHandlerDelete(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	commentID, ok := int64Param(ctx, "commentID")
+	if !ok {
+		return
+	}
+	isFound, err := h.CommentRepository.Delete(currentUserID(ctx), todoID, commentID)
+	if err != nil {
+		logrus.Errorf("failed when deleting comment: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if isFound == 0 {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Delete Comment")
+	ctx.JSON(http.StatusOK, request.TodoDeleteResponse{
+		Status:  http.StatusOK,
+		Message: "Success Delete Comment",
+	})
+}
+
+func bindComment(ctx *gin.Context) (*request.CommentRequest, bool) {
+	reqBody := new(request.CommentRequest)
+	if err := ctx.ShouldBindJSON(reqBody); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid input",
+			Status:  http.StatusBadRequest,
+		})
+		return nil, false
+	}
+	if reqBody.Blank() {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid input",
+			Status:  http.StatusBadRequest,
+		})
+		return nil, false
+	}
+	return reqBody, true
+}
@@ -0,0 +1,164 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todoGin/mocks"
+	"todoGin/model/entity"
+	"todoGin/model/respErr"
+	"todoGin/repository"
+)
+
+func TestCommentHandlers(t *testing.T) {
+	comment := &entity.Comment{ID: 4, TodoID: 1, UserID: testUserID, Author: "raihan", Body: "**Blocked** on review"}
+
+	testCases := []struct {
+		name            string
+		method          string
+		url             string
+		body            string
+		mock            func(repo *mocks.CommentRepository)
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:   "List",
+			method: http.MethodGet,
+			url:    "/manage-todo/todo/1/comments",
+			mock: func(repo *mocks.CommentRepository) {
+				repo.On("GetAll", testUserID, int64(1)).Return([]entity.Comment{*comment}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Get Comments",
+		},
+		{
+			name:   "List of missing todo",
+			method: http.MethodGet,
+			url:    "/manage-todo/todo/2/comments",
+			mock: func(repo *mocks.CommentRepository) {
+				repo.On("GetAll", testUserID, int64(2)).Return(nil, nil)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "ID not Found",
+		},
+		{
+			name:   "Create keeps markdown as written",
+			method: http.MethodPost,
+			url:    "/manage-todo/todo/1/comments",
+			body:   `{"body": "**Blocked** on review\n\n- [ ] ping"}`,
+			mock: func(repo *mocks.CommentRepository) {
+				repo.On("Create", testUserID, int64(1), "**Blocked** on review\n\n- [ ] ping").Return(comment, nil)
+			},
+			expectedStatus:  http.StatusCreated,
+			expectedMessage: "New Comment Created",
+		},
+		{
+			name:            "Create blank",
+			method:          http.MethodPost,
+			url:             "/manage-todo/todo/1/comments",
+			body:            `{"body": " \n "}`,
+			mock:            func(repo *mocks.CommentRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+		{
+			name:   "Create on missing todo",
+			method: http.MethodPost,
+			url:    "/manage-todo/todo/2/comments",
+			body:   `{"body": "hello"}`,
+			mock: func(repo *mocks.CommentRepository) {
+				repo.On("Create", testUserID, int64(2), "hello").Return(nil, nil)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "ID not Found",
+		},
+		{
+			name:   "Edit",
+			method: http.MethodPatch,
+			url:    "/manage-todo/todo/1/comments/4",
+			body:   `{"body": "Unblocked"}`,
+			mock: func(repo *mocks.CommentRepository) {
+				repo.On("Update", testUserID, int64(1), int64(4), "Unblocked").Return(comment, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Update Comment",
+		},
+		{
+			name:   "Edit someone else's comment",
+			method: http.MethodPatch,
+			url:    "/manage-todo/todo/1/comments/5",
+			body:   `{"body": "Unblocked"}`,
+			mock: func(repo *mocks.CommentRepository) {
+				repo.On("Update", testUserID, int64(1), int64(5), "Unblocked").Return(nil, nil)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "ID not Found",
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			url:    "/manage-todo/todo/1/comments/4",
+			mock: func(repo *mocks.CommentRepository) {
+				repo.On("Delete", testUserID, int64(1), int64(4)).Return(int64(1), nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Delete Comment",
+		},
+		{
+			name:   "Delete error",
+			method: http.MethodDelete,
+			url:    "/manage-todo/todo/1/comments/4",
+			mock: func(repo *mocks.CommentRepository) {
+				repo.On("Delete", testUserID, int64(1), int64(4)).Return(int64(0), errors.New("some error"))
+			},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "Internal Server Error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewCommentRepository(t)
+			tc.mock(repo)
+			handler := NewCommentService(repo)
+
+			router := gin.Default()
+			router.GET("/manage-todo/todo/:id/comments", withUser, handler.CommentHandlerGetAll)
+			router.POST("/manage-todo/todo/:id/comments", withUser, handler.CommentHandlerCreate)
+			router.PATCH("/manage-todo/todo/:id/comments/:commentID", withUser, handler.CommentHandlerUpdate)
+			router.DELETE("/manage-todo/todo/:id/comments/:commentID", withUser, handler.CommentHandlerDelete)
+
+			w := httptest.NewRecorder()
+			r, err := http.NewRequest(tc.method, tc.url, bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var resp respErr.ErrorResponse
+			err = json.Unmarshal(w.Body.Bytes(), &resp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMessage, resp.Message)
+		})
+	}
+}
+
+func TestListETagCoversCommentCount(t *testing.T) {
+	lastModified := time.Date(2023, 7, 10, 9, 0, 0, 0, time.UTC)
+	one, two := int64(1), int64(2)
+	page := func(count *int64) []entity.Todolist {
+		return []entity.Todolist{{ID: 1, Version: 2, CommentCount: count}}
+	}
+	query := repository.TodoQuery{Limit: 20}
+
+	assert.Equal(t, listETag(query, 1, page(&one), lastModified), listETag(query, 1, page(&one), lastModified))
+	assert.NotEqual(t, listETag(query, 1, page(&one), lastModified), listETag(query, 1, page(&two), lastModified))
+}
@@ -0,0 +1,72 @@
+package service
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"hash/fnv"
+	"net/http"
+	"strings"
+	"time"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+// notModified sets the ETag and Last-Modified validators on a GET response
+// and reports whether the client's cached copy is still current, in which
+// case it has already answered 304. If-None-Match takes precedence over
+// If-Modified-Since, as RFC 7232 requires.
+func notModified(ctx *gin.Context, etag string, lastModified time.Time) bool {
+	ctx.Header("Cache-Control", "private, no-cache")
+	ctx.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		ctx.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+
+	if match := ctx.GetHeader("If-None-Match"); match != "" {
+		if !etagListContains(match, etag) {
+			return false
+		}
+	} else if since, err := http.ParseTime(ctx.GetHeader("If-Modified-Since")); err != nil ||
+		lastModified.IsZero() || lastModified.Truncate(time.Second).After(since) {
+		return false
+	}
+
+	ctx.AbortWithStatus(http.StatusNotModified)
+	return true
+}
+
+// etagListContains applies the weak comparison used by If-None-Match.
+func etagListContains(list, etag string) bool {
+	etag = strings.TrimPrefix(etag, "W/")
+	for _, candidate := range strings.Split(list, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == etag {
+			return true
+		}
+	}
+	return false
+}
+
+// listETag is a weak tag for one page of todos. It covers the query, the
+// total, every todo's version and comment count and the user's last
+// modification, so any write that could change the page changes the tag.
+func listETag(query repository.TodoQuery, total int64, todos []entity.Todolist, lastModified time.Time) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%d|%d|%s|%t|%t|%s|%s|%q|%t|%d|%d",
+		query.Limit, query.Offset, query.Sort, query.Desc, query.Trashed,
+		optionalString(query.Status), optionalString(query.ListID), query.Tags, query.MatchAllTags,
+		total, lastModified.UnixNano())
+	for _, todo := range todos {
+		fmt.Fprintf(h, "|%d:%d:%s", todo.ID, todo.Version, optionalString(todo.CommentCount))
+	}
+	return fmt.Sprintf(`W/"%x"`, h.Sum64())
+}
+
+// optionalString formats the value behind p, so that equal queries hash
+// alike regardless of where their pointers point.
+func optionalString[T any](p *T) string {
+	if p == nil {
+		return "-"
+	}
+	return fmt.Sprint(*p)
+}
@@ -0,0 +1,41 @@
+package service
+
+import (
+	"fmt"
+	"github.com/gin-gonic/gin"
+	"net/http"
+	"strconv"
+	"strings"
+	"todoGin/model/entity"
+	"todoGin/model/respErr"
+)
+
+// todoETag is the strong entity tag of a todo: its quoted version.
+func todoETag(todo *entity.Todolist) string {
+	return fmt.Sprintf("%q", strconv.FormatInt(todo.Version, 10))
+}
+
+// ifMatchVersion reads the If-Match header as the todo version the client
+// expects. A missing header or "*" yields 0 (no precondition). Anything that
+// cannot be one of our strong tags, weak tags included, never matches; the
+// caller gets 412 and false.
+func ifMatchVersion(ctx *gin.Context) (int64, bool) {
+	header := strings.TrimSpace(ctx.GetHeader("If-Match"))
+	if header == "" || header == "*" {
+		return 0, true
+	}
+	if unquoted, err := strconv.Unquote(header); err == nil {
+		if version, err := strconv.ParseInt(unquoted, 10, 64); err == nil && version > 0 {
+			return version, true
+		}
+	}
+	preconditionFailed(ctx)
+	return 0, false
+}
+
+func preconditionFailed(ctx *gin.Context) {
+	ctx.AbortWithStatusJSON(http.StatusPreconditionFailed, respErr.ErrorResponse{
+		Message: "Precondition Failed",
+		Status:  http.StatusPreconditionFailed,
+	})
+}
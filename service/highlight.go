@@ -0,0 +1,77 @@
+package service
+
+import (
+	"html"
+	"regexp"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// snippetRadius is how many bytes of context are kept on each side of the first match.
+const snippetRadius = 60
+
+// searchTerms splits a search query into the words MySQL will match on.
+func searchTerms(query string) []string {
+	return strings.FieldsFunc(strings.ToLower(query), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsNumber(r)
+	})
+}
+
+// highlight returns an HTML-escaped snippet of text around the first match of
+// any term, with every match wrapped in <mark>. It returns "" when nothing matches.
+func highlight(text string, terms []string) string {
+	if len(terms) == 0 {
+		return ""
+	}
+	quoted := make([]string, len(terms))
+	for i, term := range terms {
+		quoted[i] = regexp.QuoteMeta(term)
+	}
+	re := regexp.MustCompile(`(?i)` + strings.Join(quoted, "|"))
+	matches := re.FindAllStringIndex(text, -1)
+	if matches == nil {
+		return ""
+	}
+
+	start := runeStart(text, matches[0][0]-snippetRadius)
+	end := runeStart(text, matches[len(matches)-1][1]+snippetRadius)
+	if end-start > 4*snippetRadius {
+		end = runeStart(text, matches[0][1]+snippetRadius)
+	}
+
+	var b strings.Builder
+	if start > 0 {
+		b.WriteString("…")
+	}
+	pos := start
+	for _, m := range matches {
+		if m[0] < pos || m[1] > end {
+			continue
+		}
+		b.WriteString(html.EscapeString(text[pos:m[0]]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[m[0]:m[1]]))
+		b.WriteString("</mark>")
+		pos = m[1]
+	}
+	b.WriteString(html.EscapeString(text[pos:end]))
+	if end < len(text) {
+		b.WriteString("…")
+	}
+	return b.String()
+}
+
+// runeStart clamps i into text and moves it back to the start of a rune.
+func runeStart(text string, i int) int {
+	if i <= 0 {
+		return 0
+	}
+	if i >= len(text) {
+		return len(text)
+	}
+	for i > 0 && !utf8.RuneStart(text[i]) {
+		i--
+	}
+	return i
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"github.com/stretchr/testify/assert"
+	"strings"
+	"testing"
+)
+
+func TestHighlight(t *testing.T) {
+	long := strings.Repeat("lorem ipsum ", 20) + "deploy checklist" + strings.Repeat(" dolor sit", 20)
+
+	testCases := []struct {
+		name     string
+		text     string
+		query    string
+		expected string
+	}{
+		{
+			name:     "Single term",
+			text:     "Review release notes",
+			query:    "release",
+			expected: "Review <mark>release</mark> notes",
+		},
+		{
+			name:     "Case insensitive, many terms",
+			text:     "Deploy the Backend, then deploy docs",
+			query:    "DEPLOY backend",
+			expected: "<mark>Deploy</mark> the <mark>Backend</mark>, then <mark>deploy</mark> docs",
+		},
+		{
+			name:     "Escapes html",
+			text:     "<script> fix login",
+			query:    "login",
+			expected: "&lt;script&gt; fix <mark>login</mark>",
+		},
+		{
+			name:     "No match",
+			text:     "Buy milk",
+			query:    "deploy",
+			expected: "",
+		},
+		{
+			name:     "Long text is trimmed around the match",
+			text:     long,
+			query:    "checklist",
+			expected: "…" + long[strings.Index(long, "checklist")-60:strings.Index(long, "checklist")] + "<mark>checklist</mark>" + long[strings.Index(long, "checklist")+9:strings.Index(long, "checklist")+9+60] + "…",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			assert.Equal(t, tc.expected, highlight(tc.text, searchTerms(tc.query)))
+		})
+	}
+}
@@ -0,0 +1,211 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"todoGin/model/entity"
+	"todoGin/model/request"
+	"todoGin/model/respErr"
+	"todoGin/repository"
+)
+
+type ListHandler struct {
+	ListRepository repository.ListRepository
+	TodoRepository repository.TodoRepository
+}
+
+func NewListService(listRepo repository.ListRepository, todoRepo repository.TodoRepository) *ListHandler {
+	return &ListHandler{
+		ListRepository: listRepo,
+		TodoRepository: todoRepo,
+	}
+}
+
+func (h *ListHandler) ListHandlerGetAll(ctx *gin.Context) {
+	queryReq := new(request.ListQueryRequest)
+	if err := ctx.ShouldBindQuery(queryReq); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid query",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	lists, err := h.ListRepository.GetAll(currentUserID(ctx), queryReq.Archived)
+	if err != nil {
+		logrus.Errorf("failed when get lists: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Get Lists")
+	ctx.JSON(http.StatusOK, request.ListsResponse{
+		Message: "Success Get Lists",
+		Data:    len(lists),
+		Lists:   lists,
+	})
+}
+
+func (h *ListHandler) ListHandlerCreate(ctx *gin.Context) {
+	reqBody := new(request.ListCreateRequest)
+	if err := ctx.ShouldBindJSON(reqBody); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid input",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	list, err := h.ListRepository.Create(&entity.List{
+		UserID: currentUserID(ctx),
+		Name:   reqBody.Name,
+	})
+	if err != nil {
+		logrus.Errorf("failed when creating list: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	logrus.Info(http.StatusCreated, " Success Create List")
+	ctx.JSON(http.StatusCreated, request.ListResponse{
+		Status:  http.StatusCreated,
+		Message: "New List Created",
+		Data:    *list,
+	})
+}
+
+func (h *ListHandler) ListHandlerGetByID(ctx *gin.Context) {
+	list, ok := h.findList(ctx)
+	if !ok {
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Get List")
+	ctx.JSON(http.StatusOK, request.ListResponse{
+		Status:  http.StatusOK,
+		Message: "Success Get List",
+		Data:    *list,
+	})
+}
+
+func (h *ListHandler) ListHandlerUpdate(ctx *gin.Context) {
+	listID, ok := int64Param(ctx, "listID")
+	if !ok {
+		return
+	}
+	reqBody := new(request.ListUpdateRequest)
+	if err := ctx.ShouldBindJSON(reqBody); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Bad request",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	list, err := h.ListRepository.Update(currentUserID(ctx), listID, reqBody.ReqList())
+	if err != nil {
+		logrus.Errorf("failed when updating list: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if list == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "List not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Update List")
+	ctx.JSON(http.StatusOK, request.ListResponse{
+		Status:  http.StatusOK,
+		Message: "Success Update List",
+		Data:    *list,
+	})
+}
+
+func (h *ListHandler) ListHandlerDelete(ctx *gin.Context) {
+	listID, ok := int64Param(ctx, "listID")
+	if !ok {
+		return
+	}
+	isFound, err := h.ListRepository.Delete(currentUserID(ctx), listID)
+	if err != nil {
+		logrus.Errorf("failed when deleting list: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if isFound == 0 {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "List not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Delete List")
+	ctx.JSON(http.StatusOK, request.TodoDeleteResponse{
+		Status:  http.StatusOK,
+		Message: "Success Delete List",
+	})
+}
+
+// ListHandlerGetTodos pages through the todos of one list, with the same
+// query parameters as GET /manage-todos.
+func (h *ListHandler) ListHandlerGetTodos(ctx *gin.Context) {
+	list, ok := h.findList(ctx)
+	if !ok {
+		return
+	}
+	listTodos(ctx, h.TodoRepository, false, &list.ID, "Success Get List Todos")
+}
+
+// ListHandlerCreateTodo adds a todo to a list; archived lists refuse new todos.
+func (h *ListHandler) ListHandlerCreateTodo(ctx *gin.Context) {
+	list, ok := h.findList(ctx)
+	if !ok {
+		return
+	}
+	if list.Archived {
+		ctx.AbortWithStatusJSON(http.StatusConflict, respErr.ErrorResponse{
+			Message: "List is archived",
+			Status:  http.StatusConflict,
+		})
+		return
+	}
+	createTodo(ctx, h.TodoRepository, &list.ID)
+}
+
+// findList loads the caller's list named by the :listID parameter, answering
+// 400 or 404 itself when it cannot.
+func (h *ListHandler) findList(ctx *gin.Context) (*entity.List, bool) {
+	listID, ok := int64Param(ctx, "listID")
+	if !ok {
+		return nil, false
+	}
+	list, err := h.ListRepository.GetByID(currentUserID(ctx), listID)
+	if err != nil {
+		logrus.Errorf("failed when get list by id: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	if list == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "List not Found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, false
+	}
+	return list, true
+}
@@ -0,0 +1,198 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todoGin/mocks"
+	"todoGin/model/entity"
+	"todoGin/model/request"
+	"todoGin/model/respErr"
+	"todoGin/repository"
+)
+
+func setupListRouter(listRepo *mocks.ListRepository, todoRepo *mocks.TodoRepository) *gin.Engine {
+	handler := NewListService(listRepo, todoRepo)
+	router := gin.Default()
+	router.GET("/lists", withUser, handler.ListHandlerGetAll)
+	router.POST("/lists", withUser, handler.ListHandlerCreate)
+	router.GET("/lists/:listID", withUser, handler.ListHandlerGetByID)
+	router.PATCH("/lists/:listID", withUser, handler.ListHandlerUpdate)
+	router.DELETE("/lists/:listID", withUser, handler.ListHandlerDelete)
+	router.GET("/lists/:listID/todos", withUser, handler.ListHandlerGetTodos)
+	router.POST("/lists/:listID/todos", withUser, handler.ListHandlerCreateTodo)
+	return router
+}
+
+func TestListHandlers(t *testing.T) {
+	listID := int64(5)
+	work := &entity.List{ID: listID, UserID: testUserID, Name: "Work"}
+
+	testCases := []struct {
+		name            string
+		method          string
+		url             string
+		body            string
+		mock            func(listRepo *mocks.ListRepository, todoRepo *mocks.TodoRepository)
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			url:    "/lists",
+			body:   `{"name": "Work"}`,
+			mock: func(listRepo *mocks.ListRepository, todoRepo *mocks.TodoRepository) {
+				listRepo.On("Create", &entity.List{UserID: testUserID, Name: "Work"}).Return(work, nil)
+			},
+			expectedStatus:  http.StatusCreated,
+			expectedMessage: "New List Created",
+		},
+		{
+			name:            "Create without name",
+			method:          http.MethodPost,
+			url:             "/lists",
+			body:            `{}`,
+			mock:            func(listRepo *mocks.ListRepository, todoRepo *mocks.TodoRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+		{
+			name:   "Get all archived",
+			method: http.MethodGet,
+			url:    "/lists?archived=true",
+			mock: func(listRepo *mocks.ListRepository, todoRepo *mocks.TodoRepository) {
+				listRepo.On("GetAll", testUserID, true).Return([]entity.List{}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Get Lists",
+		},
+		{
+			name:   "Archive",
+			method: http.MethodPatch,
+			url:    "/lists/5",
+			body:   `{"archived": true}`,
+			mock: func(listRepo *mocks.ListRepository, todoRepo *mocks.TodoRepository) {
+				listRepo.On("Update", testUserID, listID, map[string]interface{}{"archived": true}).
+					Return(&entity.List{ID: listID, Name: "Work", Archived: true}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Update List",
+		},
+		{
+			name:   "Rename missing list",
+			method: http.MethodPatch,
+			url:    "/lists/9",
+			body:   `{"name": "Home"}`,
+			mock: func(listRepo *mocks.ListRepository, todoRepo *mocks.TodoRepository) {
+				listRepo.On("Update", testUserID, int64(9), map[string]interface{}{"name": "Home"}).Return(nil, nil)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "List not Found",
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			url:    "/lists/5",
+			mock: func(listRepo *mocks.ListRepository, todoRepo *mocks.TodoRepository) {
+				listRepo.On("Delete", testUserID, listID).Return(int64(1), nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Delete List",
+		},
+		{
+			name:   "Delete error",
+			method: http.MethodDelete,
+			url:    "/lists/5",
+			mock: func(listRepo *mocks.ListRepository, todoRepo *mocks.TodoRepository) {
+				listRepo.On("Delete", testUserID, listID).Return(int64(0), errors.New("some error"))
+			},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "Internal Server Error",
+		},
+		{
+			name:   "Todos of a list",
+			method: http.MethodGet,
+			url:    "/lists/5/todos",
+			mock: func(listRepo *mocks.ListRepository, todoRepo *mocks.TodoRepository) {
+				listRepo.On("GetByID", testUserID, listID).Return(work, nil)
+				todoRepo.On("GetAll", testUserID, repository.TodoQuery{Limit: request.DefaultPageLimit, ListID: &listID}).
+					Return([]entity.Todolist{{ID: 1, ListID: &listID, Title: "Ship it"}}, int64(1), nil)
+				todoRepo.On("LastModified", testUserID).Return(time.Time{}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Get List Todos",
+		},
+		{
+			name:   "Todos of a missing list",
+			method: http.MethodGet,
+			url:    "/lists/9/todos",
+			mock: func(listRepo *mocks.ListRepository, todoRepo *mocks.TodoRepository) {
+				listRepo.On("GetByID", testUserID, int64(9)).Return(nil, nil)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "List not Found",
+		},
+		{
+			name:   "Create todo in list",
+			method: http.MethodPost,
+			url:    "/lists/5/todos",
+			body:   `{"title": "Ship it"}`,
+			mock: func(listRepo *mocks.ListRepository, todoRepo *mocks.TodoRepository) {
+				listRepo.On("GetByID", testUserID, listID).Return(work, nil)
+				todoRepo.On("Create", mock.MatchedBy(func(todo *entity.Todolist) bool {
+					return todo.ListID != nil && *todo.ListID == listID && todo.Title == "Ship it"
+				})).Return(&entity.Todolist{ID: 1, ListID: &listID, Title: "Ship it"}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "New Todo Created",
+		},
+		{
+			name:   "Create todo in archived list",
+			method: http.MethodPost,
+			url:    "/lists/5/todos",
+			body:   `{"title": "Ship it"}`,
+			mock: func(listRepo *mocks.ListRepository, todoRepo *mocks.TodoRepository) {
+				listRepo.On("GetByID", testUserID, listID).Return(&entity.List{ID: listID, Name: "Work", Archived: true}, nil)
+			},
+			expectedStatus:  http.StatusConflict,
+			expectedMessage: "List is archived",
+		},
+		{
+			name:            "Invalid list ID",
+			method:          http.MethodGet,
+			url:             "/lists/abc",
+			mock:            func(listRepo *mocks.ListRepository, todoRepo *mocks.TodoRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "parse ID error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			listRepo := mocks.NewListRepository(t)
+			todoRepo := mocks.NewTodoRepository(t)
+			tc.mock(listRepo, todoRepo)
+
+			w := httptest.NewRecorder()
+			r, err := http.NewRequest(tc.method, tc.url, bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+			setupListRouter(listRepo, todoRepo).ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var resp respErr.ErrorResponse
+			err = json.Unmarshal(w.Body.Bytes(), &resp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMessage, resp.Message)
+		})
+	}
+}
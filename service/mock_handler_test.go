@@ -14,15 +14,29 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"testing"
+	"time"
+	"todoGin/middleware"
 	"todoGin/mocks"
 	"todoGin/model/entity"
 	"todoGin/model/request"
 	"todoGin/model/respErr"
+	"todoGin/repository"
 )
 
 //
 // golangci-lint run --timeout=5m --fix ./...
 
+const testUserID int64 = 1
+
+// withUser stands in for middleware.JWTAuth and marks the request as testUserID.
+func withUser(ctx *gin.Context) {
+	ctx.Set(middleware.UserIDKey, testUserID)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
+
 func TestTodolist(t *testing.T) {
 	t.Run("TestGetAll", TestGetAll)
 	t.Run("TestCreate", TestCreate)
@@ -42,7 +56,8 @@ func TestGetAll(t *testing.T) {
 
 		// success
 		repo := mocks.NewTodoRepository(t)
-		repo.On("GetAll").Return(mockTodo, nil)
+		repo.On("GetAll", testUserID, mock.Anything).Return(mockTodo, int64(len(mockTodo)), nil)
+		repo.On("LastModified", testUserID).Return(time.Time{}, nil)
 
 		handler := NewTodoService(repo)
 
@@ -53,7 +68,7 @@ func TestGetAll(t *testing.T) {
 
 		rr := httptest.NewRecorder()
 		router := gin.Default()
-		router.GET("/manage-todos", handler.TodolistHandlerGetAll)
+		router.GET("/manage-todos", withUser, handler.TodolistHandlerGetAll)
 		router.ServeHTTP(rr, req)
 
 		var resp request.TodoResponseToGetAll
@@ -71,7 +86,7 @@ func TestGetAll(t *testing.T) {
 	// Internal Server Error
 	t.Run("Internal Server Error", func(t *testing.T) {
 		repo := mocks.NewTodoRepository(t)
-		repo.On("GetAll").Return(nil, errors.New("some error"))
+		repo.On("GetAll", testUserID, mock.Anything).Return(nil, int64(0), errors.New("some error"))
 
 		handler := NewTodoService(repo)
 
@@ -82,7 +97,7 @@ func TestGetAll(t *testing.T) {
 
 		rr := httptest.NewRecorder()
 		router := gin.Default()
-		router.GET("/manage-todos", handler.TodolistHandlerGetAll)
+		router.GET("/manage-todos", withUser, handler.TodolistHandlerGetAll)
 		router.ServeHTTP(rr, req)
 
 		assert.Equal(t, http.StatusInternalServerError, rr.Code)
@@ -99,7 +114,8 @@ func TestGetAll(t *testing.T) {
 
 	t.Run("Empty", func(t *testing.T) {
 		repo := mocks.NewTodoRepository(t)
-		repo.On("GetAll").Return([]entity.Todolist{}, nil)
+		repo.On("GetAll", testUserID, mock.Anything).Return([]entity.Todolist{}, int64(0), nil)
+		repo.On("LastModified", testUserID).Return(time.Time{}, nil)
 
 		handler := NewTodoService(repo)
 
@@ -110,7 +126,7 @@ func TestGetAll(t *testing.T) {
 
 		rr := httptest.NewRecorder()
 		router := gin.Default()
-		router.GET("/manage-todos", handler.TodolistHandlerGetAll)
+		router.GET("/manage-todos", withUser, handler.TodolistHandlerGetAll)
 		router.ServeHTTP(rr, req)
 
 		assert.Equal(t, http.StatusOK, rr.Code)
@@ -141,7 +157,7 @@ func TestCreate(t *testing.T) {
 			Status: false,
 		}
 
-		todoRepo.On("Create", "Makan").Return(newTodo, nil)
+		todoRepo.On("Create", &entity.Todolist{UserID: testUserID, Title: "Makan", Priority: entity.PriorityMedium}).Return(newTodo, nil)
 
 		// Initialize todo service with mock repository
 		handler := NewTodoService(todoRepo)
@@ -149,7 +165,7 @@ func TestCreate(t *testing.T) {
 		// Call the create endpoint
 		endpoint := "/manage-todo"
 		r := gin.New()
-		r.POST(endpoint, handler.TodolistHandlerCreate)
+		r.POST(endpoint, withUser, handler.TodolistHandlerCreate)
 
 		// Create an HTTP request to create a new Todo
 		reqBody := bytes.NewBufferString(`{"title": "Makan"}`)
@@ -232,7 +248,7 @@ func TestCreate(t *testing.T) {
 		expectedError := errors.New("Internal Server Error")
 		endpoint := "/manage-todo"
 
-		todoRepo.On("Create", "Test Todo").Return(nil, expectedError)
+		todoRepo.On("Create", &entity.Todolist{UserID: testUserID, Title: "Test Todo", Priority: entity.PriorityMedium}).Return(nil, expectedError)
 
 		// Create valid input
 		body := bytes.NewBufferString(`{"title": "Test Todo"}`)
@@ -242,7 +258,7 @@ func TestCreate(t *testing.T) {
 		// Set up Gin context
 		w := httptest.NewRecorder()
 		c, r := gin.CreateTestContext(w)
-		r.POST(endpoint, handler.TodolistHandlerCreate)
+		r.POST(endpoint, withUser, handler.TodolistHandlerCreate)
 
 		// Perform request
 		c.Request = req
@@ -260,7 +276,7 @@ func TestCreate(t *testing.T) {
 		assert.Equal(t, expectedError.Error(), errResp.Message)
 
 		// Check mock call
-		todoRepo.AssertCalled(t, "Create", "Test Todo")
+		todoRepo.AssertCalled(t, "Create", &entity.Todolist{UserID: testUserID, Title: "Test Todo", Priority: entity.PriorityMedium})
 	})
 
 }
@@ -277,7 +293,8 @@ func TestUpdate(t *testing.T) {
 
 		// create request body
 		reqBody := request.TodolistUpdateRequest{
-			Title: "New Title",
+			Title:  "New Title",
+			Status: boolPtr(false),
 		}
 		requestBodyBytes, _ := json.Marshal(reqBody)
 
@@ -287,8 +304,7 @@ func TestUpdate(t *testing.T) {
 			Title:  "New Title",
 			Status: false,
 		}
-		mockRepo.On("GetByID", int64(1)).Return(&entity.Todolist{}, nil)
-		mockRepo.On("Update", int64(1), mock.Anything).Return(&expectedTodo, nil)
+		mockRepo.On("Update", testUserID, int64(1), repository.UpdateOptions{}, mock.Anything).Return(&expectedTodo, int64(1), nil)
 
 		// create test request
 		req, _ := http.NewRequest(http.MethodPut, "/manage-todo/todo/1", bytes.NewBuffer(requestBodyBytes))
@@ -296,7 +312,7 @@ func TestUpdate(t *testing.T) {
 
 		// perform test request
 		r := gin.Default()
-		r.PUT("/manage-todo/todo/:id", handler.TodolistHandlerUpdate)
+		r.PUT("/manage-todo/todo/:id", withUser, handler.TodolistHandlerUpdate)
 		r.ServeHTTP(rr, req)
 
 		// check response
@@ -316,12 +332,13 @@ func TestUpdate(t *testing.T) {
 		handler := NewTodoService(mockRepo)
 
 		reqBody1 := request.TodolistUpdateRequest{
-			Title: "New Title",
+			Title:  "New Title",
+			Status: boolPtr(false),
 		}
 		requestBodyBytes, _ := json.Marshal(reqBody1)
 
 		// create mock behavior
-		mockRepo.On("GetByID", int64(2)).Return(nil, nil)
+		mockRepo.On("Update", testUserID, int64(2), repository.UpdateOptions{}, mock.Anything).Return(nil, int64(0), nil)
 
 		// create test request
 		req, _ := http.NewRequest(http.MethodPut, "/manage-todo/todo/2", bytes.NewBuffer(requestBodyBytes))
@@ -329,7 +346,7 @@ func TestUpdate(t *testing.T) {
 
 		// perform test request
 		r := gin.Default()
-		r.PUT("/manage-todo/todo/:id", handler.TodolistHandlerUpdate)
+		r.PUT("/manage-todo/todo/:id", withUser, handler.TodolistHandlerUpdate)
 		r.ServeHTTP(rr, req)
 
 		// check response
@@ -351,15 +368,14 @@ func TestUpdate(t *testing.T) {
 		// membuat object handler dan menambahkan dependensi mock
 		handler := NewTodoService(mockRepo)
 
-		mockRepo.On("GetByID", int64(3)).Return(&entity.Todolist{}, nil)
-		mockRepo.On("Update", int64(3), mock.Anything).Return(nil, errors.New("Internal Server Error"))
+		mockRepo.On("Update", testUserID, int64(3), repository.UpdateOptions{}, mock.Anything).Return(nil, int64(0), errors.New("Internal Server Error"))
 
 		// membuat handler dengan mock object
 
 		// membuat request payload
 		payload := request.TodolistUpdateRequest{
 			Title:  "New Title",
-			Status: false,
+			Status: boolPtr(false),
 		}
 		requestBody, _ := json.Marshal(payload)
 
@@ -369,7 +385,7 @@ func TestUpdate(t *testing.T) {
 
 		// perform test request
 		r := gin.Default()
-		r.PUT("/manage-todo/todo/:id", handler.TodolistHandlerUpdate)
+		r.PUT("/manage-todo/todo/:id", withUser, handler.TodolistHandlerUpdate)
 		r.ServeHTTP(rr, req)
 
 		// melakukan pengecekan status code dan response
@@ -397,12 +413,12 @@ func TestGetByID(t *testing.T) {
 		handler := NewTodoService(mockTodoRepo)
 
 		// testing success
-		mockTodoRepo.On("GetByID", int64(1)).Return(&entity.Todolist{ID: 1, Title: "Test Todo"}, nil)
+		mockTodoRepo.On("GetByID", testUserID, int64(1)).Return(&entity.Todolist{ID: 1, Title: "Test Todo"}, nil)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/manage-todo/todo/1", nil)
 		router := gin.Default()
-		router.GET("/manage-todo/todo/:id", handler.TodolistHandlerGetByID)
+		router.GET("/manage-todo/todo/:id", withUser, handler.TodolistHandlerGetByID)
 		router.ServeHTTP(w, req)
 
 		respBody, err := io.ReadAll(w.Body)
@@ -425,12 +441,12 @@ func TestGetByID(t *testing.T) {
 		// inisiasi handler
 		handler := NewTodoService(mockTodoRepo)
 
-		mockTodoRepo.On("GetByID", int64(2)).Return(nil, nil)
+		mockTodoRepo.On("GetByID", testUserID, int64(2)).Return(nil, nil)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/manage-todo/todo/2", nil)
 		router := gin.Default()
-		router.GET("/manage-todo/todo/:id", handler.TodolistHandlerGetByID)
+		router.GET("/manage-todo/todo/:id", withUser, handler.TodolistHandlerGetByID)
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
@@ -449,12 +465,12 @@ func TestGetByID(t *testing.T) {
 		// inisiasi handler
 		handler := NewTodoService(mockTodoRepo)
 
-		mockTodoRepo.On("GetByID", int64(3)).Return(nil, errors.New("Internal Server Error"))
+		mockTodoRepo.On("GetByID", testUserID, int64(3)).Return(nil, errors.New("Internal Server Error"))
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest("GET", "/manage-todo/todo/3", nil)
 		router := gin.Default()
-		router.GET("/manage-todo/todo/:id", handler.TodolistHandlerGetByID)
+		router.GET("/manage-todo/todo/:id", withUser, handler.TodolistHandlerGetByID)
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -475,12 +491,12 @@ func TestDelete(t *testing.T) {
 		handler := NewTodoService(mockTodoRepo)
 
 		// Testing Success
-		mockTodoRepo.On("Delete", int64(1)).Return(int64(1), nil)
+		mockTodoRepo.On("Delete", testUserID, int64(1), int64(0)).Return(int64(1), nil)
 
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodDelete, "/manage-todo/todo/1", nil)
 		router := gin.Default()
-		router.DELETE("/manage-todo/todo/:id", handler.TodolistHandlerDelete)
+		router.DELETE("/manage-todo/todo/:id", withUser, handler.TodolistHandlerDelete)
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusOK, w.Code)
@@ -498,11 +514,11 @@ func TestDelete(t *testing.T) {
 		mockTodoRepo := mocks.NewTodoRepository(t)
 		handler := NewTodoService(mockTodoRepo)
 
-		mockTodoRepo.On("Delete", int64(2)).Return(int64(0), nil)
+		mockTodoRepo.On("Delete", testUserID, int64(2), int64(0)).Return(int64(0), nil)
 		w := httptest.NewRecorder()
 		req, _ := http.NewRequest(http.MethodDelete, "/manage-todo/todo/2", nil)
 		router := gin.Default()
-		router.DELETE("/manage-todo/todo/:id", handler.TodolistHandlerDelete)
+		router.DELETE("/manage-todo/todo/:id", withUser, handler.TodolistHandlerDelete)
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusNotFound, w.Code)
@@ -519,12 +535,12 @@ func TestDelete(t *testing.T) {
 		mockTodoRepo := mocks.NewTodoRepository(t)
 		handler := NewTodoService(mockTodoRepo)
 
-		mockTodoRepo.On("Delete", int64(3)).Return(int64(0), errors.New("Internal Server Error"))
+		mockTodoRepo.On("Delete", testUserID, int64(3), int64(0)).Return(int64(0), errors.New("Internal Server Error"))
 		w := httptest.NewRecorder()
 
 		req, _ := http.NewRequest(http.MethodDelete, "/manage-todo/todo/3", nil)
 		router := gin.Default()
-		router.DELETE("/manage-todo/todo/:id", handler.TodolistHandlerDelete)
+		router.DELETE("/manage-todo/todo/:id", withUser, handler.TodolistHandlerDelete)
 		router.ServeHTTP(w, req)
 
 		assert.Equal(t, http.StatusInternalServerError, w.Code)
@@ -0,0 +1,85 @@
+package service
+
+import (
+	"github.com/gin-contrib/sse"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+	"todoGin/model/respErr"
+	"todoGin/stream"
+)
+
+// EventReset tells a resuming client that events were missed and its copy of
+// the todos has to be fetched again.
+const EventReset = "reset"
+
+type StreamHandler struct {
+	Broker    *stream.Broker
+	Heartbeat time.Duration
+}
+
+func NewStreamService(broker *stream.Broker, heartbeat time.Duration) *StreamHandler {
+	return &StreamHandler{
+		Broker:    broker,
+		Heartbeat: heartbeat,
+	}
+}
+
+// StreamHandlerTodos pushes the caller's todo events as Server-Sent Events
+// until the client disconnects. A client resuming with Last-Event-ID (or
+// ?last_event_id=) first gets the events it missed, or a reset event when
+// they are no longer buffered. Comments are sent every Heartbeat to keep
+// idle connections open.
+func (h *StreamHandler) StreamHandlerTodos(ctx *gin.Context) {
+	lastID := ctx.GetHeader("Last-Event-ID")
+	if lastID == "" {
+		lastID = ctx.Query("last_event_id")
+	}
+	sub, backlog, complete := h.Broker.Subscribe(currentUserID(ctx), lastID)
+	if sub == nil {
+		ctx.AbortWithStatusJSON(http.StatusServiceUnavailable, respErr.ErrorResponse{
+			Message: "Server is shutting down",
+			Status:  http.StatusServiceUnavailable,
+		})
+		return
+	}
+	defer sub.Close()
+
+	logrus.Info(http.StatusOK, " Success Open Stream")
+	ctx.Header("Content-Type", sse.ContentType)
+	ctx.Header("Cache-Control", "no-cache")
+	ctx.Header("X-Accel-Buffering", "no")
+	ctx.Status(http.StatusOK)
+	if !complete {
+		ctx.Render(-1, sse.Event{Event: EventReset, Data: "{}"})
+	}
+	for _, event := range backlog {
+		renderEvent(ctx, event)
+	}
+	// sends the headers even when there is nothing to replay
+	ctx.Writer.Flush()
+
+	heartbeat := time.NewTicker(h.Heartbeat)
+	defer heartbeat.Stop()
+	for {
+		select {
+		case <-ctx.Request.Context().Done():
+			return
+		case event, ok := <-sub.C:
+			if !ok {
+				return
+			}
+			renderEvent(ctx, event)
+		case <-heartbeat.C:
+			if _, err := ctx.Writer.WriteString(": ping\n\n"); err != nil {
+				return
+			}
+		}
+		ctx.Writer.Flush()
+	}
+}
+
+func renderEvent(ctx *gin.Context, event stream.Event) {
+	ctx.Render(-1, sse.Event{Id: event.ID, Event: event.Name, Data: event.Data})
+}
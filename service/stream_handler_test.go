@@ -0,0 +1,126 @@
+package service
+
+import (
+	"context"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todoGin/model/entity"
+	"todoGin/stream"
+)
+
+// openStream serves GET /manage-todos/stream in the background. stop
+// disconnects the client and waits for the handler; done is closed once the
+// handler has returned.
+func openStream(t *testing.T, broker *stream.Broker, lastEventID string) (w *httptest.ResponseRecorder, stop func(), done <-chan struct{}) {
+	handler := NewStreamService(broker, time.Minute)
+	router := gin.Default()
+	router.GET("/manage-todos/stream", withUser, handler.StreamHandlerTodos)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	r, _ := http.NewRequestWithContext(ctx, http.MethodGet, "/manage-todos/stream", nil)
+	if lastEventID != "" {
+		r.Header.Set("Last-Event-ID", lastEventID)
+	}
+	w = httptest.NewRecorder()
+	finished := make(chan struct{})
+	go func() {
+		router.ServeHTTP(w, r)
+		close(finished)
+	}()
+	return w, func() {
+		cancel()
+		<-finished
+	}, finished
+}
+
+func publishTodoEvent(t *testing.T, broker *stream.Broker, userID int64, eventID, event string) {
+	require.NoError(t, broker.Publish(context.Background(), entity.OutboxEvent{
+		EventID: eventID,
+		UserID:  userID,
+		Event:   event,
+		Payload: `{"id":"` + eventID + `"}`,
+	}))
+}
+
+// waitSubscribed gives the handler time to subscribe before publishing.
+func waitSubscribed() {
+	time.Sleep(50 * time.Millisecond)
+}
+
+func TestStreamHandler(t *testing.T) {
+	t.Run("Live events", func(t *testing.T) {
+		broker := stream.NewBroker(16)
+		w, stop, _ := openStream(t, broker, "")
+		waitSubscribed()
+		publishTodoEvent(t, broker, testUserID, "a", entity.EventTodoCreated)
+		publishTodoEvent(t, broker, testUserID+1, "b", entity.EventTodoCreated)
+		publishTodoEvent(t, broker, testUserID, "c", entity.EventTodoDeleted)
+		time.Sleep(50 * time.Millisecond)
+		stop()
+
+		assert.Equal(t, http.StatusOK, w.Code)
+		assert.Equal(t, "text/event-stream", w.Header().Get("Content-Type"))
+		body := w.Body.String()
+		assert.Contains(t, body, "event:todo.created\ndata:{\"id\":\"a\"}\n\n")
+		assert.Contains(t, body, "event:todo.deleted\ndata:{\"id\":\"c\"}\n\n")
+		assert.NotContains(t, body, `"b"`)
+		assert.Equal(t, 2, strings.Count(body, "id:"))
+	})
+
+	t.Run("Resume", func(t *testing.T) {
+		broker := stream.NewBroker(16)
+		sub, _, _ := broker.Subscribe(testUserID, "")
+		publishTodoEvent(t, broker, testUserID, "a", entity.EventTodoCreated)
+		publishTodoEvent(t, broker, testUserID, "b", entity.EventTodoUpdated)
+		first := <-sub.C
+		sub.Close()
+
+		w, stop, _ := openStream(t, broker, first.ID)
+		waitSubscribed()
+		stop()
+
+		body := w.Body.String()
+		assert.NotContains(t, body, `"a"`)
+		assert.Contains(t, body, "event:todo.updated\ndata:{\"id\":\"b\"}\n\n")
+		assert.NotContains(t, body, "event:"+EventReset)
+	})
+
+	t.Run("Resume from a lost position", func(t *testing.T) {
+		broker := stream.NewBroker(16)
+		w, stop, _ := openStream(t, broker, "gone-42")
+		waitSubscribed()
+		stop()
+
+		assert.True(t, strings.HasPrefix(w.Body.String(), "event:"+EventReset+"\n"))
+	})
+
+	t.Run("Shutting down", func(t *testing.T) {
+		broker := stream.NewBroker(16)
+		broker.Close()
+		w, stop, _ := openStream(t, broker, "")
+		stop()
+
+		assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	})
+
+	t.Run("Ends when the broker closes", func(t *testing.T) {
+		broker := stream.NewBroker(16)
+		w, stop, done := openStream(t, broker, "")
+		defer stop()
+		waitSubscribed()
+		broker.Close()
+
+		select {
+		case <-done:
+		case <-time.After(time.Second):
+			t.Fatal("stream still open after the broker closed")
+		}
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
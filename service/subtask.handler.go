@@ -0,0 +1,180 @@
+package service
+
+import (
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"todoGin/model/request"
+	"todoGin/model/respErr"
+	"todoGin/repository"
+)
+
+type SubtaskHandler struct {
+	SubtaskRepository repository.SubtaskRepository
+}
+
+func NewSubtaskService(subtaskRepo repository.SubtaskRepository) *SubtaskHandler {
+	return &SubtaskHandler{
+		SubtaskRepository: subtaskRepo,
+	}
+}
+
+func (h *SubtaskHandler) SubtaskHandlerCreate(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	reqBody := new(request.SubtaskCreateRequest)
+	if err := ctx.ShouldBindJSON(reqBody); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid input",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	subtask, err := h.SubtaskRepository.Create(currentUserID(ctx), todoID, reqBody.Title)
+	if err != nil {
+		logrus.Errorf("failed when creating subtask: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if subtask == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "ID not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusCreated, " Success Create Subtask")
+	ctx.JSON(http.StatusCreated, request.SubtaskResponse{
+		Status:  http.StatusCreated,
+		Message: "New Subtask Created",
+		Data:    *subtask,
+	})
+}
+
+// SubtaskHandlerUpdate renames a subtask or marks it done or not done.
+func (h *SubtaskHandler) SubtaskHandlerUpdate(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	subtaskID, ok := int64Param(ctx, "subtaskID")
+	if !ok {
+		return
+	}
+	reqBody := new(request.SubtaskUpdateRequest)
+	if err := ctx.ShouldBindJSON(reqBody); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Bad request",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	subtask, err := h.SubtaskRepository.Update(currentUserID(ctx), todoID, subtaskID, reqBody.ReqSubtask())
+	if err != nil {
+		logrus.Errorf("failed when updating subtask: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if subtask == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "ID not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Update Subtask")
+	ctx.JSON(http.StatusOK, request.SubtaskResponse{
+		Status:  http.StatusOK,
+		Message: "Success Update Subtask",
+		Data:    *subtask,
+	})
+}
+
+func (h *SubtaskHandler) SubtaskHandlerDelete(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	subtaskID, ok := int64Param(ctx, "subtaskID")
+	if !ok {
+		return
+	}
+	isFound, err := h.SubtaskRepository.Delete(currentUserID(ctx), todoID, subtaskID)
+	if err != nil {
+		logrus.Errorf("failed when deleting subtask: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if isFound == 0 {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Delete Subtask")
+	ctx.JSON(http.StatusOK, request.TodoDeleteResponse{
+		Status:  http.StatusOK,
+		Message: "Success Delete Subtask",
+	})
+}
+
+// SubtaskHandlerReorder puts the checklist in the order of the given IDs.
+func (h *SubtaskHandler) SubtaskHandlerReorder(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	reqBody := new(request.SubtaskReorderRequest)
+	if err := ctx.ShouldBindJSON(reqBody); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Bad request",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	subtasks, err := h.SubtaskRepository.Reorder(currentUserID(ctx), todoID, reqBody.IDs)
+	if errors.Is(err, repository.ErrInvalidOrder) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Order must list every subtask once",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when reordering subtasks: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if subtasks == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "ID not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Reorder Subtasks")
+	ctx.JSON(http.StatusOK, request.SubtasksResponse{
+		Message:  "Success Reorder Subtasks",
+		Data:     len(subtasks),
+		Subtasks: subtasks,
+	})
+}
@@ -0,0 +1,186 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todoGin/mocks"
+	"todoGin/model/entity"
+	"todoGin/model/request"
+	"todoGin/model/respErr"
+	"todoGin/repository"
+)
+
+func TestSubtaskHandlers(t *testing.T) {
+	testCases := []struct {
+		name            string
+		method          string
+		url             string
+		body            string
+		mock            func(repo *mocks.SubtaskRepository)
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:   "Add",
+			method: http.MethodPost,
+			url:    "/manage-todo/todo/1/subtasks",
+			body:   `{"title": "Buy milk"}`,
+			mock: func(repo *mocks.SubtaskRepository) {
+				repo.On("Create", testUserID, int64(1), "Buy milk").
+					Return(&entity.Subtask{ID: 10, TodoID: 1, Title: "Buy milk", Position: 1}, nil)
+			},
+			expectedStatus:  http.StatusCreated,
+			expectedMessage: "New Subtask Created",
+		},
+		{
+			name:   "Add to missing todo",
+			method: http.MethodPost,
+			url:    "/manage-todo/todo/2/subtasks",
+			body:   `{"title": "Buy milk"}`,
+			mock: func(repo *mocks.SubtaskRepository) {
+				repo.On("Create", testUserID, int64(2), "Buy milk").Return(nil, nil)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "ID not Found",
+		},
+		{
+			name:            "Add without title",
+			method:          http.MethodPost,
+			url:             "/manage-todo/todo/1/subtasks",
+			body:            `{}`,
+			mock:            func(repo *mocks.SubtaskRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+		{
+			name:   "Complete",
+			method: http.MethodPatch,
+			url:    "/manage-todo/todo/1/subtasks/10",
+			body:   `{"status": true}`,
+			mock: func(repo *mocks.SubtaskRepository) {
+				repo.On("Update", testUserID, int64(1), int64(10), map[string]interface{}{"status": true}).
+					Return(&entity.Subtask{ID: 10, TodoID: 1, Title: "Buy milk", Status: true}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Update Subtask",
+		},
+		{
+			name:   "Delete missing subtask",
+			method: http.MethodDelete,
+			url:    "/manage-todo/todo/1/subtasks/11",
+			mock: func(repo *mocks.SubtaskRepository) {
+				repo.On("Delete", testUserID, int64(1), int64(11)).Return(int64(0), nil)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "Not Found",
+		},
+		{
+			name:   "Reorder",
+			method: http.MethodPut,
+			url:    "/manage-todo/todo/1/subtasks/order",
+			body:   `{"ids": [11, 10]}`,
+			mock: func(repo *mocks.SubtaskRepository) {
+				repo.On("Reorder", testUserID, int64(1), []int64{11, 10}).Return([]entity.Subtask{
+					{ID: 11, TodoID: 1, Position: 1},
+					{ID: 10, TodoID: 1, Position: 2},
+				}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Reorder Subtasks",
+		},
+		{
+			name:   "Reorder with missing IDs",
+			method: http.MethodPut,
+			url:    "/manage-todo/todo/1/subtasks/order",
+			body:   `{"ids": [11]}`,
+			mock: func(repo *mocks.SubtaskRepository) {
+				repo.On("Reorder", testUserID, int64(1), []int64{11}).Return(nil, repository.ErrInvalidOrder)
+			},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Order must list every subtask once",
+		},
+		{
+			name:   "Reorder error",
+			method: http.MethodPut,
+			url:    "/manage-todo/todo/1/subtasks/order",
+			body:   `{"ids": [11, 10]}`,
+			mock: func(repo *mocks.SubtaskRepository) {
+				repo.On("Reorder", testUserID, int64(1), []int64{11, 10}).Return(nil, errors.New("some error"))
+			},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "Internal Server Error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewSubtaskRepository(t)
+			tc.mock(repo)
+			handler := NewSubtaskService(repo)
+
+			router := gin.Default()
+			router.POST("/manage-todo/todo/:id/subtasks", withUser, handler.SubtaskHandlerCreate)
+			router.PUT("/manage-todo/todo/:id/subtasks/order", withUser, handler.SubtaskHandlerReorder)
+			router.PATCH("/manage-todo/todo/:id/subtasks/:subtaskID", withUser, handler.SubtaskHandlerUpdate)
+			router.DELETE("/manage-todo/todo/:id/subtasks/:subtaskID", withUser, handler.SubtaskHandlerDelete)
+
+			w := httptest.NewRecorder()
+			r, err := http.NewRequest(tc.method, tc.url, bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var resp respErr.ErrorResponse
+			err = json.Unmarshal(w.Body.Bytes(), &resp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMessage, resp.Message)
+		})
+	}
+}
+
+func TestSubtaskProgressAndCascade(t *testing.T) {
+	t.Run("Progress on get", func(t *testing.T) {
+		repo := mocks.NewTodoRepository(t)
+		repo.On("GetByID", testUserID, int64(1)).Return(&entity.Todolist{ID: 1, Title: "Groceries", Subtasks: []entity.Subtask{
+			{ID: 10, Status: true},
+			{ID: 11, Status: true},
+			{ID: 12},
+		}}, nil)
+		handler := NewTodoService(repo)
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "/manage-todo/todo/1", nil)
+		router := gin.Default()
+		router.GET("/manage-todo/todo/:id", withUser, handler.TodolistHandlerGetByID)
+		router.ServeHTTP(w, r)
+
+		require.Equal(t, http.StatusOK, w.Code)
+		var resp request.TodoResponse
+		require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+		assert.Equal(t, &request.Progress{Done: 2, Total: 3}, resp.Progress)
+		assert.Len(t, resp.Data.Subtasks, 3)
+	})
+
+	t.Run("Cascade on completion", func(t *testing.T) {
+		repo := mocks.NewTodoRepository(t)
+		repo.On("Update", testUserID, int64(1), repository.UpdateOptions{CascadeSubtasks: true}, map[string]interface{}{"status": true}).
+			Return(&entity.Todolist{ID: 1, Title: "Groceries", Status: true}, int64(1), nil)
+		handler := NewTodoService(repo)
+
+		w := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodPatch, "/manage-todo/todo/1?cascade=true", bytes.NewBufferString(`{"status": true}`))
+		router := gin.Default()
+		router.PATCH("/manage-todo/todo/:id", withUser, handler.TodolistHandlerPatch)
+		router.ServeHTTP(w, r)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
@@ -15,13 +15,16 @@ import (
 	"net/http/httptest"
 	"strconv"
 	"testing"
+	"time"
 	"todoGin/mocks"
 	"todoGin/model/entity"
 	"todoGin/model/request"
 	"todoGin/model/respErr"
+	"todoGin/repository"
 )
 
 func TestGetAll1(t *testing.T) {
+	commentCount := int64(3)
 
 	testCases := []struct {
 		name               string
@@ -47,6 +50,20 @@ func TestGetAll1(t *testing.T) {
 				},
 			},
 		},
+		{
+			name:               "With comment counts",
+			expectedStatusCode: http.StatusOK,
+			mockTodo: []entity.Todolist{
+				{ID: 1, Title: "Task 1", CommentCount: &commentCount},
+			},
+			expectedResponse: request.TodoResponseToGetAll{
+				Message: "Success Get All",
+				Data:    1,
+				Todos: []entity.Todolist{
+					{ID: 1, Title: "Task 1", CommentCount: &commentCount},
+				},
+			},
+		},
 		{
 			name:               "Internal Server Error",
 			expectedStatusCode: http.StatusInternalServerError,
@@ -74,7 +91,10 @@ func TestGetAll1(t *testing.T) {
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			repo := mocks.NewTodoRepository(t)
-			repo.On("GetAll").Return(tc.mockTodo, tc.mockErr)
+			repo.On("GetAll", testUserID, mock.Anything).Return(tc.mockTodo, int64(len(tc.mockTodo)), tc.mockErr)
+			if tc.mockErr == nil {
+				repo.On("LastModified", testUserID).Return(time.Time{}, nil)
+			}
 
 			handler := NewTodoService(repo)
 
@@ -85,7 +105,7 @@ func TestGetAll1(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			router := gin.Default()
-			router.GET("/manage-todos", handler.TodolistHandlerGetAll)
+			router.GET("/manage-todos", withUser, handler.TodolistHandlerGetAll)
 			router.ServeHTTP(w, r)
 
 			assert.Equal(t, tc.expectedStatusCode, w.Code)
@@ -126,7 +146,7 @@ func TestCreate1(t *testing.T) {
 					Title:  "Makan",
 					Status: false,
 				}
-				mock.On("Create", "Makan").Return(newTodo, nil)
+				mock.On("Create", &entity.Todolist{UserID: testUserID, Title: "Makan", Priority: entity.PriorityMedium}).Return(newTodo, nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedData: entity.Todolist{
@@ -135,6 +155,43 @@ func TestCreate1(t *testing.T) {
 			},
 			expectedError: "",
 		},
+		{
+			name: "With details",
+			body: `{"title": "Deploy", "description": "run migrations", "priority": "high", "due_date": "2023-05-01T10:00:00Z"}`,
+			mock: func(mock *mocks.TodoRepository) {
+				description := "run migrations"
+				dueDate := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+				newTodo := &entity.Todolist{
+					UserID:      testUserID,
+					Title:       "Deploy",
+					Description: &description,
+					DueDate:     &dueDate,
+					Priority:    entity.PriorityHigh,
+				}
+				mock.On("Create", newTodo).Return(newTodo, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedData: func() entity.Todolist {
+				description := "run migrations"
+				dueDate := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+				return entity.Todolist{
+					UserID:      testUserID,
+					Title:       "Deploy",
+					Description: &description,
+					DueDate:     &dueDate,
+					Priority:    entity.PriorityHigh,
+				}
+			}(),
+			expectedError: "",
+		},
+		{
+			name:           "Invalid priority",
+			body:           `{"title": "Deploy", "priority": "someday"}`,
+			mock:           func(mock *mocks.TodoRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedData:   entity.Todolist{},
+			expectedError:  "Invalid input",
+		},
 		{
 			name:           "Invalid input",
 			body:           `{"title": ""}`,
@@ -148,7 +205,7 @@ func TestCreate1(t *testing.T) {
 			body: `{"title": "Test Todo"}`,
 			mock: func(mock *mocks.TodoRepository) {
 				expectedError := errors.New("Internal Server Error")
-				mock.On("Create", "Test Todo").Return(nil, expectedError)
+				mock.On("Create", &entity.Todolist{UserID: testUserID, Title: "Test Todo", Priority: entity.PriorityMedium}).Return(nil, expectedError)
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedData:   entity.Todolist{},
@@ -170,7 +227,7 @@ func TestCreate1(t *testing.T) {
 
 			w := httptest.NewRecorder()
 			c, r := gin.CreateTestContext(w)
-			r.POST(endpoint, handler.TodolistHandlerCreate)
+			r.POST(endpoint, withUser, handler.TodolistHandlerCreate)
 
 			c.Request = req
 			r.ServeHTTP(w, req)
@@ -246,12 +303,12 @@ func TestTodolistHandlerDelete(t *testing.T) {
 
 	for _, tc := range tests {
 		t.Run(tc.name, func(t *testing.T) {
-			mockRepo.On("Delete", tc.todoID).Return(tc.isFound, tc.repoError)
+			mockRepo.On("Delete", testUserID, tc.todoID, int64(0)).Return(tc.isFound, tc.repoError)
 
 			w := httptest.NewRecorder()
 			r, _ := http.NewRequest(http.MethodDelete, "/manage-todo/todo/"+strconv.FormatInt(tc.todoID, 10), nil)
 			router := gin.Default()
-			router.DELETE("/manage-todo/todo/:id", handler.TodolistHandlerDelete)
+			router.DELETE("/manage-todo/todo/:id", withUser, handler.TodolistHandlerDelete)
 			router.ServeHTTP(w, r)
 
 			assert.Equal(t, tc.expStatus, w.Code)
@@ -313,12 +370,12 @@ func TestGetByID1(t *testing.T) {
 			mockTodoRepo := mocks.NewTodoRepository(t)
 			handler := NewTodoService(mockTodoRepo)
 
-			mockTodoRepo.On("GetByID", tc.inputID).Return(tc.mockResult, tc.mockError)
+			mockTodoRepo.On("GetByID", testUserID, tc.inputID).Return(tc.mockResult, tc.mockError)
 
 			w := httptest.NewRecorder()
 			req, _ := http.NewRequest("GET", fmt.Sprintf("/manage-todo/todo/%d", tc.inputID), nil)
 			router := gin.Default()
-			router.GET("/manage-todo/todo/:id", handler.TodolistHandlerGetByID)
+			router.GET("/manage-todo/todo/:id", withUser, handler.TodolistHandlerGetByID)
 			router.ServeHTTP(w, req)
 
 			respBody, err := io.ReadAll(w.Body)
@@ -357,7 +414,8 @@ func TestUpdate1(t *testing.T) {
 			name: "Success",
 			id:   1,
 			requestPayload: request.TodolistUpdateRequest{
-				Title: "New Title",
+				Title:  "New Title",
+				Status: boolPtr(false),
 			},
 			mockBehavior: func() {
 				expectedTodo := entity.Todolist{
@@ -365,8 +423,7 @@ func TestUpdate1(t *testing.T) {
 					Title:  "New Title",
 					Status: false,
 				}
-				mockRepo.On("GetByID", int64(1)).Return(&entity.Todolist{}, nil)
-				mockRepo.On("Update", int64(1), mock.Anything).Return(&expectedTodo, nil)
+				mockRepo.On("Update", testUserID, int64(1), repository.UpdateOptions{}, mock.Anything).Return(&expectedTodo, int64(1), nil)
 			},
 			expectedStatus: http.StatusOK,
 			expectedResp: map[string]interface{}{
@@ -380,10 +437,11 @@ func TestUpdate1(t *testing.T) {
 			name: "Not Found",
 			id:   2,
 			requestPayload: request.TodolistUpdateRequest{
-				Title: "New Title",
+				Title:  "New Title",
+				Status: boolPtr(false),
 			},
 			mockBehavior: func() {
-				mockRepo.On("GetByID", int64(2)).Return(nil, nil)
+				mockRepo.On("Update", testUserID, int64(2), repository.UpdateOptions{}, mock.Anything).Return(nil, int64(0), nil)
 			},
 			expectedStatus: http.StatusNotFound,
 			expectedResp: respErr.ErrorResponse{
@@ -397,11 +455,10 @@ func TestUpdate1(t *testing.T) {
 			id:   3,
 			requestPayload: request.TodolistUpdateRequest{
 				Title:  "New Title",
-				Status: false,
+				Status: boolPtr(false),
 			},
 			mockBehavior: func() {
-				mockRepo.On("GetByID", int64(3)).Return(&entity.Todolist{}, nil)
-				mockRepo.On("Update", int64(3), mock.Anything).Return(nil, errors.New("Internal Server Error"))
+				mockRepo.On("Update", testUserID, int64(3), repository.UpdateOptions{}, mock.Anything).Return(nil, int64(0), errors.New("Internal Server Error"))
 			},
 			expectedStatus: http.StatusInternalServerError,
 			expectedResp: respErr.ErrorResponse{
@@ -422,7 +479,7 @@ func TestUpdate1(t *testing.T) {
 			w := httptest.NewRecorder()
 
 			r := gin.Default()
-			r.PUT("/manage-todo/todo/:id", handler.TodolistHandlerUpdate)
+			r.PUT("/manage-todo/todo/:id", withUser, handler.TodolistHandlerUpdate)
 			r.ServeHTTP(w, req)
 
 			respBody, err := io.ReadAll(w.Body)
@@ -451,3 +508,854 @@ func TestUpdate1(t *testing.T) {
 		})
 	}
 }
+
+func TestGetAllQuery(t *testing.T) {
+	done := true
+
+	testCases := []struct {
+		name               string
+		url                string
+		expectedQuery      repository.TodoQuery
+		mockTodo           []entity.Todolist
+		mockTotal          int64
+		expectedStatusCode int
+		expectedNextOffset *int
+	}{
+		{
+			name:               "Defaults",
+			url:                "/manage-todos",
+			expectedQuery:      repository.TodoQuery{Limit: request.DefaultPageLimit},
+			mockTodo:           []entity.Todolist{{ID: 1, Title: "Task 1"}},
+			mockTotal:          1,
+			expectedStatusCode: http.StatusOK,
+			expectedNextOffset: nil,
+		},
+		{
+			name:               "Paged, sorted and filtered",
+			url:                "/manage-todos?limit=2&offset=2&sort=-created_at&status=true",
+			expectedQuery:      repository.TodoQuery{Limit: 2, Offset: 2, Sort: "created_at", Desc: true, Status: &done},
+			mockTodo:           []entity.Todolist{{ID: 3, Title: "Task 3"}, {ID: 4, Title: "Task 4"}},
+			mockTotal:          5,
+			expectedStatusCode: http.StatusOK,
+			expectedNextOffset: func() *int { next := 4; return &next }(),
+		},
+		{
+			name:               "All tags",
+			url:                "/manage-todos?tag=backend&tag=release-1.4&tag=backend",
+			expectedQuery:      repository.TodoQuery{Limit: request.DefaultPageLimit, Tags: []string{"backend", "release-1.4"}, MatchAllTags: true},
+			mockTodo:           []entity.Todolist{{ID: 1, Title: "Task 1"}},
+			mockTotal:          1,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Any tag",
+			url:                "/manage-todos?tag=backend&tag=urgent&tag_mode=or",
+			expectedQuery:      repository.TodoQuery{Limit: request.DefaultPageLimit, Tags: []string{"backend", "urgent"}},
+			mockTodo:           []entity.Todolist{{ID: 1, Title: "Task 1"}},
+			mockTotal:          1,
+			expectedStatusCode: http.StatusOK,
+		},
+		{
+			name:               "Invalid tag mode",
+			url:                "/manage-todos?tag=backend&tag_mode=xor",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Invalid sort",
+			url:                "/manage-todos?sort=password",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+		{
+			name:               "Limit too large",
+			url:                "/manage-todos?limit=1000",
+			expectedStatusCode: http.StatusBadRequest,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTodoRepository(t)
+			if tc.expectedStatusCode == http.StatusOK {
+				repo.On("GetAll", testUserID, tc.expectedQuery).Return(tc.mockTodo, tc.mockTotal, nil)
+				repo.On("LastModified", testUserID).Return(time.Time{}, nil)
+			}
+			handler := NewTodoService(repo)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, tc.url, nil)
+			router := gin.Default()
+			router.GET("/manage-todos", withUser, handler.TodolistHandlerGetAll)
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatusCode, w.Code)
+			if tc.expectedStatusCode != http.StatusOK {
+				return
+			}
+
+			var resp request.TodoResponseToGetAll
+			err := json.Unmarshal(w.Body.Bytes(), &resp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.mockTotal, resp.Total)
+			assert.Equal(t, len(tc.mockTodo), resp.Data)
+			assert.Equal(t, tc.expectedNextOffset, resp.NextOffset)
+		})
+	}
+}
+
+func TestSearch(t *testing.T) {
+	testCases := []struct {
+		name           string
+		url            string
+		mock           func(repo *mocks.TodoRepository)
+		expectedStatus int
+		expectedTodos  []request.TodoSearchItem
+	}{
+		{
+			name: "Success",
+			url:  "/manage-todos/search?q=deploy",
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Search", testUserID, "deploy", request.DefaultPageLimit).Return([]entity.TodoSearchResult{
+					{Todolist: entity.Todolist{ID: 1, Title: "Deploy backend"}, Relevance: 0.9},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedTodos: []request.TodoSearchItem{
+				{
+					Todolist:   entity.Todolist{ID: 1, Title: "Deploy backend"},
+					Relevance:  0.9,
+					Highlights: map[string]string{"title": "<mark>Deploy</mark> backend"},
+				},
+			},
+		},
+		{
+			name:           "Missing query",
+			url:            "/manage-todos/search",
+			mock:           func(repo *mocks.TodoRepository) {},
+			expectedStatus: http.StatusBadRequest,
+		},
+		{
+			name: "Internal Server Error",
+			url:  "/manage-todos/search?q=deploy&limit=5",
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Search", testUserID, "deploy", 5).Return(nil, errors.New("some error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTodoRepository(t)
+			tc.mock(repo)
+			handler := NewTodoService(repo)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, tc.url, nil)
+			router := gin.Default()
+			router.GET("/manage-todos/search", withUser, handler.TodolistHandlerSearch)
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus != http.StatusOK {
+				return
+			}
+
+			var resp request.TodoSearchResponse
+			err := json.Unmarshal(w.Body.Bytes(), &resp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedTodos, resp.Todos)
+		})
+	}
+}
+
+func TestRestore(t *testing.T) {
+	testCases := []struct {
+		name           string
+		todoID         int64
+		mock           func(repo *mocks.TodoRepository)
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{
+			name:   "Success",
+			todoID: 1,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Restore", testUserID, int64(1)).Return(int64(1), nil)
+				repo.On("GetByID", testUserID, int64(1)).Return(&entity.Todolist{ID: 1, Title: "Back again"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedMsg:    "Success Restore",
+		},
+		{
+			name:   "Not in trash",
+			todoID: 2,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Restore", testUserID, int64(2)).Return(int64(0), nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedMsg:    "Not Found",
+		},
+		{
+			name:   "Internal Server Error",
+			todoID: 3,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Restore", testUserID, int64(3)).Return(int64(0), errors.New("some error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    "Internal Server Error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTodoRepository(t)
+			tc.mock(repo)
+			handler := NewTodoService(repo)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, fmt.Sprintf("/manage-todo/todo/%d/restore", tc.todoID), nil)
+			router := gin.Default()
+			router.POST("/manage-todo/todo/:id/restore", withUser, handler.TodolistHandlerRestore)
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var resp respErr.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &resp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMsg, resp.Message)
+		})
+	}
+}
+
+func TestGetTrash(t *testing.T) {
+	repo := mocks.NewTodoRepository(t)
+	repo.On("GetAll", testUserID, repository.TodoQuery{Limit: request.DefaultPageLimit, Trashed: true}).
+		Return([]entity.Todolist{{ID: 4, Title: "Deleted"}}, int64(1), nil)
+	repo.On("LastModified", testUserID).Return(time.Time{}, nil)
+	handler := NewTodoService(repo)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodGet, "/manage-todos/trash", nil)
+	router := gin.Default()
+	router.GET("/manage-todos/trash", withUser, handler.TodolistHandlerGetTrash)
+	router.ServeHTTP(w, r)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	var resp request.TodoResponseToGetAll
+	err := json.Unmarshal(w.Body.Bytes(), &resp)
+	require.NoError(t, err)
+	assert.Equal(t, "Success Get Trash", resp.Message)
+	assert.Equal(t, []entity.Todolist{{ID: 4, Title: "Deleted"}}, resp.Todos)
+}
+
+func TestPatch(t *testing.T) {
+	testCases := []struct {
+		name            string
+		body            string
+		mock            func(repo *mocks.TodoRepository)
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name: "Only status",
+			body: `{"status": true}`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Update", testUserID, int64(1), repository.UpdateOptions{}, map[string]interface{}{"status": true}).
+					Return(&entity.Todolist{ID: 1, Title: "Keep me", Status: true}, int64(1), nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Patch Todo",
+		},
+		{
+			name: "Null clears optional fields",
+			body: `{"description": null, "due_date": null, "priority": null}`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Update", testUserID, int64(1), repository.UpdateOptions{}, map[string]interface{}{
+					"description": nil,
+					"due_date":    nil,
+					"priority":    entity.PriorityMedium,
+				}).Return(&entity.Todolist{ID: 1, Title: "Keep me"}, int64(1), nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Patch Todo",
+		},
+		{
+			name:            "Null title",
+			body:            `{"title": null}`,
+			mock:            func(repo *mocks.TodoRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Bad request",
+		},
+		{
+			name:            "Invalid priority",
+			body:            `{"priority": "someday"}`,
+			mock:            func(repo *mocks.TodoRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Bad request",
+		},
+		{
+			name:            "Not an object",
+			body:            `["status", true]`,
+			mock:            func(repo *mocks.TodoRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Bad request",
+		},
+		{
+			name: "No change",
+			body: `{"title": "Keep me"}`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Update", testUserID, int64(1), repository.UpdateOptions{}, map[string]interface{}{"title": "Keep me"}).
+					Return(&entity.Todolist{ID: 1, Title: "Keep me"}, int64(0), nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Not Change",
+		},
+		{
+			name: "Not Found",
+			body: `{"title": "New Title"}`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Update", testUserID, int64(1), repository.UpdateOptions{}, map[string]interface{}{"title": "New Title"}).Return(nil, int64(0), nil)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "ID not Found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTodoRepository(t)
+			tc.mock(repo)
+			handler := NewTodoService(repo)
+
+			w := httptest.NewRecorder()
+			req, _ := http.NewRequest(http.MethodPatch, "/manage-todo/todo/1", bytes.NewBufferString(tc.body))
+			r := gin.Default()
+			r.PATCH("/manage-todo/todo/:id", withUser, handler.TodolistHandlerPatch)
+			r.ServeHTTP(w, req)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			if tc.expectedMessage == "Not Change" {
+				var resp request.TodoIDResponse
+				err := json.Unmarshal(w.Body.Bytes(), &resp)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedMessage, resp.Message)
+				return
+			}
+			if tc.expectedStatus == http.StatusOK {
+				var resp struct {
+					Message string          `json:"data"`
+					Todos   entity.Todolist `json:"todos"`
+				}
+				err := json.Unmarshal(w.Body.Bytes(), &resp)
+				require.NoError(t, err)
+				assert.Equal(t, tc.expectedMessage, resp.Message)
+				assert.Equal(t, "Keep me", resp.Todos.Title)
+				return
+			}
+			var errResp respErr.ErrorResponse
+			err := json.Unmarshal(w.Body.Bytes(), &errResp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMessage, errResp.Message)
+		})
+	}
+}
+
+func TestUpdateIsFullReplacement(t *testing.T) {
+	t.Run("Missing status", func(t *testing.T) {
+		repo := mocks.NewTodoRepository(t)
+		handler := NewTodoService(repo)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, "/manage-todo/todo/1", bytes.NewBufferString(`{"title": "New Title"}`))
+		r := gin.Default()
+		r.PUT("/manage-todo/todo/:id", withUser, handler.TodolistHandlerUpdate)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	})
+
+	t.Run("Omitted fields are cleared", func(t *testing.T) {
+		repo := mocks.NewTodoRepository(t)
+		handler := NewTodoService(repo)
+
+		repo.On("Update", testUserID, int64(1), repository.UpdateOptions{}, map[string]interface{}{
+			"title":       "New Title",
+			"description": nil,
+			"status":      true,
+			"due_date":    nil,
+			"priority":    entity.PriorityMedium,
+			"recurrence":  nil,
+		}).Return(&entity.Todolist{ID: 1, Title: "New Title", Status: true}, int64(1), nil)
+
+		w := httptest.NewRecorder()
+		req, _ := http.NewRequest(http.MethodPut, "/manage-todo/todo/1", bytes.NewBufferString(`{"title": "New Title", "status": true}`))
+		r := gin.Default()
+		r.PUT("/manage-todo/todo/:id", withUser, handler.TodolistHandlerUpdate)
+		r.ServeHTTP(w, req)
+
+		assert.Equal(t, http.StatusOK, w.Code)
+	})
+}
+
+func TestIfMatch(t *testing.T) {
+	testCases := []struct {
+		name           string
+		method         string
+		ifMatch        string
+		body           string
+		mock           func(repo *mocks.TodoRepository)
+		expectedStatus int
+		expectedETag   string
+	}{
+		{
+			name:   "Get returns ETag",
+			method: http.MethodGet,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("GetByID", testUserID, int64(1)).Return(&entity.Todolist{ID: 1, Title: "Tagged", Version: 4}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"4"`,
+		},
+		{
+			name:    "Patch with matching version",
+			method:  http.MethodPatch,
+			ifMatch: `"4"`,
+			body:    `{"title": "Renamed"}`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Update", testUserID, int64(1), repository.UpdateOptions{Version: 4}, mock.Anything).
+					Return(&entity.Todolist{ID: 1, Title: "Renamed", Version: 5}, int64(1), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedETag:   `"5"`,
+		},
+		{
+			name:    "Patch with stale version",
+			method:  http.MethodPatch,
+			ifMatch: `"3"`,
+			body:    `{"title": "Renamed"}`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Update", testUserID, int64(1), repository.UpdateOptions{Version: 3}, mock.Anything).
+					Return(nil, int64(0), repository.ErrVersionMismatch)
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:           "Weak tag never matches",
+			method:         http.MethodPut,
+			ifMatch:        `W/"4"`,
+			body:           `{"title": "Renamed", "status": false}`,
+			mock:           func(repo *mocks.TodoRepository) {},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:    "Delete with stale version",
+			method:  http.MethodDelete,
+			ifMatch: `"3"`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Delete", testUserID, int64(1), int64(3)).Return(int64(0), repository.ErrVersionMismatch)
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+		},
+		{
+			name:    "Wildcard deletes unconditionally",
+			method:  http.MethodDelete,
+			ifMatch: "*",
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Delete", testUserID, int64(1), int64(0)).Return(int64(1), nil)
+			},
+			expectedStatus: http.StatusOK,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTodoRepository(t)
+			tc.mock(repo)
+			handler := NewTodoService(repo)
+
+			router := gin.Default()
+			router.GET("/manage-todo/todo/:id", withUser, handler.TodolistHandlerGetByID)
+			router.PUT("/manage-todo/todo/:id", withUser, handler.TodolistHandlerUpdate)
+			router.PATCH("/manage-todo/todo/:id", withUser, handler.TodolistHandlerPatch)
+			router.DELETE("/manage-todo/todo/:id", withUser, handler.TodolistHandlerDelete)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(tc.method, "/manage-todo/todo/1", bytes.NewBufferString(tc.body))
+			if tc.ifMatch != "" {
+				r.Header.Set("If-Match", tc.ifMatch)
+			}
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedETag != "" {
+				assert.Equal(t, tc.expectedETag, w.Header().Get("ETag"))
+			}
+		})
+	}
+}
+
+func TestConditionalGet(t *testing.T) {
+	updatedAt := time.Date(2023, 5, 1, 10, 0, 0, 0, time.UTC)
+	todo := &entity.Todolist{ID: 1, Title: "Cached", Version: 2, UpdatedAt: updatedAt}
+
+	testCases := []struct {
+		name           string
+		header         string
+		value          string
+		expectedStatus int
+	}{
+		{name: "No validators", expectedStatus: http.StatusOK},
+		{name: "Matching ETag", header: "If-None-Match", value: `"2"`, expectedStatus: http.StatusNotModified},
+		{name: "Weak matching ETag", header: "If-None-Match", value: `"1", W/"2"`, expectedStatus: http.StatusNotModified},
+		{name: "Stale ETag", header: "If-None-Match", value: `"1"`, expectedStatus: http.StatusOK},
+		{name: "Not modified since", header: "If-Modified-Since", value: updatedAt.Format(http.TimeFormat), expectedStatus: http.StatusNotModified},
+		{name: "Modified since", header: "If-Modified-Since", value: updatedAt.Add(-time.Minute).Format(http.TimeFormat), expectedStatus: http.StatusOK},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTodoRepository(t)
+			repo.On("GetByID", testUserID, int64(1)).Return(todo, nil)
+			handler := NewTodoService(repo)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, "/manage-todo/todo/1", nil)
+			if tc.header != "" {
+				r.Header.Set(tc.header, tc.value)
+			}
+			router := gin.Default()
+			router.GET("/manage-todo/todo/:id", withUser, handler.TodolistHandlerGetByID)
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			assert.Equal(t, `"2"`, w.Header().Get("ETag"))
+			assert.Equal(t, updatedAt.Format(http.TimeFormat), w.Header().Get("Last-Modified"))
+		})
+	}
+
+	t.Run("List revalidation", func(t *testing.T) {
+		repo := mocks.NewTodoRepository(t)
+		repo.On("GetAll", testUserID, mock.Anything).Return([]entity.Todolist{*todo}, int64(1), nil)
+		repo.On("LastModified", testUserID).Return(updatedAt, nil)
+		handler := NewTodoService(repo)
+		router := gin.Default()
+		router.GET("/manage-todos", withUser, handler.TodolistHandlerGetAll)
+
+		first := httptest.NewRecorder()
+		r, _ := http.NewRequest(http.MethodGet, "/manage-todos", nil)
+		router.ServeHTTP(first, r)
+		require.Equal(t, http.StatusOK, first.Code)
+		etag := first.Header().Get("ETag")
+		require.NotEmpty(t, etag)
+
+		second := httptest.NewRecorder()
+		r, _ = http.NewRequest(http.MethodGet, "/manage-todos", nil)
+		r.Header.Set("If-None-Match", etag)
+		router.ServeHTTP(second, r)
+		assert.Equal(t, http.StatusNotModified, second.Code)
+		assert.Empty(t, second.Body.Bytes())
+
+		filtered := httptest.NewRecorder()
+		r, _ = http.NewRequest(http.MethodGet, "/manage-todos?status=false", nil)
+		router.ServeHTTP(filtered, r)
+		refiltered := httptest.NewRecorder()
+		r, _ = http.NewRequest(http.MethodGet, "/manage-todos?status=false", nil)
+		r.Header.Set("If-None-Match", filtered.Header().Get("ETag"))
+		router.ServeHTTP(refiltered, r)
+		assert.Equal(t, http.StatusNotModified, refiltered.Code)
+
+		otherPage := httptest.NewRecorder()
+		r, _ = http.NewRequest(http.MethodGet, "/manage-todos?offset=1", nil)
+		r.Header.Set("If-None-Match", etag)
+		router.ServeHTTP(otherPage, r)
+		assert.Equal(t, http.StatusOK, otherPage.Code)
+	})
+}
+
+func TestMove(t *testing.T) {
+	testCases := []struct {
+		name           string
+		body           string
+		ifMatch        string
+		mock           func(repo *mocks.TodoRepository)
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{
+			name: "Before another todo",
+			body: `{"before": 3}`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Move", testUserID, int64(1), int64(0), int64(3), false).
+					Return(&entity.Todolist{ID: 1, Position: 1536, Version: 2}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedMsg:    "Success Move Todo",
+		},
+		{
+			name:    "After another todo with If-Match",
+			body:    `{"after": 3}`,
+			ifMatch: `"4"`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Move", testUserID, int64(1), int64(4), int64(3), true).
+					Return(nil, repository.ErrVersionMismatch)
+			},
+			expectedStatus: http.StatusPreconditionFailed,
+			expectedMsg:    "Precondition Failed",
+		},
+		{
+			name:           "Both before and after",
+			body:           `{"before": 3, "after": 4}`,
+			mock:           func(repo *mocks.TodoRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "Bad request",
+		},
+		{
+			name:           "No anchor",
+			body:           `{}`,
+			mock:           func(repo *mocks.TodoRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "Bad request",
+		},
+		{
+			name: "Missing anchor",
+			body: `{"after": 99}`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Move", testUserID, int64(1), int64(0), int64(99), true).Return(nil, repository.ErrAnchorNotFound)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "Anchor not Found",
+		},
+		{
+			name: "Missing todo",
+			body: `{"after": 3}`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Move", testUserID, int64(1), int64(0), int64(3), true).Return(nil, nil)
+			},
+			expectedStatus: http.StatusNotFound,
+			expectedMsg:    "ID not Found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTodoRepository(t)
+			tc.mock(repo)
+			handler := NewTodoService(repo)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodPost, "/manage-todo/todo/1/move", bytes.NewBufferString(tc.body))
+			if tc.ifMatch != "" {
+				r.Header.Set("If-Match", tc.ifMatch)
+			}
+			router := gin.Default()
+			router.POST("/manage-todo/todo/:id/move", withUser, handler.TodolistHandlerMove)
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var resp map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			if tc.expectedStatus == http.StatusOK {
+				assert.Equal(t, tc.expectedMsg, resp["data"])
+			} else {
+				assert.Equal(t, tc.expectedMsg, resp["message"])
+			}
+		})
+	}
+}
+
+func TestRecurrence(t *testing.T) {
+	due := time.Date(2023, 5, 1, 9, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		method         string
+		body           string
+		mock           func(repo *mocks.TodoRepository)
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{
+			name:   "Create stores the canonical rule",
+			method: http.MethodPost,
+			body:   `{"title": "deploy checklist", "due_date": "2023-05-01T09:00:00Z", "recurrence": "rrule:freq=weekly;byday=mo"}`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Create", mock.MatchedBy(func(todo *entity.Todolist) bool {
+					return todo.Recurrence != nil && *todo.Recurrence == "FREQ=WEEKLY;BYDAY=MO" && todo.DueDate.Equal(due)
+				})).Return(&entity.Todolist{ID: 1, Title: "deploy checklist"}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedMsg:    "New Todo Created",
+		},
+		{
+			name:           "Create with an invalid rule",
+			method:         http.MethodPost,
+			body:           `{"title": "deploy checklist", "due_date": "2023-05-01T09:00:00Z", "recurrence": "FREQ=HOURLY"}`,
+			mock:           func(repo *mocks.TodoRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "Invalid input",
+		},
+		{
+			name:           "Create without a due date",
+			method:         http.MethodPost,
+			body:           `{"title": "deploy checklist", "recurrence": "weekly"}`,
+			mock:           func(repo *mocks.TodoRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "Invalid input",
+		},
+		{
+			name:   "Patch stores the canonical rule",
+			method: http.MethodPatch,
+			body:   `{"recurrence": "monthly"}`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Update", testUserID, int64(1), repository.UpdateOptions{}, map[string]interface{}{
+					"recurrence": "FREQ=MONTHLY",
+				}).Return(&entity.Todolist{ID: 1}, int64(1), nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedMsg:    "Success Patch Todo",
+		},
+		{
+			name:   "Patch clearing the due date of a recurring todo",
+			method: http.MethodPatch,
+			body:   `{"due_date": null}`,
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Update", testUserID, int64(1), repository.UpdateOptions{}, map[string]interface{}{
+					"due_date": nil,
+				}).Return(nil, int64(0), repository.ErrRecurrenceWithoutDueDate)
+			},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "Recurring todo needs a due date",
+		},
+		{
+			name:           "Patch with an invalid rule",
+			method:         http.MethodPatch,
+			body:           `{"recurrence": "FREQ=WEEKLY;BYMONTHDAY=1"}`,
+			mock:           func(repo *mocks.TodoRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "Bad request",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTodoRepository(t)
+			tc.mock(repo)
+			handler := NewTodoService(repo)
+
+			url := "/manage-todo"
+			if tc.method == http.MethodPatch {
+				url = "/manage-todo/todo/1"
+			}
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(tc.method, url, bytes.NewBufferString(tc.body))
+			router := gin.Default()
+			router.POST("/manage-todo", withUser, handler.TodolistHandlerCreate)
+			router.PATCH("/manage-todo/todo/:id", withUser, handler.TodolistHandlerPatch)
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var resp map[string]interface{}
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			if tc.method == http.MethodPatch && tc.expectedStatus == http.StatusOK {
+				assert.Equal(t, tc.expectedMsg, resp["data"])
+			} else {
+				assert.Equal(t, tc.expectedMsg, resp["message"])
+			}
+		})
+	}
+}
+
+func TestOccurrences(t *testing.T) {
+	from := time.Date(2023, 5, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name           string
+		query          string
+		mock           func(repo *mocks.TodoRepository)
+		expectedStatus int
+		expectedMsg    string
+	}{
+		{
+			name:  "Success",
+			query: "?from=2023-05-01T00:00:00Z&to=2023-05-15T00:00:00Z",
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Occurrences", testUserID, from, from.AddDate(0, 0, 14)).Return([]entity.Occurrence{
+					{TodoID: 1, Title: "deploy checklist", DueDate: from.Add(9 * time.Hour)},
+					{TodoID: 1, Title: "deploy checklist", DueDate: from.AddDate(0, 0, 7).Add(9 * time.Hour), Projected: true},
+				}, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedMsg:    "Success Get Occurrences",
+		},
+		{
+			name:  "Defaults to 30 days",
+			query: "?from=2023-05-01T00:00:00Z",
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Occurrences", testUserID, from, from.AddDate(0, 0, 30)).Return(nil, nil)
+			},
+			expectedStatus: http.StatusOK,
+			expectedMsg:    "Success Get Occurrences",
+		},
+		{
+			name:           "To before from",
+			query:          "?from=2023-05-15T00:00:00Z&to=2023-05-01T00:00:00Z",
+			mock:           func(repo *mocks.TodoRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "Invalid query",
+		},
+		{
+			name:           "Range too long",
+			query:          "?from=2023-05-01T00:00:00Z&to=2024-06-01T00:00:00Z",
+			mock:           func(repo *mocks.TodoRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "Invalid query",
+		},
+		{
+			name:           "Not a time",
+			query:          "?from=tomorrow",
+			mock:           func(repo *mocks.TodoRepository) {},
+			expectedStatus: http.StatusBadRequest,
+			expectedMsg:    "Invalid query",
+		},
+		{
+			name:  "Repository error",
+			query: "?from=2023-05-01T00:00:00Z",
+			mock: func(repo *mocks.TodoRepository) {
+				repo.On("Occurrences", testUserID, from, from.AddDate(0, 0, 30)).Return(nil, errors.New("some error"))
+			},
+			expectedStatus: http.StatusInternalServerError,
+			expectedMsg:    "Internal Server Error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTodoRepository(t)
+			tc.mock(repo)
+			handler := NewTodoService(repo)
+
+			w := httptest.NewRecorder()
+			r, _ := http.NewRequest(http.MethodGet, "/manage-todos/occurrences"+tc.query, nil)
+			router := gin.Default()
+			router.GET("/manage-todos/occurrences", withUser, handler.TodolistHandlerOccurrences)
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+			if tc.expectedStatus != http.StatusOK {
+				var resp respErr.ErrorResponse
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+				assert.Equal(t, tc.expectedMsg, resp.Message)
+				return
+			}
+			var resp request.TodoOccurrencesResponse
+			require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+			assert.Equal(t, tc.expectedMsg, resp.Message)
+			assert.Equal(t, resp.Data, len(resp.Occurrences))
+		})
+	}
+}
@@ -0,0 +1,114 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+	"todoGin/model/request"
+	"todoGin/model/respErr"
+	"todoGin/repository"
+)
+
+type TagHandler struct {
+	TagRepository repository.TagRepository
+}
+
+func NewTagService(tagRepo repository.TagRepository) *TagHandler {
+	return &TagHandler{
+		TagRepository: tagRepo,
+	}
+}
+
+// TagHandlerGetAll lists the caller's tags with their usage counts.
+func (h *TagHandler) TagHandlerGetAll(ctx *gin.Context) {
+	tags, err := h.TagRepository.GetAll(currentUserID(ctx))
+	if err != nil {
+		logrus.Errorf("failed when get tags: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Get Tags")
+	ctx.JSON(http.StatusOK, request.TagCountsResponse{
+		Message: "Success Get Tags",
+		Data:    len(tags),
+		Tags:    tags,
+	})
+}
+
+func (h *TagHandler) TagHandlerAttach(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	reqBody := new(request.TagAttachRequest)
+	if err := ctx.ShouldBindJSON(reqBody); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid input",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	names := reqBody.Names()
+	if len(names) == 0 {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid input",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	tags, err := h.TagRepository.Attach(currentUserID(ctx), todoID, names)
+	if err != nil {
+		logrus.Errorf("failed when attaching tags: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if tags == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "ID not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Attach Tags")
+	ctx.JSON(http.StatusOK, request.TagsResponse{
+		Message: "Success Attach Tags",
+		Data:    len(tags),
+		Tags:    tags,
+	})
+}
+
+func (h *TagHandler) TagHandlerDetach(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	isFound, err := h.TagRepository.Detach(currentUserID(ctx), todoID, strings.TrimSpace(ctx.Param("tag")))
+	if err != nil {
+		logrus.Errorf("failed when detaching tag: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if isFound == 0 {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Detach Tag")
+	ctx.JSON(http.StatusOK, request.TodoDeleteResponse{
+		Status:  http.StatusOK,
+		Message: "Success Detach Tag",
+	})
+}
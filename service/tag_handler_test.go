@@ -0,0 +1,120 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todoGin/mocks"
+	"todoGin/model/entity"
+	"todoGin/model/respErr"
+)
+
+func TestTagHandlers(t *testing.T) {
+	testCases := []struct {
+		name            string
+		method          string
+		url             string
+		body            string
+		mock            func(repo *mocks.TagRepository)
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:   "List with counts",
+			method: http.MethodGet,
+			url:    "/tags",
+			mock: func(repo *mocks.TagRepository) {
+				repo.On("GetAll", testUserID).Return([]entity.TagCount{
+					{Tag: entity.Tag{ID: 1, Name: "backend"}, TodoCount: 3},
+				}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Get Tags",
+		},
+		{
+			name:   "Attach trims and dedupes",
+			method: http.MethodPost,
+			url:    "/manage-todo/todo/1/tags",
+			body:   `{"tags": [" backend", "urgent", "backend ", "Urgent"]}`,
+			mock: func(repo *mocks.TagRepository) {
+				repo.On("Attach", testUserID, int64(1), []string{"backend", "urgent"}).Return([]entity.Tag{
+					{ID: 1, Name: "backend"},
+					{ID: 2, Name: "urgent"},
+				}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Attach Tags",
+		},
+		{
+			name:            "Attach blank tags",
+			method:          http.MethodPost,
+			url:             "/manage-todo/todo/1/tags",
+			body:            `{"tags": ["  "]}`,
+			mock:            func(repo *mocks.TagRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+		{
+			name:   "Attach to missing todo",
+			method: http.MethodPost,
+			url:    "/manage-todo/todo/2/tags",
+			body:   `{"tags": ["backend"]}`,
+			mock: func(repo *mocks.TagRepository) {
+				repo.On("Attach", testUserID, int64(2), []string{"backend"}).Return(nil, nil)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "ID not Found",
+		},
+		{
+			name:   "Detach",
+			method: http.MethodDelete,
+			url:    "/manage-todo/todo/1/tags/release-1.4",
+			mock: func(repo *mocks.TagRepository) {
+				repo.On("Detach", testUserID, int64(1), "release-1.4").Return(int64(1), nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Detach Tag",
+		},
+		{
+			name:   "Detach error",
+			method: http.MethodDelete,
+			url:    "/manage-todo/todo/1/tags/backend",
+			mock: func(repo *mocks.TagRepository) {
+				repo.On("Detach", testUserID, int64(1), "backend").Return(int64(0), errors.New("some error"))
+			},
+			expectedStatus:  http.StatusInternalServerError,
+			expectedMessage: "Internal Server Error",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTagRepository(t)
+			tc.mock(repo)
+			handler := NewTagService(repo)
+
+			router := gin.Default()
+			router.GET("/tags", withUser, handler.TagHandlerGetAll)
+			router.POST("/manage-todo/todo/:id/tags", withUser, handler.TagHandlerAttach)
+			router.DELETE("/manage-todo/todo/:id/tags/:tag", withUser, handler.TagHandlerDetach)
+
+			w := httptest.NewRecorder()
+			r, err := http.NewRequest(tc.method, tc.url, bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+			router.ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var resp respErr.ErrorResponse
+			err = json.Unmarshal(w.Body.Bytes(), &resp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMessage, resp.Message)
+		})
+	}
+}
@@ -1,10 +1,14 @@
 package service
 
 import (
+	"errors"
 	"github.com/gin-gonic/gin"
 	"github.com/sirupsen/logrus"
 	"net/http"
 	"strconv"
+	"time"
+	"todoGin/middleware"
+	"todoGin/model/entity"
 	"todoGin/model/request"
 	"todoGin/model/respErr"
 	"todoGin/repository"
@@ -20,28 +24,52 @@ func NewTodoService(todoRepo repository.TodoRepository) *Handler {
 	}
 }
 
+// currentUserID returns the ID stored on the context by middleware.JWTAuth.
+func currentUserID(ctx *gin.Context) int64 {
+	return ctx.GetInt64(middleware.UserIDKey)
+}
+
+// int64Param parses the named path parameter, answering 400 when it is not
+// a number.
+func int64Param(ctx *gin.Context, name string) (int64, bool) {
+	id, err := strconv.ParseInt(ctx.Param(name), 10, 64)
+	if err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "parse ID error",
+			Status:  http.StatusBadRequest,
+		})
+		return 0, false
+	}
+	return id, true
+}
+
 func (h *Handler) TodolistHandlerGetAll(ctx *gin.Context) {
-	//authHeader := ctx.GetHeader("Authorization")
-	//if authHeader == "" {
-	//	ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-	//	ctx.Abort()
-	//	return
-	//}
-	//
-	//tokenString := authHeader[7:]
-	//token, err := jwt.Parse(tokenString, func(token *jwt.Token) (interface{}, error) {
-	//	if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
-	//		return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
-	//	}
-	//	return []byte("secret"), nil
-	//})
-	//if err != nil || !token.Valid {
-	//	ctx.JSON(http.StatusUnauthorized, gin.H{"error": "Unauthorized"})
-	//	ctx.Abort()
-	//	return
-	//}
-
-	todos, err := h.TodoRepository.GetAll()
+	listTodos(ctx, h.TodoRepository, false, nil, "Success Get All")
+}
+
+func (h *Handler) TodolistHandlerGetTrash(ctx *gin.Context) {
+	listTodos(ctx, h.TodoRepository, true, nil, "Success Get Trash")
+}
+
+// listTodos serves a page of the caller's live or soft-deleted todos, or 304
+// when the client's cached page is still current. A non-nil listID limits the
+// page to that list.
+func listTodos(ctx *gin.Context, todoRepo repository.TodoRepository, trashed bool, listID *int64, message string) {
+	queryReq := new(request.TodolistQueryRequest)
+	if err := ctx.ShouldBindQuery(queryReq); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid query",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	query := queryReq.Query()
+	query.Trashed = trashed
+	query.ListID = listID
+
+	todos, total, err := todoRepo.GetAll(currentUserID(ctx), query)
 	if err != nil {
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, &respErr.ErrorResponse{
 			Message: err.Error(),
@@ -49,15 +77,40 @@ func (h *Handler) TodolistHandlerGetAll(ctx *gin.Context) {
 		})
 		return
 	}
-	logrus.Info(http.StatusOK, " Success Get All Data")
+	lastModified, err := todoRepo.LastModified(currentUserID(ctx))
+	if err != nil {
+		logrus.Errorf("failed when reading last modified: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if notModified(ctx, listETag(query, total, todos, lastModified), lastModified) {
+		return
+	}
+	logrus.Info(http.StatusOK, " ", message)
 	//ctx.AbortWithStatusJSON(http.StatusOK, todos)
+	var nextOffset *int
+	if next := query.Offset + len(todos); int64(next) < total {
+		nextOffset = &next
+	}
 	ctx.AbortWithStatusJSON(http.StatusOK, request.TodoResponseToGetAll{
-		Message: "Success Get All",
-		Data:    len(todos),
-		Todos:   todos,
+		Message:    message,
+		Data:       len(todos),
+		Total:      total,
+		Limit:      query.Limit,
+		Offset:     query.Offset,
+		NextOffset: nextOffset,
+		Todos:      todos,
 	})
 }
 func (h *Handler) TodolistHandlerCreate(ctx *gin.Context) {
+	createTodo(ctx, h.TodoRepository, nil)
+}
+
+// createTodo creates a todo from the request body, inside listID when set.
+func createTodo(ctx *gin.Context, todoRepo repository.TodoRepository, listID *int64) {
 	todolist := new(request.TodolistCreateRequest)
 	err := ctx.ShouldBindJSON(todolist)
 	if err != nil {
@@ -68,7 +121,9 @@ func (h *Handler) TodolistHandlerCreate(ctx *gin.Context) {
 		})
 		return
 	}
-	newTodo, errCreate := h.TodoRepository.Create(todolist.Title)
+	todo := todolist.ToTodo(currentUserID(ctx))
+	todo.ListID = listID
+	newTodo, errCreate := todoRepo.Create(todo)
 	if errCreate != nil {
 		logrus.Error(errCreate)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
@@ -86,17 +141,11 @@ func (h *Handler) TodolistHandlerCreate(ctx *gin.Context) {
 	})
 }
 func (h *Handler) TodolistHandlerGetByID(ctx *gin.Context) {
-	userId := ctx.Param("id")
-	todoID, err := strconv.ParseInt(userId, 10, 64)
-	if err != nil {
-		logrus.Error(err)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
-			Message: "Bad request",
-			Status:  http.StatusBadRequest,
-		})
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
 		return
 	}
-	todo, err := h.TodoRepository.GetByID(todoID)
+	todo, err := h.TodoRepository.GetByID(currentUserID(ctx), todoID)
 	if err != nil {
 		logrus.Errorf("failed when get todo by id: %v", err)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
@@ -112,27 +161,43 @@ func (h *Handler) TodolistHandlerGetByID(ctx *gin.Context) {
 		})
 		return
 	}
+	if notModified(ctx, todoETag(todo), todo.UpdatedAt) {
+		return
+	}
 	logrus.Info(http.StatusOK, " Success Get By ID")
+	progress := request.ProgressOf(todo.Subtasks)
 	ctx.JSON(http.StatusOK, request.TodoResponse{
-		Status:  http.StatusOK,
-		Message: "Success Get Id",
-		Data:    *todo,
+		Status:   http.StatusOK,
+		Message:  "Success Get Id",
+		Data:     *todo,
+		Progress: &progress,
 	})
 }
 
 func (h *Handler) TodolistHandlerUpdate(ctx *gin.Context) {
-	userId := ctx.Param("id")
-	todoID, err := strconv.ParseInt(userId, 10, 64)
-	if err != nil {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	reqBody := new(request.TodolistUpdateRequest)
+	if err := ctx.ShouldBindJSON(reqBody); err != nil {
 		logrus.Error(err)
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
-			Message: "parse ID error",
+			Message: "Bad request",
 			Status:  http.StatusBadRequest,
 		})
 		return
 	}
-	reqBody := new(request.TodolistUpdateRequest)
-	if err := ctx.ShouldBindJSON(reqBody); err != nil {
+	h.updateTodo(ctx, todoID, reqBody.ReqTodo(), "Success Update Todo")
+}
+
+func (h *Handler) TodolistHandlerPatch(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	patch := new(request.TodolistPatchRequest)
+	if err := ctx.ShouldBindJSON(patch); err != nil {
 		logrus.Error(err)
 		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
 			Message: "Bad request",
@@ -140,23 +205,47 @@ func (h *Handler) TodolistHandlerUpdate(ctx *gin.Context) {
 		})
 		return
 	}
-	ErrId, err := h.TodoRepository.GetByID(todoID)
+	updates, err := patch.ReqTodo()
 	if err != nil {
-		logrus.Errorf("failed when get todo by id: %v", err)
-		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
-			Message: "Internal Server Error",
-			Status:  http.StatusInternalServerError,
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Bad request",
+			Status:  http.StatusBadRequest,
 		})
 		return
 	}
-	if ErrId == nil {
-		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
-			Message: "ID not Found",
-			Status:  http.StatusNotFound,
+	h.updateTodo(ctx, todoID, updates, "Success Patch Todo")
+}
+
+// updateTodo writes updates and answers with the todo as persisted, telling
+// "not found", "no change" and "updated" apart. An If-Match header makes the
+// write conditional on the todo's current version, and ?cascade=true
+// completes the subtasks along with the todo.
+func (h *Handler) updateTodo(ctx *gin.Context, todoID int64, updates map[string]interface{}, message string) {
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+	cascade, _ := strconv.ParseBool(ctx.Query("cascade"))
+	opts := repository.UpdateOptions{Version: version, CascadeSubtasks: cascade}
+	todo, rowsAffected, err := h.TodoRepository.Update(currentUserID(ctx), todoID, opts, updates)
+	respondUpdate(ctx, todo, rowsAffected, err, message)
+}
+
+// respondUpdate answers with the outcome of TodoRepository.Update or of a
+// write going through it.
+func respondUpdate(ctx *gin.Context, todo *entity.Todolist, rowsAffected int64, err error, message string) {
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		preconditionFailed(ctx)
+		return
+	}
+	if errors.Is(err, repository.ErrRecurrenceWithoutDueDate) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Recurring todo needs a due date",
+			Status:  http.StatusBadRequest,
 		})
 		return
 	}
-	rowsAffected, err := h.TodoRepository.Update(todoID, reqBody.ReqTodo())
 	if err != nil {
 		logrus.Errorf("failed when updating todo: %v", err)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
@@ -165,34 +254,44 @@ func (h *Handler) TodolistHandlerUpdate(ctx *gin.Context) {
 		})
 		return
 	}
-	if rowsAffected == nil {
+	if todo == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "ID not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	ctx.Header("ETag", todoETag(todo))
+	if rowsAffected == 0 {
 		ctx.AbortWithStatusJSON(http.StatusOK, request.TodoIDResponse{
 			Message: "Not Change",
-			Data:    reqBody,
+			Data:    todo,
 		})
 		return
 	}
 
-	logrus.Info(http.StatusOK, " Success Update Todo")
+	logrus.Info(http.StatusOK, " ", message)
 	ctx.JSON(http.StatusOK, request.TodoUpdateResponse{
 		Status:  http.StatusOK,
-		Message: "Success Update Todo",
-		Todos:   reqBody,
+		Message: message,
+		Todos:   todo,
 	})
-
 }
+
 func (h *Handler) TodolistHandlerDelete(ctx *gin.Context) {
-	userId := ctx.Param("id")
-	todoID, err := strconv.ParseInt(userId, 10, 64)
-	if err != nil {
-		logrus.Error(err)
-		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
-			Message: "Parse ID Error",
-			Status:  http.StatusBadRequest,
-		})
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+	isFound, err := h.TodoRepository.Delete(currentUserID(ctx), todoID, version)
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		preconditionFailed(ctx)
 		return
 	}
-	isFound, err := h.TodoRepository.Delete(todoID)
 	if err != nil {
 		logrus.Errorf("failed when deleting todo: %v", err)
 		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
@@ -215,3 +314,203 @@ func (h *Handler) TodolistHandlerDelete(ctx *gin.Context) {
 		Message: "Success Delete",
 	})
 }
+
+func (h *Handler) TodolistHandlerSearch(ctx *gin.Context) {
+	searchReq := new(request.TodolistSearchRequest)
+	if err := ctx.ShouldBindQuery(searchReq); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid query",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	limit := searchReq.Limit
+	if limit == 0 {
+		limit = request.DefaultPageLimit
+	}
+
+	results, err := h.TodoRepository.Search(currentUserID(ctx), searchReq.Q, limit)
+	if err != nil {
+		logrus.Errorf("failed when searching todos: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	terms := searchTerms(searchReq.Q)
+	items := make([]request.TodoSearchItem, 0, len(results))
+	for _, result := range results {
+		highlights := make(map[string]string)
+		if snippet := highlight(result.Title, terms); snippet != "" {
+			highlights["title"] = snippet
+		}
+		if result.Description != nil {
+			if snippet := highlight(*result.Description, terms); snippet != "" {
+				highlights["description"] = snippet
+			}
+		}
+		items = append(items, request.TodoSearchItem{
+			Todolist:   result.Todolist,
+			Relevance:  result.Relevance,
+			Highlights: highlights,
+		})
+	}
+
+	logrus.Info(http.StatusOK, " Success Search")
+	ctx.JSON(http.StatusOK, request.TodoSearchResponse{
+		Message: "Success Search",
+		Data:    len(items),
+		Todos:   items,
+	})
+}
+
+func (h *Handler) TodolistHandlerRestore(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	restored, err := h.TodoRepository.Restore(currentUserID(ctx), todoID)
+	if err != nil {
+		logrus.Errorf("failed when restoring todo: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if restored == 0 {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	todo, err := h.TodoRepository.GetByID(currentUserID(ctx), todoID)
+	if err != nil || todo == nil {
+		logrus.Errorf("failed when get restored todo: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Restore")
+	progress := request.ProgressOf(todo.Subtasks)
+	ctx.JSON(http.StatusOK, request.TodoResponse{
+		Status:   http.StatusOK,
+		Message:  "Success Restore",
+		Data:     *todo,
+		Progress: &progress,
+	})
+}
+
+// TodolistHandlerMove places a todo before or after another one in the
+// caller's manual order. It honours If-Match like PUT.
+func (h *Handler) TodolistHandlerMove(ctx *gin.Context) {
+	todoID, ok := int64Param(ctx, "id")
+	if !ok {
+		return
+	}
+	reqBody := new(request.TodolistMoveRequest)
+	if err := ctx.ShouldBindJSON(reqBody); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Bad request",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	anchorID, after, err := reqBody.Anchor()
+	if err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Bad request",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	version, ok := ifMatchVersion(ctx)
+	if !ok {
+		return
+	}
+
+	todo, err := h.TodoRepository.Move(currentUserID(ctx), todoID, version, anchorID, after)
+	if errors.Is(err, repository.ErrVersionMismatch) {
+		preconditionFailed(ctx)
+		return
+	}
+	if errors.Is(err, repository.ErrAnchorNotFound) {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Anchor not Found",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	if err != nil {
+		logrus.Errorf("failed when moving todo: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if todo == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "ID not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+
+	logrus.Info(http.StatusOK, " Success Move Todo")
+	ctx.Header("ETag", todoETag(todo))
+	ctx.JSON(http.StatusOK, request.TodoUpdateResponse{
+		Status:  http.StatusOK,
+		Message: "Success Move Todo",
+		Todos:   todo,
+	})
+}
+
+// TodolistHandlerOccurrences lists the due dates of open todos in a window,
+// with recurring todos expanded into their upcoming repetitions.
+func (h *Handler) TodolistHandlerOccurrences(ctx *gin.Context) {
+	reqQuery := new(request.TodolistOccurrencesRequest)
+	if err := ctx.ShouldBindQuery(reqQuery); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid query",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	from, to, err := reqQuery.Range(time.Now())
+	if err != nil {
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid query",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+
+	occurrences, err := h.TodoRepository.Occurrences(currentUserID(ctx), from, to)
+	if err != nil {
+		logrus.Errorf("failed when expanding occurrences: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+
+	logrus.Info(http.StatusOK, " Success Get Occurrences")
+	ctx.JSON(http.StatusOK, request.TodoOccurrencesResponse{
+		Message:     "Success Get Occurrences",
+		Data:        len(occurrences),
+		From:        from,
+		To:          to,
+		Occurrences: occurrences,
+	})
+}
@@ -0,0 +1,255 @@
+package service
+
+import (
+	"github.com/gin-gonic/gin"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"todoGin/model/entity"
+	"todoGin/model/request"
+	"todoGin/model/respErr"
+	"todoGin/repository"
+	"todoGin/webhook"
+)
+
+type WebhookHandler struct {
+	WebhookRepository repository.WebhookRepository
+}
+
+func NewWebhookService(webhookRepo repository.WebhookRepository) *WebhookHandler {
+	return &WebhookHandler{
+		WebhookRepository: webhookRepo,
+	}
+}
+
+func (h *WebhookHandler) WebhookHandlerGetAll(ctx *gin.Context) {
+	webhooks, err := h.WebhookRepository.GetAll(currentUserID(ctx))
+	if err != nil {
+		logrus.Errorf("failed when get webhooks: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Get Webhooks")
+	ctx.JSON(http.StatusOK, request.WebhooksResponse{
+		Message:  "Success Get Webhooks",
+		Data:     len(webhooks),
+		Webhooks: webhooks,
+	})
+}
+
+// WebhookHandlerCreate registers a webhook with a fresh signing secret, which
+// is returned this once only.
+func (h *WebhookHandler) WebhookHandlerCreate(ctx *gin.Context) {
+	reqBody := new(request.WebhookCreateRequest)
+	if err := ctx.ShouldBindJSON(reqBody); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid input",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	secret, err := webhook.NewSecret()
+	if err != nil {
+		logrus.Errorf("failed when generating webhook secret: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	created, err := h.WebhookRepository.Create(reqBody.ToWebhook(currentUserID(ctx), secret))
+	if err != nil {
+		logrus.Errorf("failed when creating webhook: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	logrus.Info(http.StatusCreated, " Success Create Webhook")
+	ctx.JSON(http.StatusCreated, request.WebhookCreatedResponse{
+		Status:  http.StatusCreated,
+		Message: "New Webhook Created",
+		Data:    *created,
+		Secret:  secret,
+	})
+}
+
+func (h *WebhookHandler) WebhookHandlerGetByID(ctx *gin.Context) {
+	found, ok := h.findWebhook(ctx)
+	if !ok {
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Get Webhook")
+	ctx.JSON(http.StatusOK, request.WebhookResponse{
+		Status:  http.StatusOK,
+		Message: "Success Get Webhook",
+		Data:    *found,
+	})
+}
+
+func (h *WebhookHandler) WebhookHandlerUpdate(ctx *gin.Context) {
+	webhookID, ok := int64Param(ctx, "webhookID")
+	if !ok {
+		return
+	}
+	reqBody := new(request.WebhookUpdateRequest)
+	if err := ctx.ShouldBindJSON(reqBody); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Bad request",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	updated, err := h.WebhookRepository.Update(currentUserID(ctx), webhookID, reqBody.ReqWebhook())
+	if err != nil {
+		logrus.Errorf("failed when updating webhook: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if updated == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Webhook not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Update Webhook")
+	ctx.JSON(http.StatusOK, request.WebhookResponse{
+		Status:  http.StatusOK,
+		Message: "Success Update Webhook",
+		Data:    *updated,
+	})
+}
+
+func (h *WebhookHandler) WebhookHandlerDelete(ctx *gin.Context) {
+	webhookID, ok := int64Param(ctx, "webhookID")
+	if !ok {
+		return
+	}
+	isFound, err := h.WebhookRepository.Delete(currentUserID(ctx), webhookID)
+	if err != nil {
+		logrus.Errorf("failed when deleting webhook: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if isFound == 0 {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Webhook not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Delete Webhook")
+	ctx.JSON(http.StatusOK, request.TodoDeleteResponse{
+		Status:  http.StatusOK,
+		Message: "Success Delete Webhook",
+	})
+}
+
+// WebhookHandlerDeliveries lists the latest deliveries of a webhook, newest
+// first.
+func (h *WebhookHandler) WebhookHandlerDeliveries(ctx *gin.Context) {
+	found, ok := h.findWebhook(ctx)
+	if !ok {
+		return
+	}
+	queryReq := new(request.WebhookDeliveriesRequest)
+	if err := ctx.ShouldBindQuery(queryReq); err != nil {
+		logrus.Error(err)
+		ctx.AbortWithStatusJSON(http.StatusBadRequest, respErr.ErrorResponse{
+			Message: "Invalid query",
+			Status:  http.StatusBadRequest,
+		})
+		return
+	}
+	limit := queryReq.Limit
+	if limit == 0 {
+		limit = request.DefaultPageLimit
+	}
+	deliveries, err := h.WebhookRepository.Deliveries(currentUserID(ctx), found.ID, limit)
+	if err != nil {
+		logrus.Errorf("failed when get webhook deliveries: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	logrus.Info(http.StatusOK, " Success Get Deliveries")
+	ctx.JSON(http.StatusOK, request.WebhookDeliveriesResponse{
+		Message:    "Success Get Deliveries",
+		Data:       len(deliveries),
+		Deliveries: deliveries,
+	})
+}
+
+// WebhookHandlerReplay queues an earlier delivery to be sent again.
+func (h *WebhookHandler) WebhookHandlerReplay(ctx *gin.Context) {
+	webhookID, ok := int64Param(ctx, "webhookID")
+	if !ok {
+		return
+	}
+	deliveryID, ok := int64Param(ctx, "deliveryID")
+	if !ok {
+		return
+	}
+	replay, err := h.WebhookRepository.Replay(currentUserID(ctx), webhookID, deliveryID)
+	if err != nil {
+		logrus.Errorf("failed when replaying webhook delivery: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return
+	}
+	if replay == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Delivery not Found",
+			Status:  http.StatusNotFound,
+		})
+		return
+	}
+	logrus.Info(http.StatusAccepted, " Success Replay Delivery")
+	ctx.JSON(http.StatusAccepted, request.WebhookDeliveryResponse{
+		Status:  http.StatusAccepted,
+		Message: "Delivery Queued",
+		Data:    *replay,
+	})
+}
+
+// findWebhook loads the caller's webhook named by the :webhookID parameter,
+// answering 400 or 404 itself when it cannot.
+func (h *WebhookHandler) findWebhook(ctx *gin.Context) (*entity.Webhook, bool) {
+	webhookID, ok := int64Param(ctx, "webhookID")
+	if !ok {
+		return nil, false
+	}
+	found, err := h.WebhookRepository.GetByID(currentUserID(ctx), webhookID)
+	if err != nil {
+		logrus.Errorf("failed when get webhook by id: %v", err)
+		ctx.AbortWithStatusJSON(http.StatusInternalServerError, respErr.ErrorResponse{
+			Message: "Internal Server Error",
+			Status:  http.StatusInternalServerError,
+		})
+		return nil, false
+	}
+	if found == nil {
+		ctx.AbortWithStatusJSON(http.StatusNotFound, respErr.ErrorResponse{
+			Message: "Webhook not Found",
+			Status:  http.StatusNotFound,
+		})
+		return nil, false
+	}
+	return found, true
+}
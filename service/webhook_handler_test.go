@@ -0,0 +1,204 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"todoGin/mocks"
+	"todoGin/model/entity"
+	"todoGin/model/request"
+	"todoGin/model/respErr"
+)
+
+func setupWebhookRouter(repo *mocks.WebhookRepository) *gin.Engine {
+	handler := NewWebhookService(repo)
+	router := gin.Default()
+	router.GET("/webhooks", withUser, handler.WebhookHandlerGetAll)
+	router.POST("/webhooks", withUser, handler.WebhookHandlerCreate)
+	router.GET("/webhooks/:webhookID", withUser, handler.WebhookHandlerGetByID)
+	router.PATCH("/webhooks/:webhookID", withUser, handler.WebhookHandlerUpdate)
+	router.DELETE("/webhooks/:webhookID", withUser, handler.WebhookHandlerDelete)
+	router.GET("/webhooks/:webhookID/deliveries", withUser, handler.WebhookHandlerDeliveries)
+	router.POST("/webhooks/:webhookID/deliveries/:deliveryID/replay", withUser, handler.WebhookHandlerReplay)
+	return router
+}
+
+func TestWebhookHandlers(t *testing.T) {
+	hook := &entity.Webhook{ID: 3, UserID: testUserID, URL: "https://ci.example.com/hook", Active: true}
+
+	testCases := []struct {
+		name            string
+		method          string
+		url             string
+		body            string
+		mock            func(repo *mocks.WebhookRepository)
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:   "Create",
+			method: http.MethodPost,
+			url:    "/webhooks",
+			body:   `{"url": "https://ci.example.com/hook", "events": ["todo.completed", "todo.completed"]}`,
+			mock: func(repo *mocks.WebhookRepository) {
+				repo.On("Create", mock.MatchedBy(func(w *entity.Webhook) bool {
+					return w.UserID == testUserID && w.URL == "https://ci.example.com/hook" &&
+						len(w.Secret) == 64 && assert.ObjectsAreEqual([]string{entity.EventTodoCompleted}, w.Events)
+				})).Return(hook, nil)
+			},
+			expectedStatus:  http.StatusCreated,
+			expectedMessage: "New Webhook Created",
+		},
+		{
+			name:            "Create with a non-HTTP URL",
+			method:          http.MethodPost,
+			url:             "/webhooks",
+			body:            `{"url": "ftp://ci.example.com/hook"}`,
+			mock:            func(repo *mocks.WebhookRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+		{
+			name:            "Create with a private address",
+			method:          http.MethodPost,
+			url:             "/webhooks",
+			body:            `{"url": "http://169.254.169.254/latest/meta-data"}`,
+			mock:            func(repo *mocks.WebhookRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+		{
+			name:            "Create with an unknown event",
+			method:          http.MethodPost,
+			url:             "/webhooks",
+			body:            `{"url": "https://ci.example.com/hook", "events": ["todo.exploded"]}`,
+			mock:            func(repo *mocks.WebhookRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+		{
+			name:   "Get all",
+			method: http.MethodGet,
+			url:    "/webhooks",
+			mock: func(repo *mocks.WebhookRepository) {
+				repo.On("GetAll", testUserID).Return([]entity.Webhook{*hook}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Get Webhooks",
+		},
+		{
+			name:   "Deactivate and subscribe to everything",
+			method: http.MethodPatch,
+			url:    "/webhooks/3",
+			body:   `{"active": false, "events": []}`,
+			mock: func(repo *mocks.WebhookRepository) {
+				repo.On("Update", testUserID, int64(3), map[string]interface{}{"active": false, "events": []string{}}).Return(hook, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Update Webhook",
+		},
+		{
+			name:   "Update missing webhook",
+			method: http.MethodPatch,
+			url:    "/webhooks/9",
+			body:   `{"active": false}`,
+			mock: func(repo *mocks.WebhookRepository) {
+				repo.On("Update", testUserID, int64(9), map[string]interface{}{"active": false}).Return(nil, nil)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "Webhook not Found",
+		},
+		{
+			name:   "Delete",
+			method: http.MethodDelete,
+			url:    "/webhooks/3",
+			mock: func(repo *mocks.WebhookRepository) {
+				repo.On("Delete", testUserID, int64(3)).Return(int64(1), nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Delete Webhook",
+		},
+		{
+			name:   "Deliveries",
+			method: http.MethodGet,
+			url:    "/webhooks/3/deliveries?limit=5",
+			mock: func(repo *mocks.WebhookRepository) {
+				repo.On("GetByID", testUserID, int64(3)).Return(hook, nil)
+				repo.On("Deliveries", testUserID, int64(3), 5).Return([]entity.WebhookDelivery{{ID: 1, WebhookID: 3}}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Get Deliveries",
+		},
+		{
+			name:   "Deliveries of missing webhook",
+			method: http.MethodGet,
+			url:    "/webhooks/9/deliveries",
+			mock: func(repo *mocks.WebhookRepository) {
+				repo.On("GetByID", testUserID, int64(9)).Return(nil, nil)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "Webhook not Found",
+		},
+		{
+			name:   "Replay",
+			method: http.MethodPost,
+			url:    "/webhooks/3/deliveries/1/replay",
+			mock: func(repo *mocks.WebhookRepository) {
+				repo.On("Replay", testUserID, int64(3), int64(1)).Return(&entity.WebhookDelivery{ID: 2, WebhookID: 3, Status: entity.DeliveryPending}, nil)
+			},
+			expectedStatus:  http.StatusAccepted,
+			expectedMessage: "Delivery Queued",
+		},
+		{
+			name:   "Replay missing delivery",
+			method: http.MethodPost,
+			url:    "/webhooks/3/deliveries/99/replay",
+			mock: func(repo *mocks.WebhookRepository) {
+				repo.On("Replay", testUserID, int64(3), int64(99)).Return(nil, nil)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "Delivery not Found",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewWebhookRepository(t)
+			tc.mock(repo)
+
+			w := httptest.NewRecorder()
+			r, err := http.NewRequest(tc.method, tc.url, bytes.NewBufferString(tc.body))
+			require.NoError(t, err)
+			setupWebhookRouter(repo).ServeHTTP(w, r)
+
+			assert.Equal(t, tc.expectedStatus, w.Code)
+
+			var resp respErr.ErrorResponse
+			err = json.Unmarshal(w.Body.Bytes(), &resp)
+			require.NoError(t, err)
+			assert.Equal(t, tc.expectedMessage, resp.Message)
+		})
+	}
+}
+
+func TestWebhookSecretShownOnce(t *testing.T) {
+	repo := mocks.NewWebhookRepository(t)
+	repo.On("Create", mock.AnythingOfType("*entity.Webhook")).
+		Return(func(w *entity.Webhook) *entity.Webhook { return w }, nil)
+
+	w := httptest.NewRecorder()
+	r, _ := http.NewRequest(http.MethodPost, "/webhooks", bytes.NewBufferString(`{"url": "http://hooks.example.com:9000/hook"}`))
+	setupWebhookRouter(repo).ServeHTTP(w, r)
+	require.Equal(t, http.StatusCreated, w.Code)
+
+	var resp request.WebhookCreatedResponse
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	assert.Len(t, resp.Secret, 64)
+	assert.Empty(t, resp.Data.Secret)
+}
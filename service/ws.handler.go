@@ -0,0 +1,209 @@
+package service
+
+import (
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/gin-gonic/gin/binding"
+	"github.com/gorilla/websocket"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"strings"
+	"time"
+	"todoGin/model/request"
+	"todoGin/repository"
+	"todoGin/stream"
+)
+
+// WSProtocol is the subprotocol of the collaboration channel. Browsers send
+// it next to the "bearer.<token>" one, and the server picks it.
+const WSProtocol = "todos.v1"
+
+// Timing of the collaboration connections: a client that answers no ping
+// within wsPongWait is dropped.
+const (
+	wsWriteWait    = 10 * time.Second
+	wsPongWait     = 60 * time.Second
+	wsPingInterval = wsPongWait * 9 / 10
+	wsMaxMessage   = 64 << 10
+)
+
+// wsReplyType is the Type of a request.WSReply.
+const wsReplyType = "result"
+
+type WSHandler struct {
+	TodoRepository repository.TodoRepository
+	ListRepository repository.ListRepository
+	Hub            *stream.Hub
+	Upgrader       websocket.Upgrader
+}
+
+// NewWSService builds the collaboration handler. Browsers may connect from
+// allowedOrigins; with none, only from the API's own origin.
+func NewWSService(todoRepo repository.TodoRepository, listRepo repository.ListRepository, hub *stream.Hub, allowedOrigins []string) *WSHandler {
+	upgrader := websocket.Upgrader{Subprotocols: []string{WSProtocol}}
+	if len(allowedOrigins) > 0 {
+		upgrader.CheckOrigin = func(r *http.Request) bool {
+			origin := r.Header.Get("Origin")
+			for _, allowed := range allowedOrigins {
+				if strings.EqualFold(origin, allowed) {
+					return true
+				}
+			}
+			return false
+		}
+	}
+	return &WSHandler{
+		TodoRepository: todoRepo,
+		ListRepository: listRepo,
+		Hub:            hub,
+		Upgrader:       upgrader,
+	}
+}
+
+// WSHandlerConnect upgrades to the collaboration WebSocket. The client sends
+// request.WSCommand messages and gets a request.WSReply for each; the todo
+// events of the lists it subscribed to arrive as stream.Message, except for
+// the changes it made itself.
+func (h *WSHandler) WSHandlerConnect(ctx *gin.Context) {
+	client := h.Hub.Register(currentUserID(ctx))
+	if client == nil {
+		ctx.AbortWithStatus(http.StatusServiceUnavailable)
+		return
+	}
+	defer client.Close()
+
+	// the token subprotocol is never echoed back, only WSProtocol
+	conn, err := h.Upgrader.Upgrade(ctx.Writer, ctx.Request, nil)
+	if err != nil {
+		logrus.Errorf("failed when upgrading to websocket: %v", err)
+		return
+	}
+	logrus.Info(http.StatusSwitchingProtocols, " Success Open WebSocket")
+
+	replies := make(chan []byte, 1)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		h.writeLoop(conn, client, replies)
+	}()
+
+	conn.SetReadLimit(wsMaxMessage)
+	conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	conn.SetPongHandler(func(string) error {
+		return conn.SetReadDeadline(time.Now().Add(wsPongWait))
+	})
+	for {
+		_, data, err := conn.ReadMessage()
+		if err != nil {
+			break
+		}
+		reply, err := json.Marshal(h.handleCommand(ctx, client, data))
+		if err != nil {
+			logrus.Errorf("failed when encoding websocket reply: %v", err)
+			break
+		}
+		select {
+		case replies <- reply:
+		case <-done:
+		}
+	}
+	close(replies)
+	<-done
+	conn.Close()
+}
+
+// writeLoop is the connection's only writer. It sends the replies and the
+// client's events, and pings, until either runs out.
+func (h *WSHandler) writeLoop(conn *websocket.Conn, client *stream.Client, replies <-chan []byte) {
+	ping := time.NewTicker(wsPingInterval)
+	defer ping.Stop()
+	for {
+		var message []byte
+		var ok bool
+		select {
+		case message, ok = <-replies:
+		case message, ok = <-client.Send:
+		case <-ping.C:
+			conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+			if err := conn.WriteMessage(websocket.PingMessage, nil); err != nil {
+				return
+			}
+			continue
+		}
+		conn.SetWriteDeadline(time.Now().Add(wsWriteWait))
+		if !ok {
+			// unblocks the read loop when the hub dropped the client
+			conn.WriteMessage(websocket.CloseMessage, websocket.FormatCloseMessage(websocket.CloseGoingAway, ""))
+			conn.Close()
+			return
+		}
+		if err := conn.WriteMessage(websocket.TextMessage, message); err != nil {
+			conn.Close()
+			return
+		}
+	}
+}
+
+// handleCommand runs one command and builds its reply. Writes go through
+// TodoRepository.Bulk as a single operation, so they are validated and
+// answered exactly like their bulk and single-item counterparts.
+func (h *WSHandler) handleCommand(ctx *gin.Context, client *stream.Client, data []byte) request.WSReply {
+	command := new(request.WSCommand)
+	if err := binding.JSON.BindBody(data, command); err != nil {
+		logrus.Error(err)
+		return request.WSReply{Type: wsReplyType, ID: command.ID, Status: http.StatusBadRequest, Message: "Invalid input"}
+	}
+	reply := request.WSReply{Type: wsReplyType, ID: command.ID}
+	userID := currentUserID(ctx)
+
+	if command.Type != request.WSUnsubscribe && command.ListID != nil {
+		list, err := h.ListRepository.GetByID(userID, *command.ListID)
+		switch {
+		case err != nil:
+			logrus.Errorf("failed when get list by id: %v", err)
+			reply.Status, reply.Message = http.StatusInternalServerError, "Internal Server Error"
+			return reply
+		case list == nil:
+			reply.Status, reply.Message = http.StatusNotFound, "List not Found"
+			return reply
+		case list.Archived && command.Type == request.WSCreate:
+			reply.Status, reply.Message = http.StatusConflict, "List is archived"
+			return reply
+		}
+	}
+
+	switch command.Type {
+	case request.WSSubscribe:
+		client.Subscribe(command.ListID)
+		reply.Status, reply.Message = http.StatusOK, "Subscribed"
+		return reply
+	case request.WSUnsubscribe:
+		client.Unsubscribe(command.ListID)
+		reply.Status, reply.Message = http.StatusOK, "Unsubscribed"
+		return reply
+	}
+
+	op, err := command.ReqBulk(userID)
+	if err != nil {
+		logrus.Error(err)
+		reply.Status, reply.Message = http.StatusBadRequest, "Invalid input"
+		return reply
+	}
+	// the change's events wait until the client knows which to ignore
+	client.Hold()
+	defer client.Release()
+	results, err := h.TodoRepository.Bulk(userID, []repository.BulkOperation{op}, true)
+	if err != nil {
+		logrus.Errorf("failed when running websocket %s: %v", command.Type, err)
+		reply.Status, reply.Message = http.StatusInternalServerError, "Internal Server Error"
+		return reply
+	}
+	result := request.TodoBulkResult{Op: op.Op}
+	setBulkResult(&result, results[0])
+	reply.Status, reply.Message, reply.Data = result.Status, result.Message, result.Data
+	if todo := result.Data; todo != nil && result.Status < http.StatusBadRequest {
+		client.Ignore(todo.ID, todo.Version)
+		logrus.Info(result.Status, " ", result.Message)
+	}
+	return reply
+}
@@ -0,0 +1,202 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"github.com/gin-gonic/gin"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+	"todoGin/middleware"
+	"todoGin/mocks"
+	"todoGin/model/entity"
+	"todoGin/model/request"
+	"todoGin/repository"
+	"todoGin/stream"
+)
+
+func setupWSServer(t *testing.T, todoRepo *mocks.TodoRepository, listRepo *mocks.ListRepository, hub *stream.Hub) *httptest.Server {
+	handler := NewWSService(todoRepo, listRepo, hub, nil)
+	router := gin.Default()
+	router.GET("/ws", middleware.JWTAuth("secret"), handler.WSHandlerConnect)
+	server := httptest.NewServer(router)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// dialWS connects as testUserID, passing the token the way browsers do.
+func dialWS(t *testing.T, server *httptest.Server) *websocket.Conn {
+	token, _, err := middleware.GenerateToken(&entity.User{ID: testUserID}, "secret", time.Hour)
+	require.NoError(t, err)
+	dialer := websocket.Dialer{Subprotocols: []string{WSProtocol, middleware.WebSocketTokenPrefix + token}}
+	conn, resp, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	require.NoError(t, err)
+	assert.Equal(t, WSProtocol, resp.Header.Get("Sec-WebSocket-Protocol"))
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// roundTrip sends a command and reads the reply.
+func roundTrip(t *testing.T, conn *websocket.Conn, command string) request.WSReply {
+	require.NoError(t, conn.WriteMessage(websocket.TextMessage, []byte(command)))
+	var reply request.WSReply
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(time.Second)))
+	require.NoError(t, conn.ReadJSON(&reply))
+	return reply
+}
+
+func TestWSCommands(t *testing.T) {
+	listID := int64(5)
+	testCases := []struct {
+		name            string
+		command         string
+		mock            func(todoRepo *mocks.TodoRepository, listRepo *mocks.ListRepository)
+		expectedStatus  int
+		expectedMessage string
+	}{
+		{
+			name:    "Subscribe",
+			command: `{"type": "subscribe", "id": "1", "list_id": 5}`,
+			mock: func(todoRepo *mocks.TodoRepository, listRepo *mocks.ListRepository) {
+				listRepo.On("GetByID", testUserID, listID).Return(&entity.List{ID: listID}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Subscribed",
+		},
+		{
+			name:    "Subscribe to someone else's list",
+			command: `{"type": "subscribe", "id": "1", "list_id": 5}`,
+			mock: func(todoRepo *mocks.TodoRepository, listRepo *mocks.ListRepository) {
+				listRepo.On("GetByID", testUserID, listID).Return(nil, nil)
+			},
+			expectedStatus:  http.StatusNotFound,
+			expectedMessage: "List not Found",
+		},
+		{
+			name:    "Create",
+			command: `{"type": "create", "id": "2", "list_id": 5, "todo": {"title": "deploy"}}`,
+			mock: func(todoRepo *mocks.TodoRepository, listRepo *mocks.ListRepository) {
+				listRepo.On("GetByID", testUserID, listID).Return(&entity.List{ID: listID}, nil)
+				todoRepo.On("Bulk", testUserID, mock.MatchedBy(func(ops []repository.BulkOperation) bool {
+					return len(ops) == 1 && ops[0].Op == repository.BulkCreate && ops[0].Todo.Title == "deploy" && *ops[0].Todo.ListID == listID
+				}), true).Return([]repository.BulkResult{{Todo: &entity.Todolist{ID: 9, ListID: &listID, Title: "deploy", Version: 1}, RowsAffected: 1}}, nil)
+			},
+			expectedStatus:  http.StatusCreated,
+			expectedMessage: "New Todo Created",
+		},
+		{
+			name:    "Create in an archived list",
+			command: `{"type": "create", "id": "2", "list_id": 5, "todo": {"title": "deploy"}}`,
+			mock: func(todoRepo *mocks.TodoRepository, listRepo *mocks.ListRepository) {
+				listRepo.On("GetByID", testUserID, listID).Return(&entity.List{ID: listID, Archived: true}, nil)
+			},
+			expectedStatus:  http.StatusConflict,
+			expectedMessage: "List is archived",
+		},
+		{
+			name:            "Create without a title",
+			command:         `{"type": "create", "id": "2", "todo": {}}`,
+			mock:            func(todoRepo *mocks.TodoRepository, listRepo *mocks.ListRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+		{
+			name:    "Complete",
+			command: `{"type": "complete", "id": "3", "todo_id": 9, "version": 1}`,
+			mock: func(todoRepo *mocks.TodoRepository, listRepo *mocks.ListRepository) {
+				todoRepo.On("Bulk", testUserID, []repository.BulkOperation{{
+					Op: repository.BulkUpdate, TodoID: 9, Version: 1, Updates: map[string]interface{}{"status": true},
+				}}, true).Return([]repository.BulkResult{{Todo: &entity.Todolist{ID: 9, Status: true, Version: 2}, RowsAffected: 1}}, nil)
+			},
+			expectedStatus:  http.StatusOK,
+			expectedMessage: "Success Update Todo",
+		},
+		{
+			name:    "Stale update",
+			command: `{"type": "update", "id": "4", "todo_id": 9, "version": 1, "todo": {"title": "deploy v2"}}`,
+			mock: func(todoRepo *mocks.TodoRepository, listRepo *mocks.ListRepository) {
+				todoRepo.On("Bulk", testUserID, mock.Anything, true).
+					Return([]repository.BulkResult{{Err: repository.ErrVersionMismatch}}, nil)
+			},
+			expectedStatus:  http.StatusPreconditionFailed,
+			expectedMessage: "Precondition Failed",
+		},
+		{
+			name:            "Complete without a todo",
+			command:         `{"type": "complete", "id": "5"}`,
+			mock:            func(todoRepo *mocks.TodoRepository, listRepo *mocks.ListRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+		{
+			name:            "Unknown command",
+			command:         `{"type": "delete", "id": "6", "todo_id": 9}`,
+			mock:            func(todoRepo *mocks.TodoRepository, listRepo *mocks.ListRepository) {},
+			expectedStatus:  http.StatusBadRequest,
+			expectedMessage: "Invalid input",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			todoRepo := mocks.NewTodoRepository(t)
+			listRepo := mocks.NewListRepository(t)
+			tc.mock(todoRepo, listRepo)
+			conn := dialWS(t, setupWSServer(t, todoRepo, listRepo, stream.NewHub()))
+
+			var command request.WSCommand
+			require.NoError(t, json.Unmarshal([]byte(tc.command), &command))
+			reply := roundTrip(t, conn, tc.command)
+			assert.Equal(t, "result", reply.Type)
+			assert.Equal(t, command.ID, reply.ID)
+			assert.Equal(t, tc.expectedStatus, reply.Status)
+			assert.Equal(t, tc.expectedMessage, reply.Message)
+		})
+	}
+}
+
+func TestWSBroadcast(t *testing.T) {
+	todoRepo := mocks.NewTodoRepository(t)
+	todoRepo.On("Bulk", testUserID, mock.Anything, true).
+		Return([]repository.BulkResult{{Todo: &entity.Todolist{ID: 9, UserID: testUserID, Title: "deploy", Version: 1}, RowsAffected: 1}}, nil)
+	hub := stream.NewHub()
+	server := setupWSServer(t, todoRepo, mocks.NewListRepository(t), hub)
+
+	author := dialWS(t, server)
+	other := dialWS(t, server)
+	for _, conn := range []*websocket.Conn{author, other} {
+		require.Equal(t, http.StatusOK, roundTrip(t, conn, `{"type": "subscribe"}`).Status)
+	}
+	require.Equal(t, http.StatusCreated, roundTrip(t, author, `{"type": "create", "todo": {"title": "deploy"}}`).Status)
+
+	// what the outbox relay publishes once the create has committed
+	payload, _ := json.Marshal(entity.EventPayload{ID: "a", Event: entity.EventTodoCreated, Data: entity.Todolist{ID: 9, Title: "deploy", Version: 1}})
+	require.NoError(t, hub.Publish(context.Background(), entity.OutboxEvent{
+		EventID: "a", UserID: testUserID, Event: entity.EventTodoCreated, Payload: string(payload),
+	}))
+
+	var message stream.Message
+	require.NoError(t, other.SetReadDeadline(time.Now().Add(time.Second)))
+	require.NoError(t, other.ReadJSON(&message))
+	assert.Equal(t, entity.EventTodoCreated, message.Event)
+	assert.Contains(t, string(message.Data), `"title":"deploy"`)
+
+	// the author already has the result and is not told again
+	require.NoError(t, author.SetReadDeadline(time.Now().Add(100*time.Millisecond)))
+	_, _, err := author.ReadMessage()
+	assert.Error(t, err)
+}
+
+func TestWSUnauthorized(t *testing.T) {
+	server := setupWSServer(t, mocks.NewTodoRepository(t), mocks.NewListRepository(t), stream.NewHub())
+	dialer := websocket.Dialer{Subprotocols: []string{WSProtocol, middleware.WebSocketTokenPrefix + "forged"}}
+	_, resp, err := dialer.Dial("ws"+strings.TrimPrefix(server.URL, "http")+"/ws", nil)
+	require.Error(t, err)
+	assert.Equal(t, http.StatusUnauthorized, resp.StatusCode)
+}
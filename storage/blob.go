@@ -0,0 +1,102 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"net/http"
+)
+
+var (
+	// ErrTooLarge is returned by Save when the content exceeds its limit.
+	ErrTooLarge = errors.New("blob too large")
+	// ErrChecksumMismatch is returned at the end of a blob read through
+	// Verify whose content is not what was stored.
+	ErrChecksumMismatch = errors.New("blob checksum mismatch")
+)
+
+// sniffLen is how much of the content http.DetectContentType looks at.
+const sniffLen = 512
+
+// Blob describes the content Save has stored.
+type Blob struct {
+	Size int64
+	// Checksum is the hex SHA-256 of the content.
+	Checksum    string
+	ContentType string
+}
+
+// Save stores the content of r under key, sniffing its content type from the
+// first bytes and hashing it on the way. Content longer than limit bytes is
+// not kept and yields ErrTooLarge.
+func Save(ctx context.Context, store BlobStore, key string, r io.Reader, limit int64) (*Blob, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	head = head[:n]
+
+	hash := sha256.New()
+	body := &limitedReader{r: io.MultiReader(bytes.NewReader(head), r), remaining: limit}
+	if err := store.Put(ctx, key, io.TeeReader(body, hash)); err != nil {
+		return nil, err
+	}
+	return &Blob{
+		Size:        limit - body.remaining,
+		Checksum:    hex.EncodeToString(hash.Sum(nil)),
+		ContentType: http.DetectContentType(head),
+	}, nil
+}
+
+// limitedReader fails with ErrTooLarge instead of stopping at the limit, so
+// the store drops what it has written rather than keep a truncated blob.
+type limitedReader struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *limitedReader) Read(p []byte) (int, error) {
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return n, ErrTooLarge
+	}
+	return n, err
+}
+
+// Verify reads rc while hashing it, expecting size bytes with the hex SHA-256
+// checksum. Content that turns out different fails with ErrChecksumMismatch
+// instead of its final read, so that a response streamed from it with the
+// expected Content-Length comes up short rather than quietly wrong.
+func Verify(rc io.ReadCloser, size int64, checksum string) io.ReadCloser {
+	return &verifyingReader{ReadCloser: rc, hash: sha256.New(), remaining: size, checksum: checksum}
+}
+
+type verifyingReader struct {
+	io.ReadCloser
+	hash      hash.Hash
+	remaining int64
+	checksum  string
+}
+
+func (v *verifyingReader) Read(p []byte) (int, error) {
+	n, err := v.ReadCloser.Read(p)
+	v.hash.Write(p[:n])
+	v.remaining -= int64(n)
+	switch {
+	case v.remaining < 0:
+		return 0, ErrChecksumMismatch
+	case v.remaining == 0 && n > 0:
+		if hex.EncodeToString(v.hash.Sum(nil)) != v.checksum {
+			return 0, ErrChecksumMismatch
+		}
+	case errors.Is(err, io.EOF) && v.remaining > 0:
+		return n, ErrChecksumMismatch
+	}
+	return n, err
+}
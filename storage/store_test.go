@@ -0,0 +1,128 @@
+package storage
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLocalStore(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStore(filepath.Join(root, "blobs"))
+	require.NoError(t, err)
+	ctx := context.Background()
+
+	key, err := NewKey()
+	require.NoError(t, err)
+	require.NoError(t, store.Put(ctx, key, strings.NewReader("hello")))
+	assert.FileExists(t, filepath.Join(root, "blobs", key[:2], key))
+
+	rc, err := store.Get(ctx, key)
+	require.NoError(t, err)
+	content, err := io.ReadAll(rc)
+	require.NoError(t, err)
+	require.NoError(t, rc.Close())
+	assert.Equal(t, "hello", string(content))
+
+	require.NoError(t, store.Delete(ctx, key))
+	require.NoError(t, store.Delete(ctx, key), "deleting twice")
+	_, err = store.Get(ctx, key)
+	assert.ErrorIs(t, err, ErrNotFound)
+
+	for _, bad := range []string{"", "ab", "../../etc/passwd", "ab/cd", "/abs"} {
+		assert.Error(t, store.Put(ctx, bad, strings.NewReader("x")), bad)
+	}
+}
+
+func TestLocalStorePutFailureLeavesNothing(t *testing.T) {
+	root := t.TempDir()
+	store, err := NewLocalStore(root)
+	require.NoError(t, err)
+
+	failing := io.MultiReader(strings.NewReader("partial"), &errReader{errors.New("connection reset")})
+	err = store.Put(context.Background(), "abcdef", failing)
+	assert.Error(t, err)
+
+	entries, err := os.ReadDir(filepath.Join(root, "ab"))
+	require.NoError(t, err)
+	assert.Empty(t, entries)
+}
+
+func TestSave(t *testing.T) {
+	png := "\x89PNG\r\n\x1a\n" + strings.Repeat("x", 1000)
+	sum := sha256.Sum256([]byte(png))
+
+	testCases := []struct {
+		name                string
+		content             string
+		limit               int64
+		expectedErr         error
+		expectedContentType string
+	}{
+		{name: "Sniffs type", content: png, limit: 2000, expectedContentType: "image/png"},
+		{name: "Exactly the limit", content: png, limit: int64(len(png)), expectedContentType: "image/png"},
+		{name: "Short text", content: "just a note", limit: 2000, expectedContentType: "text/plain; charset=utf-8"},
+		{name: "Too large", content: png, limit: 600, expectedErr: ErrTooLarge},
+		{name: "Too large within the sniffed bytes", content: png, limit: 100, expectedErr: ErrTooLarge},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			root := t.TempDir()
+			store, err := NewLocalStore(root)
+			require.NoError(t, err)
+
+			blob, err := Save(context.Background(), store, "abcdef", strings.NewReader(tc.content), tc.limit)
+			if tc.expectedErr != nil {
+				assert.ErrorIs(t, err, tc.expectedErr)
+				_, err := store.Get(context.Background(), "abcdef")
+				assert.ErrorIs(t, err, ErrNotFound)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(len(tc.content)), blob.Size)
+			assert.Equal(t, tc.expectedContentType, blob.ContentType)
+			if tc.content == png {
+				assert.Equal(t, hex.EncodeToString(sum[:]), blob.Checksum)
+			}
+		})
+	}
+}
+
+func TestVerify(t *testing.T) {
+	sum := sha256.Sum256([]byte("hello"))
+	checksum := hex.EncodeToString(sum[:])
+
+	testCases := []struct {
+		name        string
+		content     string
+		expected    string
+		expectedErr error
+	}{
+		{name: "Intact", content: "hello", expected: "hello"},
+		{name: "Corrupted", content: "hellO", expectedErr: ErrChecksumMismatch},
+		{name: "Truncated", content: "hell", expected: "hell", expectedErr: ErrChecksumMismatch},
+		{name: "Grown", content: "hello!", expectedErr: ErrChecksumMismatch},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			content, err := io.ReadAll(Verify(io.NopCloser(strings.NewReader(tc.content)), 5, checksum))
+			assert.ErrorIs(t, err, tc.expectedErr)
+			// a bad final read is withheld, so nothing wrong gets out
+			assert.Equal(t, tc.expected, string(content))
+		})
+	}
+}
+
+type errReader struct{ err error }
+
+func (r *errReader) Read([]byte) (int, error) { return 0, r.err }
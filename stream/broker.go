@@ -0,0 +1,189 @@
+// Package stream fans todo events out to the clients following them live:
+// Broker feeds the Server-Sent Events stream, keeping the latest events so
+// that a client which reconnects can pick up where it left off, and Hub
+// feeds the collaboration WebSockets.
+package stream
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"todoGin/model/entity"
+)
+
+// subscriberBuffer is how many events a subscriber may fall behind before it
+// is dropped; it then reconnects and resumes from the broker's buffer.
+const subscriberBuffer = 64
+
+// Event is a todo event as sent to subscribers. Its ID is
+// "<epoch>-<sequence>": the sequence grows by one per event and the epoch
+// tells IDs handed out before a restart apart.
+type Event struct {
+	ID     string
+	UserID int64
+	Name   string
+	Data   string
+
+	seq     uint64
+	eventID string
+}
+
+// Broker keeps the last events in a ring buffer and pushes new ones to the
+// subscribers of their owner. It is an outbox sink and never fails, so the
+// stream sees each committed event once the relay has picked it up.
+type Broker struct {
+	mu     sync.Mutex
+	epoch  string
+	seq    uint64
+	ring   []Event
+	head   int // index of the oldest event
+	count  int
+	seen   map[string]bool // outbox event IDs in the ring
+	subs   map[int64]map[*Subscription]bool
+	closed bool
+}
+
+// NewBroker returns a broker remembering the last size events.
+func NewBroker(size int) *Broker {
+	return &Broker{
+		epoch: strconv.FormatInt(time.Now().UnixNano(), 36),
+		ring:  make([]Event, size),
+		seen:  make(map[string]bool),
+		subs:  make(map[int64]map[*Subscription]bool),
+	}
+}
+
+// Subscription receives the events of one user on C, which is closed when
+// the subscriber falls too far behind or the broker shuts down.
+type Subscription struct {
+	C <-chan Event
+
+	ch     chan Event
+	userID int64
+	broker *Broker
+}
+
+// Close unsubscribes. It is safe to call more than once.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+	s.broker.drop(s)
+}
+
+// Publish stores the event and pushes it to its owner's subscribers. An event
+// still in the buffer is not sent twice when the relay repeats it.
+func (b *Broker) Publish(ctx context.Context, event entity.OutboxEvent) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.seen[event.EventID] {
+		return nil
+	}
+
+	b.seq++
+	e := Event{
+		ID:      fmt.Sprintf("%s-%d", b.epoch, b.seq),
+		UserID:  event.UserID,
+		Name:    event.Event,
+		Data:    event.Payload,
+		seq:     b.seq,
+		eventID: event.EventID,
+	}
+	if len(b.ring) > 0 {
+		if b.count == len(b.ring) {
+			delete(b.seen, b.ring[b.head].eventID)
+			b.head = (b.head + 1) % len(b.ring)
+			b.count--
+		}
+		b.ring[(b.head+b.count)%len(b.ring)] = e
+		b.count++
+		b.seen[e.eventID] = true
+	}
+
+	for sub := range b.subs[e.UserID] {
+		select {
+		case sub.ch <- e:
+		default:
+			b.drop(sub)
+		}
+	}
+	return nil
+}
+
+// Subscribe follows the user's events. With a lastID from an earlier
+// subscription it also returns the user's buffered events after it. It
+// reports false, and no events, when it cannot tell what the subscriber
+// missed: events after lastID were already evicted or the ID is from before
+// a restart. Subscribe returns nil once the broker is closed.
+func (b *Broker) Subscribe(userID int64, lastID string) (*Subscription, []Event, bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.closed {
+		return nil, nil, false
+	}
+
+	ch := make(chan Event, subscriberBuffer)
+	sub := &Subscription{C: ch, ch: ch, userID: userID, broker: b}
+	if b.subs[userID] == nil {
+		b.subs[userID] = make(map[*Subscription]bool)
+	}
+	b.subs[userID][sub] = true
+
+	if lastID == "" {
+		return sub, nil, true
+	}
+	after, ok := b.parseID(lastID)
+	oldest := b.seq + 1
+	if b.count > 0 {
+		oldest = b.ring[b.head].seq
+	}
+	if !ok || after > b.seq || after+1 < oldest {
+		return sub, nil, false
+	}
+
+	var backlog []Event
+	for i := 0; i < b.count; i++ {
+		e := b.ring[(b.head+i)%len(b.ring)]
+		if e.UserID == userID && e.seq > after {
+			backlog = append(backlog, e)
+		}
+	}
+	return sub, backlog, true
+}
+
+// Close ends every subscription and refuses new ones, so that open streams
+// do not hold up a shutdown.
+func (b *Broker) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.closed = true
+	for _, subs := range b.subs {
+		for sub := range subs {
+			b.drop(sub)
+		}
+	}
+}
+
+// drop removes the subscription and closes its channel; b.mu must be held.
+func (b *Broker) drop(sub *Subscription) {
+	if !b.subs[sub.userID][sub] {
+		return
+	}
+	delete(b.subs[sub.userID], sub)
+	if len(b.subs[sub.userID]) == 0 {
+		delete(b.subs, sub.userID)
+	}
+	close(sub.ch)
+}
+
+// parseID returns the sequence of an ID handed out by this broker.
+func (b *Broker) parseID(id string) (uint64, bool) {
+	epoch, seq, ok := strings.Cut(id, "-")
+	if !ok || epoch != b.epoch {
+		return 0, false
+	}
+	n, err := strconv.ParseUint(seq, 10, 64)
+	return n, err == nil
+}
@@ -0,0 +1,126 @@
+package stream
+
+import (
+	"context"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"todoGin/model/entity"
+)
+
+func publish(t *testing.T, b *Broker, userID int64, eventID string) {
+	t.Helper()
+	require.NoError(t, b.Publish(context.Background(), entity.OutboxEvent{
+		EventID: eventID,
+		UserID:  userID,
+		Event:   entity.EventTodoUpdated,
+		Payload: fmt.Sprintf(`{"id":%q}`, eventID),
+	}))
+}
+
+func names(events []Event) []string {
+	var ids []string
+	for _, e := range events {
+		ids = append(ids, e.eventID)
+	}
+	return ids
+}
+
+func TestBrokerLive(t *testing.T) {
+	b := NewBroker(8)
+	sub, backlog, complete := b.Subscribe(1, "")
+	require.NotNil(t, sub)
+	assert.Empty(t, backlog)
+	assert.True(t, complete)
+
+	publish(t, b, 1, "a")
+	publish(t, b, 2, "b") // someone else's
+	publish(t, b, 1, "a") // repeated by the relay
+
+	event := <-sub.C
+	assert.Equal(t, "a", event.eventID)
+	assert.Equal(t, `{"id":"a"}`, event.Data)
+	assert.Equal(t, entity.EventTodoUpdated, event.Name)
+	assert.Empty(t, sub.C)
+
+	sub.Close()
+	sub.Close()
+	_, open := <-sub.C
+	assert.False(t, open)
+}
+
+func TestBrokerResume(t *testing.T) {
+	b := NewBroker(4)
+	publish(t, b, 1, "a")
+	publish(t, b, 1, "b")
+	publish(t, b, 2, "c")
+	publish(t, b, 1, "d")
+
+	ids := map[string]string{}
+	for i := 0; i < b.count; i++ {
+		e := b.ring[(b.head+i)%len(b.ring)]
+		ids[e.eventID] = e.ID
+	}
+
+	testCases := []struct {
+		name     string
+		lastID   string
+		expected []string
+		complete bool
+	}{
+		{name: "From the start of the buffer", lastID: ids["a"], expected: []string{"b", "d"}, complete: true},
+		{name: "Up to date", lastID: ids["d"], complete: true},
+		{name: "Other epoch", lastID: "zzz-2", complete: false},
+		{name: "Garbage", lastID: "nope", complete: false},
+		{name: "From the future", lastID: fmt.Sprintf("%s-%d", b.epoch, 99), complete: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			sub, backlog, complete := b.Subscribe(1, tc.lastID)
+			defer sub.Close()
+			assert.Equal(t, tc.expected, names(backlog))
+			assert.Equal(t, tc.complete, complete)
+		})
+	}
+
+	// once "a" and "b" are evicted, resuming after "a" would miss "b"
+	publish(t, b, 1, "e")
+	publish(t, b, 1, "f")
+	sub, backlog, complete := b.Subscribe(1, ids["a"])
+	defer sub.Close()
+	assert.Empty(t, backlog)
+	assert.False(t, complete)
+
+	// an evicted event is no longer recognised as a repeat
+	publish(t, b, 1, "a")
+	assert.Equal(t, "a", (<-sub.C).eventID)
+}
+
+func TestBrokerDropsSlowSubscriber(t *testing.T) {
+	b := NewBroker(4)
+	sub, _, _ := b.Subscribe(1, "")
+	for i := 0; i <= subscriberBuffer; i++ {
+		publish(t, b, 1, fmt.Sprint(i))
+	}
+
+	received := 0
+	for range sub.C {
+		received++
+	}
+	assert.Equal(t, subscriberBuffer, received)
+}
+
+func TestBrokerClose(t *testing.T) {
+	b := NewBroker(4)
+	sub, _, _ := b.Subscribe(1, "")
+	b.Close()
+
+	_, open := <-sub.C
+	assert.False(t, open)
+	sub.Close()
+
+	again, _, _ := b.Subscribe(1, "")
+	assert.Nil(t, again)
+}
@@ -0,0 +1,254 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+	"todoGin/model/entity"
+)
+
+// hubRecent is how many event IDs the hub remembers to drop repeats.
+const hubRecent = 1024
+
+// clientOwn is how many todos a client remembers its own changes of.
+const clientOwn = 256
+
+// Message is an event as written to collaboration clients.
+type Message struct {
+	Type   string          `json:"type"`
+	Event  string          `json:"event"`
+	ListID *int64          `json:"list_id"`
+	Data   json.RawMessage `json:"data"`
+}
+
+// MessageEvent is the Type of a Message.
+const MessageEvent = "event"
+
+// eventTodo holds the fields of an event's data the hub routes by.
+type eventTodo struct {
+	ID      int64  `json:"id"`
+	ListID  *int64 `json:"list_id"`
+	Version int64  `json:"version"`
+}
+
+// Hub pushes todo events to the collaboration clients subscribed to the
+// todo's list, or to the todos outside any list. Like Broker it is an outbox
+// sink that never fails.
+type Hub struct {
+	mu      sync.Mutex
+	clients map[int64]map[*Client]bool
+	recent  []string
+	next    int
+	seen    map[string]bool
+	closed  bool
+}
+
+func NewHub() *Hub {
+	return &Hub{
+		clients: make(map[int64]map[*Client]bool),
+		recent:  make([]string, hubRecent),
+		seen:    make(map[string]bool),
+	}
+}
+
+// Client is one connection of a user. Messages for it arrive on Send, which
+// is closed when it falls too far behind or the hub shuts down.
+type Client struct {
+	Send <-chan []byte
+
+	send   chan []byte
+	userID int64
+	hub    *Hub
+	lists  map[int64]bool // 0 stands for the todos outside any list
+	own    map[int64]int64
+	held   []heldMessage
+	hold   bool
+}
+
+// heldMessage is a message queued while its client is held.
+type heldMessage struct {
+	todo    eventTodo
+	message []byte
+}
+
+// Register connects a client of the user, or returns nil once the hub is
+// closed.
+func (h *Hub) Register(userID int64) *Client {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.closed {
+		return nil
+	}
+	send := make(chan []byte, subscriberBuffer)
+	c := &Client{
+		Send:   send,
+		send:   send,
+		userID: userID,
+		hub:    h,
+		lists:  make(map[int64]bool),
+		own:    make(map[int64]int64),
+	}
+	if h.clients[userID] == nil {
+		h.clients[userID] = make(map[*Client]bool)
+	}
+	h.clients[userID][c] = true
+	return c
+}
+
+// Subscribe starts sending the client the events of the list, or of the
+// todos outside any list when listID is nil.
+func (c *Client) Subscribe(listID *int64) {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+	c.lists[listKey(listID)] = true
+}
+
+func (c *Client) Unsubscribe(listID *int64) {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+	delete(c.lists, listKey(listID))
+}
+
+// Ignore skips the events of the client's own change that left the todo at
+// version: the client already has the result, and only the other clients
+// need to hear about it. A todo is forgotten once a later version of it
+// goes out, and past clientOwn todos an arbitrary one is, whose events then
+// reach the client like anyone else's.
+func (c *Client) Ignore(todoID, version int64) {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+	if _, ok := c.own[todoID]; !ok && len(c.own) >= clientOwn {
+		for id := range c.own {
+			delete(c.own, id)
+			break
+		}
+	}
+	if version > c.own[todoID] {
+		c.own[todoID] = version
+	}
+}
+
+// Hold queues the client's messages instead of sending them, until Release.
+// A client holds while its own change commits: the relay may publish the
+// change before the client gets to Ignore it.
+func (c *Client) Hold() {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+	c.hold = true
+}
+
+// Release sends the messages queued since Hold, skipping those the client
+// has meanwhile been told to Ignore.
+func (c *Client) Release() {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+	c.hold = false
+	held := c.held
+	c.held = nil
+	for _, m := range held {
+		if !c.hub.clients[c.userID][c] {
+			return
+		}
+		c.hub.deliver(c, m.todo, m.message)
+	}
+}
+
+// Close disconnects the client. It is safe to call more than once.
+func (c *Client) Close() {
+	c.hub.mu.Lock()
+	defer c.hub.mu.Unlock()
+	c.hub.drop(c)
+}
+
+// Publish sends the event to the owner's clients subscribed to the todo's
+// list, once even when the relay repeats it. An event whose payload cannot
+// be read is skipped rather than holding up the relay.
+func (h *Hub) Publish(ctx context.Context, event entity.OutboxEvent) error {
+	var payload struct {
+		Data json.RawMessage `json:"data"`
+	}
+	var todo eventTodo
+	if err := json.Unmarshal([]byte(event.Payload), &payload); err != nil {
+		return nil
+	}
+	if err := json.Unmarshal(payload.Data, &todo); err != nil {
+		return nil
+	}
+	message, err := json.Marshal(Message{Type: MessageEvent, Event: event.Event, ListID: todo.ListID, Data: payload.Data})
+	if err != nil {
+		return nil
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.seen[event.EventID] {
+		return nil
+	}
+	delete(h.seen, h.recent[h.next])
+	h.recent[h.next] = event.EventID
+	h.next = (h.next + 1) % len(h.recent)
+	h.seen[event.EventID] = true
+
+	for c := range h.clients[event.UserID] {
+		if !c.lists[listKey(todo.ListID)] {
+			continue
+		}
+		if c.hold {
+			if len(c.held) >= subscriberBuffer {
+				h.drop(c)
+				continue
+			}
+			c.held = append(c.held, heldMessage{todo: todo, message: message})
+			continue
+		}
+		h.deliver(c, todo, message)
+	}
+	return nil
+}
+
+// deliver sends the client the message unless it is of the client's own
+// change; h.mu must be held.
+func (h *Hub) deliver(c *Client, todo eventTodo, message []byte) {
+	if own, ok := c.own[todo.ID]; ok && todo.Version > 0 {
+		if todo.Version <= own {
+			return
+		}
+		delete(c.own, todo.ID)
+	}
+	select {
+	case c.send <- message:
+	default:
+		h.drop(c)
+	}
+}
+
+// Close disconnects every client and refuses new ones.
+func (h *Hub) Close() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.closed = true
+	for _, clients := range h.clients {
+		for c := range clients {
+			h.drop(c)
+		}
+	}
+}
+
+// drop removes the client and closes its channel; h.mu must be held.
+func (h *Hub) drop(c *Client) {
+	if !h.clients[c.userID][c] {
+		return
+	}
+	delete(h.clients[c.userID], c)
+	if len(h.clients[c.userID]) == 0 {
+		delete(h.clients, c.userID)
+	}
+	close(c.send)
+}
+
+func listKey(listID *int64) int64 {
+	if listID == nil {
+		return 0
+	}
+	return *listID
+}
@@ -0,0 +1,149 @@
+package stream
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"testing"
+	"todoGin/model/entity"
+)
+
+func int64Ptr(n int64) *int64 {
+	return &n
+}
+
+func publishTodo(t *testing.T, h *Hub, userID int64, eventID, event string, data interface{}) {
+	t.Helper()
+	payload, err := json.Marshal(entity.EventPayload{ID: eventID, Event: event, Data: data})
+	require.NoError(t, err)
+	require.NoError(t, h.Publish(context.Background(), entity.OutboxEvent{
+		EventID: eventID,
+		UserID:  userID,
+		Event:   event,
+		Payload: string(payload),
+	}))
+}
+
+func received(c *Client) []Message {
+	var messages []Message
+	for {
+		select {
+		case data := <-c.Send:
+			var message Message
+			if err := json.Unmarshal(data, &message); err == nil {
+				messages = append(messages, message)
+			}
+		default:
+			return messages
+		}
+	}
+}
+
+func TestHubRouting(t *testing.T) {
+	h := NewHub()
+	inList := h.Register(1)
+	inList.Subscribe(int64Ptr(5))
+	inbox := h.Register(1)
+	inbox.Subscribe(nil)
+	stranger := h.Register(2)
+	stranger.Subscribe(int64Ptr(5))
+
+	publishTodo(t, h, 1, "a", entity.EventTodoCreated, entity.Todolist{ID: 9, ListID: int64Ptr(5), Version: 1})
+	publishTodo(t, h, 1, "b", entity.EventTodoCreated, entity.Todolist{ID: 10, Version: 1})
+	publishTodo(t, h, 1, "a", entity.EventTodoCreated, entity.Todolist{ID: 9, ListID: int64Ptr(5), Version: 1})
+	publishTodo(t, h, 1, "c", entity.EventTodoDeleted, map[string]interface{}{"id": 9, "list_id": 5})
+
+	messages := received(inList)
+	require.Len(t, messages, 2)
+	assert.Equal(t, MessageEvent, messages[0].Type)
+	assert.Equal(t, entity.EventTodoCreated, messages[0].Event)
+	assert.Equal(t, int64(5), *messages[0].ListID)
+	assert.Equal(t, entity.EventTodoDeleted, messages[1].Event)
+
+	messages = received(inbox)
+	require.Len(t, messages, 1)
+	assert.Nil(t, messages[0].ListID)
+
+	assert.Empty(t, received(stranger))
+
+	inList.Unsubscribe(int64Ptr(5))
+	publishTodo(t, h, 1, "d", entity.EventTodoUpdated, entity.Todolist{ID: 9, ListID: int64Ptr(5), Version: 2})
+	assert.Empty(t, received(inList))
+}
+
+func TestHubIgnoresOwnChanges(t *testing.T) {
+	h := NewHub()
+	author := h.Register(1)
+	author.Subscribe(nil)
+	other := h.Register(1)
+	other.Subscribe(nil)
+
+	author.Ignore(9, 2)
+	publishTodo(t, h, 1, "a", entity.EventTodoUpdated, entity.Todolist{ID: 9, Version: 2})
+	publishTodo(t, h, 1, "b", entity.EventTodoCompleted, entity.Todolist{ID: 9, Version: 2})
+	publishTodo(t, h, 1, "c", entity.EventTodoUpdated, entity.Todolist{ID: 9, Version: 3})
+
+	assert.Len(t, received(author), 1)
+	assert.Len(t, received(other), 3)
+	assert.Empty(t, author.own, "forgotten once a later version went out")
+
+	for id := int64(1); id <= clientOwn+10; id++ {
+		author.Ignore(id, 1)
+	}
+	assert.Len(t, author.own, clientOwn)
+}
+
+func TestHubHoldsOwnChanges(t *testing.T) {
+	h := NewHub()
+	author := h.Register(1)
+	author.Subscribe(nil)
+
+	author.Hold()
+	publishTodo(t, h, 1, "a", entity.EventTodoUpdated, entity.Todolist{ID: 9, Version: 2})
+	publishTodo(t, h, 1, "b", entity.EventTodoUpdated, entity.Todolist{ID: 10, Version: 4})
+	assert.Empty(t, received(author), "held until released")
+
+	author.Ignore(9, 2)
+	author.Release()
+	messages := received(author)
+	require.Len(t, messages, 1)
+	assert.Equal(t, MessageEvent, messages[0].Type)
+
+	publishTodo(t, h, 1, "c", entity.EventTodoUpdated, entity.Todolist{ID: 9, Version: 3})
+	assert.Len(t, received(author), 1)
+
+	author.Hold()
+	publishTodo(t, h, 1, "d", entity.EventTodoUpdated, entity.Todolist{ID: 10, Version: 5})
+	author.Close()
+	author.Release()
+	_, ok := <-author.Send
+	assert.False(t, ok, "nothing sent once closed")
+}
+
+func TestHubDropsSlowClient(t *testing.T) {
+	h := NewHub()
+	c := h.Register(1)
+	c.Subscribe(nil)
+	for i := 0; i <= subscriberBuffer; i++ {
+		publishTodo(t, h, 1, fmt.Sprint(i), entity.EventTodoUpdated, entity.Todolist{ID: 9, Version: int64(i + 1)})
+	}
+
+	count := 0
+	for range c.Send {
+		count++
+	}
+	assert.Equal(t, subscriberBuffer, count)
+	c.Close()
+}
+
+func TestHubClose(t *testing.T) {
+	h := NewHub()
+	c := h.Register(1)
+	h.Close()
+
+	_, open := <-c.Send
+	assert.False(t, open)
+	assert.Nil(t, h.Register(1))
+}
@@ -13,16 +13,23 @@ import (
 	"log"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"testing"
+	"time"
 	"todoGin/database"
+	"todoGin/middleware"
+	"todoGin/model/entity"
 	"todoGin/router"
 	"todoGin/service"
+	"todoGin/storage"
+	"todoGin/stream"
 )
 
 func setupTestDB() (*gorm.DB, error) {
-	db, err := gorm.Open(mysql.Open("Raihan:Pastibisa@tcp(localhost:3306)/Gin_test"))
+	db, err := gorm.Open(mysql.Open("Raihan:Pastibisa@tcp(localhost:3306)/Gin_test?parseTime=true"))
 	if err != nil {
 		fmt.Println(err)
 	}
@@ -31,28 +38,84 @@ func setupTestDB() (*gorm.DB, error) {
 	return db, nil
 }
 
+const testJWTSecret = "test_secret"
+
 func setupRouter(db *gorm.DB) *gin.Engine {
 	todoRepo := database.NewTodoRepository(db)
+	listRepo := database.NewListRepository(db)
+	subtaskRepo := database.NewSubtaskRepository(db)
+	tagRepo := database.NewTagRepository(db)
+	commentRepo := database.NewCommentRepository(db)
+	attachmentRepo := database.NewAttachmentRepository(db)
+	webhookRepo := database.NewWebhookRepository(db)
+	activityRepo := database.NewActivityRepository(db)
+	userRepo := database.NewUserRepository(db)
 	todoService := service.NewTodoService(todoRepo)
-	routeBuilder := router.NewRouteBuilder(todoService)
+	listService := service.NewListService(listRepo, todoRepo)
+	subtaskService := service.NewSubtaskService(subtaskRepo)
+	tagService := service.NewTagService(tagRepo)
+	commentService := service.NewCommentService(commentRepo)
+	blobStore, err := storage.NewLocalStore(filepath.Join(os.TempDir(), "todo-attachments"))
+	if err != nil {
+		log.Fatal(err)
+	}
+	attachmentService := service.NewAttachmentService(attachmentRepo, blobStore, 1<<20)
+	webhookService := service.NewWebhookService(webhookRepo)
+	activityService := service.NewActivityService(activityRepo, todoRepo, 5*time.Minute)
+	streamService := service.NewStreamService(stream.NewBroker(16), time.Minute)
+	wsService := service.NewWSService(todoRepo, listRepo, stream.NewHub(), nil)
+	authService := service.NewAuthService(userRepo, testJWTSecret, time.Hour)
+	routeBuilder := router.NewRouteBuilder(todoService, listService, subtaskService, tagService, commentService, attachmentService, webhookService, activityService, streamService, wsService, authService)
 	routeInit := routeBuilder.RouteInit()
 
 	return routeInit
 }
 
-func truncateTodolist(DB *gorm.DB) {
-	DB.Exec("TRUNCATE todolists")
+func testUser(db *gorm.DB) *entity.User {
+	userRepo := database.NewUserRepository(db)
+	user, _ := userRepo.GetByUsername("tester")
+	if user == nil {
+		user, _ = userRepo.Create("tester", "not-a-real-hash", nil)
+	}
+	return user
+}
+
+func authHeader(db *gorm.DB) string {
+	token, _, err := middleware.GenerateToken(testUser(db), testJWTSecret, time.Hour)
+	if err != nil {
+		logrus.Error(err)
+	}
+	return "Bearer " + token
+}
+
+// truncateTodolist empties todolists and the tables hanging off it. MySQL
+// refuses to truncate a table other tables refer to, so foreign key checks
+// are switched off for the one connection doing it.
+func truncateTodolist(t *testing.T, DB *gorm.DB) {
+	err := DB.Connection(func(conn *gorm.DB) error {
+		if err := conn.Exec("SET FOREIGN_KEY_CHECKS = 0").Error; err != nil {
+			return err
+		}
+		defer conn.Exec("SET FOREIGN_KEY_CHECKS = 1")
+		for _, table := range []string{"subtasks", "todo_tags", "comments", "attachments", "todolists"} {
+			if err := conn.Exec("TRUNCATE " + table).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
 }
 
 func TestCreateSuccess(t *testing.T) {
 	db, _ := setupTestDB()
 
-	truncateTodolist(db)
+	truncateTodolist(t, db)
 	router := setupRouter(db)
 
 	requestBody := strings.NewReader(`{"title": "sholat isya"}`)
 	request := httptest.NewRequest(http.MethodPost, "http://localhost:3000/manage-todo", requestBody)
-	request.Header.Add("Authorization", "Basic a2V5OnZhbHVl")
+	request.Header.Add("Authorization", authHeader(db))
 
 	recorder := httptest.NewRecorder()
 
@@ -74,12 +137,12 @@ func TestCreateSuccess(t *testing.T) {
 }
 func TestCreateFailedValidation(t *testing.T) {
 	db, _ := setupTestDB()
-	truncateTodolist(db)
+	truncateTodolist(t, db)
 	router := setupRouter(db)
 
 	requestBody := strings.NewReader(`{"title" : ""}`)
 	request := httptest.NewRequest(http.MethodPost, "http://localhost:3000/manage-todo", requestBody)
-	request.Header.Add("Authorization", "Basic a2V5OnZhbHVl")
+	request.Header.Add("Authorization", authHeader(db))
 
 	recorder := httptest.NewRecorder()
 
@@ -102,18 +165,18 @@ func TestUpdateSuccess(t *testing.T) {
 	if err != nil {
 		log.Fatal(err)
 	}
-	truncateTodolist(db)
+	truncateTodolist(t, db)
 	router := setupRouter(db)
 
 	tx := db.Begin()
 	todolistRepository := database.NewTodoRepository(db)
-	todolist, _ := todolistRepository.Create("halo")
+	todolist, _ := todolistRepository.Create(&entity.Todolist{UserID: testUser(db).ID, Title: "halo"})
 
 	tx.Commit()
 
 	requestBody := strings.NewReader(`{"title": "sholat isya","status": true}`)
 	request := httptest.NewRequest(http.MethodPut, "http://localhost:3000/manage-todo/todo/"+strconv.Itoa(int(todolist.ID)), requestBody)
-	request.Header.Add("Authorization", "Basic a2V5OnZhbHVl")
+	request.Header.Add("Authorization", authHeader(db))
 
 	recorder := httptest.NewRecorder()
 	router.ServeHTTP(recorder, request)
@@ -134,18 +197,18 @@ func TestUpdateInvalid(t *testing.T) {
 	if err != nil {
 		log.Fatal(err)
 	}
-	truncateTodolist(db)
+	truncateTodolist(t, db)
 	router := setupRouter(db)
 
 	tx := db.Begin()
 	todolistRepository := database.NewTodoRepository(db)
-	todolist, _ := todolistRepository.Create("holaa")
+	todolist, _ := todolistRepository.Create(&entity.Todolist{UserID: testUser(db).ID, Title: "holaa"})
 
 	tx.Commit()
 
 	requestBody := strings.NewReader(`{"title":  }`)
 	request := httptest.NewRequest(http.MethodPut, "http://localhost:3000/manage-todo/todo/"+strconv.Itoa(int(todolist.ID)), requestBody)
-	request.Header.Add("Authorization", "Basic a2V5OnZhbHVl")
+	request.Header.Add("Authorization", authHeader(db))
 
 	recorder := httptest.NewRecorder()
 	router.ServeHTTP(recorder, request)
@@ -161,16 +224,16 @@ func TestUpdateInvalid(t *testing.T) {
 }
 func TestGetSuccess(t *testing.T) {
 	db, err := setupTestDB()
-	truncateTodolist(db)
+	truncateTodolist(t, db)
 	router := setupRouter(db)
 
 	tx := db.Begin()
 	todolistRepository := database.NewTodoRepository(db)
-	todolist, _ := todolistRepository.Create("makan pagi")
+	todolist, _ := todolistRepository.Create(&entity.Todolist{UserID: testUser(db).ID, Title: "makan pagi"})
 	tx.Commit()
 
 	request := httptest.NewRequest(http.MethodGet, "/manage-todo/todo/1", nil)
-	request.Header.Add("Authorization", "Basic a2V5OnZhbHVl")
+	request.Header.Add("Authorization", authHeader(db))
 
 	recorder := httptest.NewRecorder()
 	router.ServeHTTP(recorder, request)
@@ -190,11 +253,11 @@ func TestGetSuccess(t *testing.T) {
 }
 func TestGetFailed(t *testing.T) {
 	db, _ := setupTestDB()
-	truncateTodolist(db)
+	truncateTodolist(t, db)
 	router := setupRouter(db)
 
 	request := httptest.NewRequest(http.MethodGet, "/manage-todo/todo/404", nil)
-	request.Header.Add("Authorization", "Basic a2V5OnZhbHVl")
+	request.Header.Add("Authorization", authHeader(db))
 
 	recorder := httptest.NewRecorder()
 	router.ServeHTTP(recorder, request)
@@ -212,19 +275,19 @@ func TestGetFailed(t *testing.T) {
 }
 func TestDeleteSuccess(t *testing.T) {
 	db, _ := setupTestDB()
-	truncateTodolist(db)
+	truncateTodolist(t, db)
 
 	tx := db.Begin()
 
 	todolistRepo := database.NewTodoRepository(db)
-	todolist, _ := todolistRepo.Create("hapus ini")
+	todolist, _ := todolistRepo.Create(&entity.Todolist{UserID: testUser(db).ID, Title: "hapus ini"})
 
 	tx.Commit()
 
 	router := setupRouter(db)
 
 	request := httptest.NewRequest(http.MethodDelete, "/manage-todo/todo/"+strconv.Itoa(int(todolist.ID)), nil)
-	request.Header.Add("Authorization", "Basic a2V5OnZhbHVl")
+	request.Header.Add("Authorization", authHeader(db))
 
 	recorder := httptest.NewRecorder()
 
@@ -243,12 +306,12 @@ func TestDeleteSuccess(t *testing.T) {
 }
 func TestDeleteFailedNotFound(t *testing.T) {
 	db, _ := setupTestDB()
-	truncateTodolist(db)
+	truncateTodolist(t, db)
 
 	router := setupRouter(db)
 
 	request := httptest.NewRequest(http.MethodDelete, "/manage-todo/todo/404", nil)
-	request.Header.Add("Authorization", "Basic a2V5OnZhbHVl")
+	request.Header.Add("Authorization", authHeader(db))
 
 	recorder := httptest.NewRecorder()
 
@@ -267,19 +330,19 @@ func TestDeleteFailedNotFound(t *testing.T) {
 }
 func TestGetAll(t *testing.T) {
 	db, _ := setupTestDB()
-	truncateTodolist(db)
+	truncateTodolist(t, db)
 
 	tx := db.Begin()
 
 	todolistRepo := database.NewTodoRepository(db)
-	todolist1, _ := todolistRepo.Create("hapus ini")
-	todolist2, _ := todolistRepo.Create("hapus itu")
+	todolist1, _ := todolistRepo.Create(&entity.Todolist{UserID: testUser(db).ID, Title: "hapus ini"})
+	todolist2, _ := todolistRepo.Create(&entity.Todolist{UserID: testUser(db).ID, Title: "hapus itu"})
 	tx.Commit()
 
 	router := setupRouter(db)
 
 	request := httptest.NewRequest(http.MethodGet, "/manage-todos", nil)
-	request.Header.Add("Authorization", "Basic a2V5OnZhbHVl")
+	request.Header.Add("Authorization", authHeader(db))
 
 	recorder := httptest.NewRecorder()
 
@@ -309,7 +372,7 @@ func TestGetAll(t *testing.T) {
 }
 func TestUnauthorized(t *testing.T) {
 	db, _ := setupTestDB()
-	truncateTodolist(db)
+	truncateTodolist(t, db)
 	router := setupRouter(db)
 
 	request := httptest.NewRequest(http.MethodGet, "/manage-todos", nil)
@@ -0,0 +1,27 @@
+package webhook
+
+import (
+	"context"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+// Dispatcher is the outbox sink that queues events for delivery to the
+// subscribed webhooks. The deliveries go out from a background worker, so
+// publishing never waits on a receiver.
+type Dispatcher struct {
+	WebhookRepository repository.WebhookRepository
+}
+
+func NewDispatcher(webhookRepo repository.WebhookRepository) *Dispatcher {
+	return &Dispatcher{
+		WebhookRepository: webhookRepo,
+	}
+}
+
+// Publish queues the event for the user's webhooks. Publishing the same
+// event again queues nothing new.
+func (d *Dispatcher) Publish(ctx context.Context, event entity.OutboxEvent) error {
+	_, err := d.WebhookRepository.Enqueue(event.UserID, event.EventID, event.Event, event.Payload)
+	return err
+}
@@ -0,0 +1,148 @@
+package webhook
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+	"todoGin/mocks"
+	"todoGin/model/entity"
+)
+
+func TestSign(t *testing.T) {
+	body := []byte(`{"event":"todo.created"}`)
+	signature := Sign("secret", 1686560400, body)
+
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+	assert.True(t, Verify("secret", 1686560400, body, signature))
+	assert.False(t, Verify("other", 1686560400, body, signature))
+	assert.False(t, Verify("secret", 1686560401, body, signature))
+	assert.False(t, Verify("secret", 1686560400, []byte(`{}`), signature))
+}
+
+func TestNewSecret(t *testing.T) {
+	a, err := NewSecret()
+	require.NoError(t, err)
+	b, err := NewSecret()
+	require.NoError(t, err)
+	assert.Len(t, a, 64)
+	assert.NotEqual(t, a, b)
+}
+
+func TestSend(t *testing.T) {
+	testCases := []struct {
+		name    string
+		status  int
+		wantErr bool
+	}{
+		{name: "Success", status: http.StatusOK},
+		{name: "Rejected", status: http.StatusGone, wantErr: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var header http.Header
+			var body []byte
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				header = r.Header
+				body, _ = io.ReadAll(r.Body)
+				w.WriteHeader(tc.status)
+			}))
+			defer server.Close()
+
+			delivery := &entity.WebhookDelivery{
+				ID:      42,
+				Event:   entity.EventTodoCreated,
+				Payload: `{"event":"todo.created","data":{"id":1}}`,
+				Webhook: &entity.Webhook{URL: server.URL, Secret: "secret"},
+			}
+			now := time.Unix(1686560400, 0)
+			code, err := Send(context.Background(), server.Client(), delivery, now)
+
+			assert.Equal(t, tc.status, code)
+			if tc.wantErr {
+				assert.Error(t, err)
+			} else {
+				assert.NoError(t, err)
+			}
+			assert.Equal(t, delivery.Payload, string(body))
+			assert.Equal(t, "todo.created", header.Get(HeaderEvent))
+			assert.Equal(t, "42", header.Get(HeaderDelivery))
+			timestamp, err := strconv.ParseInt(header.Get(HeaderTimestamp), 10, 64)
+			require.NoError(t, err)
+			assert.True(t, Verify("secret", timestamp, body, header.Get(HeaderSignature)))
+		})
+	}
+}
+
+func TestDispatcherPublish(t *testing.T) {
+	repo := mocks.NewWebhookRepository(t)
+	repo.On("Enqueue", int64(7), "0b5f4e0c-4a8e-4d3b-9a52-8f9d6c1e2a3b", entity.EventTodoCreated, `{"id":1}`).
+		Return(int64(1), nil).Once()
+	repo.On("Enqueue", int64(7), "0b5f4e0c-4a8e-4d3b-9a52-8f9d6c1e2a3b", entity.EventTodoCreated, `{"id":1}`).
+		Return(int64(0), errors.New("connection refused")).Once()
+
+	event := entity.OutboxEvent{
+		EventID: "0b5f4e0c-4a8e-4d3b-9a52-8f9d6c1e2a3b",
+		UserID:  7,
+		Event:   entity.EventTodoCreated,
+		Payload: `{"id":1}`,
+	}
+	dispatcher := NewDispatcher(repo)
+	assert.NoError(t, dispatcher.Publish(context.Background(), event))
+	assert.Error(t, dispatcher.Publish(context.Background(), event))
+}
+
+func TestNewClientRefusesPrivateAddresses(t *testing.T) {
+	redirected := false
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		redirected = true
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.RedirectHandler(target.URL, http.StatusFound))
+	defer server.Close()
+
+	delivery := &entity.WebhookDelivery{Payload: `{}`, Webhook: &entity.Webhook{URL: server.URL, Secret: "secret"}}
+	_, err := Send(context.Background(), NewClient(time.Second), delivery, time.Now())
+	assert.ErrorIs(t, err, ErrPrivateAddress)
+
+	// redirects are not followed, wherever they lead; the test server's own
+	// transport stands in for the public-only one
+	client := server.Client()
+	client.CheckRedirect = NewClient(time.Second).CheckRedirect
+	code, err := Send(context.Background(), client, delivery, time.Now())
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusFound, code)
+	assert.False(t, redirected)
+}
+
+func TestIsPublicHost(t *testing.T) {
+	testCases := map[string]bool{
+		"hooks.example.com": true,
+		"93.184.216.34":     true,
+		"2606:4700::1111":   true,
+		"localhost":         false,
+		"LOCALHOST.":        false,
+		"api.localhost":     false,
+		"127.0.0.1":         false,
+		"10.1.2.3":          false,
+		"192.168.0.10":      false,
+		"172.16.0.1":        false,
+		"169.254.169.254":   false,
+		"0.0.0.0":           false,
+		"::1":               false,
+		"fe80::1":           false,
+		"fd00::1":           false,
+		"::ffff:127.0.0.1":  false,
+		"":                  false,
+	}
+	for host, expected := range testCases {
+		assert.Equal(t, expected, IsPublicHost(host), host)
+	}
+}
@@ -0,0 +1,70 @@
+package worker
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"time"
+	"todoGin/repository"
+	"todoGin/storage"
+)
+
+// sweepBatch is how many orphaned attachments one sweep removes at most.
+const sweepBatch = 100
+
+// AttachmentSweeper removes the attachments of purged todos, content first,
+// so that a failed sweep leaves a record to retry rather than a lost blob.
+type AttachmentSweeper struct {
+	AttachmentRepository repository.AttachmentRepository
+	BlobStore            storage.BlobStore
+	Interval             time.Duration
+}
+
+func NewAttachmentSweeper(attachmentRepo repository.AttachmentRepository, store storage.BlobStore, interval time.Duration) *AttachmentSweeper {
+	return &AttachmentSweeper{
+		AttachmentRepository: attachmentRepo,
+		BlobStore:            store,
+		Interval:             interval,
+	}
+}
+
+// Run sweeps once immediately and then every Interval until ctx is cancelled.
+func (s *AttachmentSweeper) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.sweep(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *AttachmentSweeper) sweep(ctx context.Context) {
+	orphans, err := s.AttachmentRepository.Orphans(sweepBatch)
+	if err != nil {
+		logrus.Errorf("failed when finding orphaned attachments: %v", err)
+		return
+	}
+
+	swept := 0
+	for _, attachment := range orphans {
+		if ctx.Err() != nil {
+			return
+		}
+		if err := s.BlobStore.Delete(ctx, attachment.StorageKey); err != nil {
+			logrus.Errorf("failed when deleting content of attachment %d: %v", attachment.ID, err)
+			continue
+		}
+		if err := s.AttachmentRepository.Forget(attachment.ID); err != nil {
+			logrus.Errorf("failed when forgetting attachment %d: %v", attachment.ID, err)
+			continue
+		}
+		swept++
+	}
+	if swept > 0 {
+		logrus.Infof("swept %d attachments of purged todos", swept)
+	}
+}
@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"strings"
+	"testing"
+	"time"
+	"todoGin/mocks"
+	"todoGin/model/entity"
+	"todoGin/storage"
+)
+
+func TestAttachmentSweeperSweep(t *testing.T) {
+	testCases := []struct {
+		name       string
+		orphansErr error
+		forgetErr  error
+		kept       bool
+	}{
+		{name: "Success"},
+		{name: "Forget error", forgetErr: errors.New("some error")},
+		{name: "Repository error", orphansErr: errors.New("some error"), kept: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			store, err := storage.NewLocalStore(t.TempDir())
+			require.NoError(t, err)
+			require.NoError(t, store.Put(context.Background(), "abcdef", strings.NewReader("content")))
+
+			repo := mocks.NewAttachmentRepository(t)
+			orphans := []entity.Attachment{{ID: 3, StorageKey: "abcdef"}}
+			repo.On("Orphans", sweepBatch).Return(orphans, tc.orphansErr).Once()
+			if tc.orphansErr == nil {
+				repo.On("Forget", int64(3)).Return(tc.forgetErr).Once()
+			}
+
+			NewAttachmentSweeper(repo, store, time.Hour).sweep(context.Background())
+
+			_, err = store.Get(context.Background(), "abcdef")
+			if tc.kept {
+				assert.NoError(t, err)
+			} else {
+				assert.ErrorIs(t, err, storage.ErrNotFound)
+			}
+		})
+	}
+}
@@ -0,0 +1,133 @@
+package worker
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"time"
+	"todoGin/outbox"
+	"todoGin/repository"
+)
+
+// outboxBatch is how many pending events one query loads.
+const outboxBatch = 100
+
+// OutboxRelay publishes the events recorded in the outbox to the Sink of one
+// Channel, oldest first. An event is marked published only after the sink
+// took it, so a crash in between publishes it again: delivery is
+// at-least-once. A failed event holds back the ones after it on the channel,
+// keeping them in order, and is retried with exponential backoff from Backoff
+// up to MaxBackoff. After MaxAttempts it is given up on, marked failed, and
+// the relay moves on to the next. Other channels carry on. Events every one of
+// PruneChannels has published are pruned after Retention; a relay without
+// PruneChannels leaves pruning to another.
+type OutboxRelay struct {
+	OutboxRepository repository.OutboxRepository
+	Channel          string
+	Sink             outbox.Sink
+	Interval         time.Duration
+	Timeout          time.Duration
+	MaxAttempts      int
+	Backoff          time.Duration
+	MaxBackoff       time.Duration
+	Retention        time.Duration
+	PruneChannels    []string
+
+	failures int
+	retryAt  time.Time
+	prunedAt time.Time
+}
+
+func NewOutboxRelay(outboxRepo repository.OutboxRepository, channel string, sink outbox.Sink, interval, timeout time.Duration, maxAttempts int, backoff, maxBackoff, retention time.Duration, pruneChannels []string) *OutboxRelay {
+	return &OutboxRelay{
+		OutboxRepository: outboxRepo,
+		Channel:          channel,
+		Sink:             sink,
+		Interval:         interval,
+		Timeout:          timeout,
+		MaxAttempts:      maxAttempts,
+		Backoff:          backoff,
+		MaxBackoff:       maxBackoff,
+		Retention:        retention,
+		PruneChannels:    pruneChannels,
+	}
+}
+
+// Run relays once immediately and then every Interval until ctx is cancelled.
+func (r *OutboxRelay) Run(ctx context.Context) {
+	ticker := time.NewTicker(r.Interval)
+	defer ticker.Stop()
+
+	for {
+		r.relay(ctx)
+		r.prune()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// relay publishes pending events until none are left or one fails.
+func (r *OutboxRelay) relay(ctx context.Context) {
+	if time.Now().Before(r.retryAt) {
+		return
+	}
+	for ctx.Err() == nil {
+		events, err := r.OutboxRepository.Pending(r.Channel, outboxBatch)
+		if err != nil {
+			logrus.Errorf("failed when loading outbox events: %v", err)
+			return
+		}
+		for _, event := range events {
+			if ctx.Err() != nil {
+				return
+			}
+			publishCtx, cancel := context.WithTimeout(ctx, r.Timeout)
+			err := r.Sink.Publish(publishCtx, event)
+			cancel()
+			if err != nil {
+				failed, markErr := r.OutboxRepository.MarkFailed(r.Channel, event.ID, err.Error(), time.Now(), r.MaxAttempts)
+				if markErr != nil {
+					logrus.Errorf("failed when saving outbox event %s: %v", event.EventID, markErr)
+				}
+				if failed {
+					r.failures = 0
+					logrus.Errorf("gave up publishing outbox event %s to %s: %v", event.EventID, r.Channel, err)
+					continue
+				}
+				r.failures++
+				r.retryAt = time.Now().Add(backoff(r.Backoff, r.MaxBackoff, r.failures))
+				logrus.Errorf("failed when publishing outbox event %s to %s, retrying at %s: %v", event.EventID, r.Channel, r.retryAt.Format(time.RFC3339), err)
+				return
+			}
+			r.failures = 0
+			if err := r.OutboxRepository.MarkPublished(r.Channel, event.ID, time.Now()); err != nil {
+				// it stays pending and goes out again on the next pass
+				logrus.Errorf("failed when marking outbox event %s published: %v", event.EventID, err)
+				return
+			}
+		}
+		if len(events) < outboxBatch {
+			return
+		}
+	}
+}
+
+// prune deletes old published events, at most once per Retention / 24 so
+// that the delete does not run on every pass.
+func (r *OutboxRelay) prune() {
+	now := time.Now()
+	if r.Retention <= 0 || len(r.PruneChannels) == 0 || now.Sub(r.prunedAt) < r.Retention/24 {
+		return
+	}
+	r.prunedAt = now
+	pruned, err := r.OutboxRepository.Prune(now.Add(-r.Retention), r.PruneChannels)
+	if err != nil {
+		logrus.Errorf("failed when pruning the outbox: %v", err)
+		return
+	}
+	if pruned > 0 {
+		logrus.Infof("pruned %d published outbox events", pruned)
+	}
+}
@@ -0,0 +1,130 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+	"todoGin/mocks"
+	"todoGin/model/entity"
+	"todoGin/repository"
+)
+
+// recordingSink remembers the events it is given and fails on those in fail.
+type recordingSink struct {
+	published []string
+	fail      map[string]bool
+}
+
+func (s *recordingSink) Publish(ctx context.Context, event entity.OutboxEvent) error {
+	if s.fail[event.EventID] {
+		return errors.New("sink unavailable")
+	}
+	s.published = append(s.published, event.EventID)
+	return nil
+}
+
+func TestOutboxRelay(t *testing.T) {
+	pending := []entity.OutboxEvent{
+		{ID: 1, EventID: "a", Event: entity.EventTodoCreated},
+		{ID: 2, EventID: "b", Event: entity.EventTodoUpdated},
+		{ID: 3, EventID: "c", Event: entity.EventTodoDeleted},
+	}
+
+	t.Run("Publishes in order", func(t *testing.T) {
+		repo := mocks.NewOutboxRepository(t)
+		repo.On("Pending", repository.OutboxLocal, outboxBatch).Return(pending, nil).Once()
+		for _, event := range pending {
+			repo.On("MarkPublished", repository.OutboxLocal, event.ID, mock.AnythingOfType("time.Time")).Return(nil).Once()
+		}
+		sink := &recordingSink{}
+
+		NewOutboxRelay(repo, repository.OutboxLocal, sink, time.Second, time.Second, 5, time.Second, time.Minute, 0, nil).relay(context.Background())
+		assert.Equal(t, []string{"a", "b", "c"}, sink.published)
+	})
+
+	t.Run("Failure holds back later events", func(t *testing.T) {
+		repo := mocks.NewOutboxRepository(t)
+		repo.On("Pending", repository.OutboxLocal, outboxBatch).Return(pending, nil).Once()
+		repo.On("MarkPublished", repository.OutboxLocal, int64(1), mock.AnythingOfType("time.Time")).Return(nil).Once()
+		repo.On("MarkFailed", repository.OutboxLocal, int64(2), "sink unavailable", mock.AnythingOfType("time.Time"), 5).Return(false, nil).Once()
+		sink := &recordingSink{fail: map[string]bool{"b": true}}
+
+		relay := NewOutboxRelay(repo, repository.OutboxLocal, sink, time.Second, time.Second, 5, time.Second, time.Minute, 0, nil)
+		relay.relay(context.Background())
+		assert.Equal(t, []string{"a"}, sink.published)
+		assert.Equal(t, 1, relay.failures)
+		assert.WithinDuration(t, time.Now().Add(time.Second), relay.retryAt, time.Second)
+
+		// nothing is loaded again until the backoff has passed
+		relay.relay(context.Background())
+	})
+
+	t.Run("Gives up after the last attempt", func(t *testing.T) {
+		repo := mocks.NewOutboxRepository(t)
+		repo.On("Pending", repository.OutboxLocal, outboxBatch).Return(pending, nil).Once()
+		repo.On("MarkPublished", repository.OutboxLocal, int64(1), mock.AnythingOfType("time.Time")).Return(nil).Once()
+		repo.On("MarkFailed", repository.OutboxLocal, int64(2), "sink unavailable", mock.AnythingOfType("time.Time"), 5).Return(true, nil).Once()
+		repo.On("MarkPublished", repository.OutboxLocal, int64(3), mock.AnythingOfType("time.Time")).Return(nil).Once()
+		sink := &recordingSink{fail: map[string]bool{"b": true}}
+
+		relay := NewOutboxRelay(repo, repository.OutboxLocal, sink, time.Second, time.Second, 5, time.Second, time.Minute, 0, nil)
+		relay.relay(context.Background())
+		assert.Equal(t, []string{"a", "c"}, sink.published)
+		assert.Equal(t, 0, relay.failures)
+		assert.True(t, relay.retryAt.IsZero())
+	})
+
+	t.Run("Publishes again when marking fails", func(t *testing.T) {
+		repo := mocks.NewOutboxRepository(t)
+		repo.On("Pending", repository.OutboxLocal, outboxBatch).Return(pending[:1], nil).Twice()
+		repo.On("MarkPublished", repository.OutboxLocal, int64(1), mock.AnythingOfType("time.Time")).Return(errors.New("some error")).Once()
+		repo.On("MarkPublished", repository.OutboxLocal, int64(1), mock.AnythingOfType("time.Time")).Return(nil).Once()
+		sink := &recordingSink{}
+
+		relay := NewOutboxRelay(repo, repository.OutboxLocal, sink, time.Second, time.Second, 5, time.Second, time.Minute, 0, nil)
+		relay.relay(context.Background())
+		relay.relay(context.Background())
+		assert.Equal(t, []string{"a", "a"}, sink.published)
+	})
+}
+
+func TestOutboxRelayChannels(t *testing.T) {
+	event := entity.OutboxEvent{ID: 1, EventID: "a", Event: entity.EventTodoCreated}
+	repo := mocks.NewOutboxRepository(t)
+	repo.On("Pending", repository.OutboxExternal, outboxBatch).Return([]entity.OutboxEvent{event}, nil).Once()
+	repo.On("MarkFailed", repository.OutboxExternal, int64(1), "sink unavailable", mock.AnythingOfType("time.Time"), 5).Return(false, nil).Once()
+	repo.On("Pending", repository.OutboxLocal, outboxBatch).Return([]entity.OutboxEvent{event}, nil).Once()
+	repo.On("MarkPublished", repository.OutboxLocal, int64(1), mock.AnythingOfType("time.Time")).Return(nil).Once()
+
+	external := &recordingSink{fail: map[string]bool{"a": true}}
+	local := &recordingSink{}
+	NewOutboxRelay(repo, repository.OutboxExternal, external, time.Second, time.Second, 5, time.Second, time.Minute, 0, nil).relay(context.Background())
+	NewOutboxRelay(repo, repository.OutboxLocal, local, time.Second, time.Second, 5, time.Second, time.Minute, 0, nil).relay(context.Background())
+
+	// the external sink being down does not hold back the local one
+	assert.Empty(t, external.published)
+	assert.Equal(t, []string{"a"}, local.published)
+}
+
+func TestOutboxRelayPrune(t *testing.T) {
+	repo := mocks.NewOutboxRepository(t)
+	retention := 7 * 24 * time.Hour
+	var cutoff time.Time
+	channels := []string{repository.OutboxLocal, repository.OutboxExternal}
+	repo.On("Prune", mock.AnythingOfType("time.Time"), channels).
+		Run(func(args mock.Arguments) { cutoff = args.Get(0).(time.Time) }).
+		Return(int64(4), nil).Once()
+
+	relay := NewOutboxRelay(repo, repository.OutboxLocal, &recordingSink{}, time.Second, time.Second, 5, time.Second, time.Minute, retention, channels)
+	relay.prune()
+	assert.WithinDuration(t, time.Now().Add(-retention), cutoff, time.Minute)
+
+	// pruned again only after a while
+	relay.prune()
+
+	// a relay without channels to wait for leaves pruning to another
+	NewOutboxRelay(repo, repository.OutboxExternal, &recordingSink{}, time.Second, time.Second, 5, time.Second, time.Minute, retention, nil).prune()
+}
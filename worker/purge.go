@@ -0,0 +1,49 @@
+package worker
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"time"
+	"todoGin/repository"
+)
+
+// Purger permanently removes todos that have been in the trash longer than Retention.
+type Purger struct {
+	TodoRepository repository.TodoRepository
+	Retention      time.Duration
+	Interval       time.Duration
+}
+
+func NewPurger(todoRepo repository.TodoRepository, retention, interval time.Duration) *Purger {
+	return &Purger{
+		TodoRepository: todoRepo,
+		Retention:      retention,
+		Interval:       interval,
+	}
+}
+
+// Run purges once immediately and then every Interval until ctx is cancelled.
+func (p *Purger) Run(ctx context.Context) {
+	ticker := time.NewTicker(p.Interval)
+	defer ticker.Stop()
+
+	for {
+		p.purge()
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *Purger) purge() {
+	purged, err := p.TodoRepository.Purge(time.Now().Add(-p.Retention))
+	if err != nil {
+		logrus.Errorf("failed when purging trash: %v", err)
+		return
+	}
+	if purged > 0 {
+		logrus.Infof("purged %d todos from trash", purged)
+	}
+}
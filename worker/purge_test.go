@@ -0,0 +1,51 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+	"todoGin/mocks"
+)
+
+func TestPurgerRun(t *testing.T) {
+	testCases := []struct {
+		name     string
+		purged   int64
+		purgeErr error
+	}{
+		{name: "Success", purged: 3},
+		{name: "Repository error", purgeErr: errors.New("some error")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTodoRepository(t)
+			ctx, cancel := context.WithCancel(context.Background())
+
+			retention := 30 * 24 * time.Hour
+			var cutoff time.Time
+			repo.On("Purge", mock.AnythingOfType("time.Time")).
+				Run(func(args mock.Arguments) {
+					cutoff = args.Get(0).(time.Time)
+					cancel()
+				}).
+				Return(tc.purged, tc.purgeErr).Once()
+
+			done := make(chan struct{})
+			go func() {
+				NewPurger(repo, retention, time.Hour).Run(ctx)
+				close(done)
+			}()
+
+			select {
+			case <-done:
+			case <-time.After(time.Second):
+				t.Fatal("purger did not stop after context cancel")
+			}
+			assert.WithinDuration(t, time.Now().Add(-retention), cutoff, time.Minute)
+		})
+	}
+}
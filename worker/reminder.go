@@ -0,0 +1,87 @@
+package worker
+
+import (
+	"context"
+	"github.com/sirupsen/logrus"
+	"time"
+	"todoGin/notifier"
+	"todoGin/repository"
+)
+
+// reminderBatch is how many due todos one scan hands to the notifier.
+const reminderBatch = 100
+
+// ReminderScheduler notifies owners of open todos due within Lead, once per
+// due date. A reminder that fails to go out is retried with exponential
+// backoff, starting at Backoff and capped at MaxBackoff.
+type ReminderScheduler struct {
+	TodoRepository repository.TodoRepository
+	Notifier       notifier.Notifier
+	Lead           time.Duration
+	Interval       time.Duration
+	Timeout        time.Duration
+	Backoff        time.Duration
+	MaxBackoff     time.Duration
+}
+
+func NewReminderScheduler(todoRepo repository.TodoRepository, n notifier.Notifier, lead, interval, timeout, backoff, maxBackoff time.Duration) *ReminderScheduler {
+	return &ReminderScheduler{
+		TodoRepository: todoRepo,
+		Notifier:       n,
+		Lead:           lead,
+		Interval:       interval,
+		Timeout:        timeout,
+		Backoff:        backoff,
+		MaxBackoff:     maxBackoff,
+	}
+}
+
+// Run scans once immediately and then every Interval until ctx is cancelled.
+func (s *ReminderScheduler) Run(ctx context.Context) {
+	ticker := time.NewTicker(s.Interval)
+	defer ticker.Stop()
+
+	for {
+		s.scan(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (s *ReminderScheduler) scan(ctx context.Context) {
+	now := time.Now()
+	reminders, err := s.TodoRepository.DueSoon(now, now.Add(s.Lead), reminderBatch)
+	if err != nil {
+		logrus.Errorf("failed when scanning due todos: %v", err)
+		return
+	}
+
+	sent := 0
+	for _, reminder := range reminders {
+		if ctx.Err() != nil {
+			return
+		}
+		notifyCtx, cancel := context.WithTimeout(ctx, s.Timeout)
+		err := s.Notifier.Notify(notifyCtx, reminder)
+		cancel()
+		if err != nil {
+			retryAt := time.Now().Add(backoff(s.Backoff, s.MaxBackoff, reminder.Attempts+1))
+			logrus.Errorf("failed when sending reminder for todo %d, retrying at %s: %v", reminder.TodoID, retryAt.Format(time.RFC3339), err)
+			if err := s.TodoRepository.MarkReminderFailed(reminder.TodoID, reminder.DueDate, retryAt); err != nil {
+				logrus.Errorf("failed when saving reminder for todo %d: %v", reminder.TodoID, err)
+			}
+			continue
+		}
+		if _, err := s.TodoRepository.MarkReminded(reminder.TodoID, reminder.DueDate, time.Now()); err != nil {
+			logrus.Errorf("failed when marking todo %d reminded: %v", reminder.TodoID, err)
+			continue
+		}
+		sent++
+	}
+	if sent > 0 {
+		logrus.Infof("sent %d due-date reminders", sent)
+	}
+}
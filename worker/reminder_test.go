@@ -0,0 +1,90 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"testing"
+	"time"
+	"todoGin/mocks"
+	"todoGin/model/entity"
+	"todoGin/notifier"
+)
+
+func TestReminderSchedulerScan(t *testing.T) {
+	due := time.Date(2023, 6, 5, 9, 0, 0, 0, time.UTC)
+	reminders := []entity.Reminder{
+		{TodoID: 1, UserID: 7, Username: "raihan", Title: "deploy", DueDate: due},
+		{TodoID: 2, UserID: 7, Username: "raihan", Title: "review", DueDate: due.Add(time.Hour), Attempts: 2},
+	}
+
+	testCases := []struct {
+		name      string
+		dueErr    error
+		notifyErr error
+		delivered int
+	}{
+		{name: "Success", delivered: 2},
+		{name: "Notifier error", notifyErr: errors.New("smtp down")},
+		{name: "Repository error", dueErr: errors.New("some error")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			repo := mocks.NewTodoRepository(t)
+			fake := notifier.NewFake()
+			fake.Err = tc.notifyErr
+
+			lead := time.Hour
+			var before time.Time
+			retryAt := make(map[int64]time.Time)
+			repo.On("DueSoon", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"), reminderBatch).
+				Run(func(args mock.Arguments) { before = args.Get(1).(time.Time) }).
+				Return(reminders, tc.dueErr).Once()
+			if tc.dueErr == nil {
+				for _, reminder := range reminders {
+					if tc.notifyErr != nil {
+						repo.On("MarkReminderFailed", reminder.TodoID, reminder.DueDate, mock.AnythingOfType("time.Time")).
+							Run(func(args mock.Arguments) { retryAt[args.Get(0).(int64)] = args.Get(2).(time.Time) }).
+							Return(nil).Once()
+						continue
+					}
+					repo.On("MarkReminded", reminder.TodoID, reminder.DueDate, mock.AnythingOfType("time.Time")).
+						Return(int64(1), nil).Once()
+				}
+			}
+
+			NewReminderScheduler(repo, fake, lead, time.Minute, time.Second, time.Minute, time.Hour).scan(context.Background())
+
+			assert.WithinDuration(t, time.Now().Add(lead), before, time.Minute)
+			if tc.notifyErr != nil {
+				// the backoff doubles with every failed try
+				assert.WithinDuration(t, time.Now().Add(time.Minute), retryAt[1], 10*time.Second)
+				assert.WithinDuration(t, time.Now().Add(4*time.Minute), retryAt[2], 10*time.Second)
+			}
+			assert.Len(t, fake.Reminders(), tc.delivered)
+		})
+	}
+}
+
+func TestReminderSchedulerRun(t *testing.T) {
+	repo := mocks.NewTodoRepository(t)
+	ctx, cancel := context.WithCancel(context.Background())
+
+	repo.On("DueSoon", mock.AnythingOfType("time.Time"), mock.AnythingOfType("time.Time"), reminderBatch).
+		Run(func(args mock.Arguments) { cancel() }).
+		Return(nil, nil).Once()
+
+	done := make(chan struct{})
+	go func() {
+		NewReminderScheduler(repo, notifier.NewFake(), time.Hour, time.Hour, time.Second, time.Minute, time.Hour).Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("scheduler did not stop after context cancel")
+	}
+}
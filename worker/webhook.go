@@ -0,0 +1,121 @@
+package worker
+
+import (
+	"context"
+	"errors"
+	"github.com/sirupsen/logrus"
+	"net/http"
+	"time"
+	"todoGin/model/entity"
+	"todoGin/repository"
+	"todoGin/webhook"
+)
+
+// deliveryBatch is how many due deliveries one pass sends.
+const deliveryBatch = 50
+
+// errWebhookInactive fails deliveries queued for a webhook that has since
+// been switched off.
+var errWebhookInactive = errors.New("webhook is inactive")
+
+// WebhookDeliverer sends queued webhook deliveries. A failed attempt is
+// retried with exponential backoff, starting at Backoff and capped at
+// MaxBackoff, until MaxAttempts have been made.
+type WebhookDeliverer struct {
+	WebhookRepository repository.WebhookRepository
+	Client            *http.Client
+	Interval          time.Duration
+	MaxAttempts       int
+	Backoff           time.Duration
+	MaxBackoff        time.Duration
+}
+
+func NewWebhookDeliverer(webhookRepo repository.WebhookRepository, timeout, interval time.Duration, maxAttempts int, backoff, maxBackoff time.Duration) *WebhookDeliverer {
+	return &WebhookDeliverer{
+		WebhookRepository: webhookRepo,
+		Client:            webhook.NewClient(timeout),
+		Interval:          interval,
+		MaxAttempts:       maxAttempts,
+		Backoff:           backoff,
+		MaxBackoff:        maxBackoff,
+	}
+}
+
+// Run delivers once immediately and then every Interval until ctx is cancelled.
+func (d *WebhookDeliverer) Run(ctx context.Context) {
+	ticker := time.NewTicker(d.Interval)
+	defer ticker.Stop()
+
+	for {
+		d.deliver(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *WebhookDeliverer) deliver(ctx context.Context) {
+	deliveries, err := d.WebhookRepository.DueDeliveries(time.Now(), deliveryBatch)
+	if err != nil {
+		logrus.Errorf("failed when loading webhook deliveries: %v", err)
+		return
+	}
+	for i := range deliveries {
+		if ctx.Err() != nil {
+			return
+		}
+		d.attempt(ctx, &deliveries[i])
+		if err := d.WebhookRepository.SaveAttempt(&deliveries[i]); err != nil {
+			logrus.Errorf("failed when saving webhook delivery %d: %v", deliveries[i].ID, err)
+		}
+	}
+}
+
+// attempt sends the delivery once and records the outcome on it.
+func (d *WebhookDeliverer) attempt(ctx context.Context, delivery *entity.WebhookDelivery) {
+	now := time.Now()
+	delivery.Attempts++
+
+	var code int
+	err := errWebhookInactive
+	if delivery.Webhook != nil && delivery.Webhook.Active {
+		code, err = webhook.Send(ctx, d.Client, delivery, now)
+	}
+	if code != 0 {
+		delivery.ResponseCode = &code
+	}
+
+	if err == nil {
+		delivery.Status = entity.DeliverySucceeded
+		delivery.DeliveredAt = &now
+		delivery.NextAttemptAt = nil
+		delivery.LastError = nil
+		return
+	}
+
+	message := err.Error()
+	delivery.LastError = &message
+	if delivery.Attempts >= d.MaxAttempts || errors.Is(err, errWebhookInactive) {
+		delivery.Status = entity.DeliveryFailed
+		delivery.NextAttemptAt = nil
+		logrus.Errorf("webhook delivery %d failed for good: %v", delivery.ID, err)
+		return
+	}
+	next := now.Add(backoff(d.Backoff, d.MaxBackoff, delivery.Attempts))
+	delivery.NextAttemptAt = &next
+}
+
+// backoff is the wait after the given number of failed attempts: base,
+// doubling each time, but never more than max.
+func backoff(base, max time.Duration, attempts int) time.Duration {
+	wait := base
+	for i := 1; i < attempts; i++ {
+		wait *= 2
+		if wait >= max {
+			return max
+		}
+	}
+	return wait
+}
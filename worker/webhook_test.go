@@ -0,0 +1,113 @@
+package worker
+
+import (
+	"context"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+	"todoGin/mocks"
+	"todoGin/model/entity"
+)
+
+func TestBackoff(t *testing.T) {
+	testCases := []struct {
+		attempts int
+		expected time.Duration
+	}{
+		{attempts: 1, expected: 30 * time.Second},
+		{attempts: 2, expected: time.Minute},
+		{attempts: 4, expected: 4 * time.Minute},
+		{attempts: 8, expected: 10 * time.Minute},
+		{attempts: 60, expected: 10 * time.Minute},
+	}
+
+	for _, tc := range testCases {
+		assert.Equal(t, tc.expected, backoff(30*time.Second, 10*time.Minute, tc.attempts), "attempts %d", tc.attempts)
+	}
+}
+
+func TestWebhookDelivererAttempt(t *testing.T) {
+	status := http.StatusOK
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name           string
+		status         int
+		attempts       int
+		inactive       bool
+		expectedStatus string
+		retry          bool
+	}{
+		{name: "Delivered", status: http.StatusOK, expectedStatus: entity.DeliverySucceeded},
+		{name: "Retried", status: http.StatusServiceUnavailable, attempts: 2, expectedStatus: entity.DeliveryPending, retry: true},
+		{name: "Out of attempts", status: http.StatusServiceUnavailable, attempts: 4, expectedStatus: entity.DeliveryFailed},
+		{name: "Webhook switched off", inactive: true, expectedStatus: entity.DeliveryFailed},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			status = tc.status
+			d := NewWebhookDeliverer(mocks.NewWebhookRepository(t), time.Second, time.Minute, 5, time.Minute, time.Hour)
+			// the test server listens on loopback, which the deliverer refuses
+			d.Client = server.Client()
+			delivery := &entity.WebhookDelivery{
+				ID:       1,
+				Event:    entity.EventTodoDeleted,
+				Payload:  `{}`,
+				Status:   entity.DeliveryPending,
+				Attempts: tc.attempts,
+				Webhook:  &entity.Webhook{URL: server.URL, Secret: "secret", Active: !tc.inactive},
+			}
+
+			d.attempt(context.Background(), delivery)
+
+			assert.Equal(t, tc.attempts+1, delivery.Attempts)
+			assert.Equal(t, tc.expectedStatus, delivery.Status)
+			if tc.retry {
+				assert.WithinDuration(t, time.Now().Add(4*time.Minute), *delivery.NextAttemptAt, time.Minute)
+			} else {
+				assert.Nil(t, delivery.NextAttemptAt)
+			}
+			if tc.expectedStatus == entity.DeliverySucceeded {
+				assert.NotNil(t, delivery.DeliveredAt)
+				assert.Nil(t, delivery.LastError)
+			} else {
+				assert.NotNil(t, delivery.LastError)
+			}
+		})
+	}
+}
+
+func TestWebhookDelivererRun(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+
+	repo := mocks.NewWebhookRepository(t)
+	ctx, cancel := context.WithCancel(context.Background())
+	repo.On("DueDeliveries", mock.AnythingOfType("time.Time"), deliveryBatch).Return([]entity.WebhookDelivery{
+		{ID: 1, Payload: `{}`, Webhook: &entity.Webhook{URL: server.URL, Active: true}},
+	}, nil).Once()
+	repo.On("SaveAttempt", mock.MatchedBy(func(delivery *entity.WebhookDelivery) bool {
+		return delivery.ID == 1 && delivery.Status == entity.DeliverySucceeded
+	})).Run(func(args mock.Arguments) { cancel() }).Return(nil).Once()
+
+	done := make(chan struct{})
+	go func() {
+		d := NewWebhookDeliverer(repo, time.Second, time.Hour, 8, time.Minute, time.Hour)
+		d.Client = server.Client()
+		d.Run(ctx)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("deliverer did not stop after context cancel")
+	}
+}